@@ -1,14 +1,36 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/dsypasit/social-clone/server/config"
+	"github.com/dsypasit/social-clone/server/internal/admin"
 	"github.com/dsypasit/social-clone/server/internal/auth"
+	"github.com/dsypasit/social-clone/server/internal/block"
+	"github.com/dsypasit/social-clone/server/internal/bookmark"
+	"github.com/dsypasit/social-clone/server/internal/comment"
+	"github.com/dsypasit/social-clone/server/internal/filter"
+	"github.com/dsypasit/social-clone/server/internal/follow"
+	"github.com/dsypasit/social-clone/server/internal/health"
+	"github.com/dsypasit/social-clone/server/internal/linkpreview"
+	"github.com/dsypasit/social-clone/server/internal/message"
+	"github.com/dsypasit/social-clone/server/internal/middleware"
+	"github.com/dsypasit/social-clone/server/internal/notification"
 	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/ratelimit"
+	"github.com/dsypasit/social-clone/server/internal/reaction"
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/dsypasit/social-clone/server/internal/report"
+	"github.com/dsypasit/social-clone/server/internal/search"
 	"github.com/dsypasit/social-clone/server/internal/share/db"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/dsypasit/social-clone/server/internal/user"
 	"github.com/dsypasit/social-clone/server/pkg"
 	"github.com/dsypasit/social-clone/server/pkg/logger"
@@ -26,24 +48,134 @@ func main() {
 	}
 	defer db.Close()
 
+	// workers run on ctx, which is cancelled once the server has drained
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	workers := health.NewWorkers()
+
+	hub := realtime.NewHub(cfg.Realtime.BufferSize)
+	broker := realtime.NewBroker(db.DB, hub)
+	workers.Go("realtime", func() error {
+		err := broker.Listen(ctx, cfg.DBConnection)
+		if err != nil {
+			log.Println("realtime listener stopped:", err)
+		}
+		return err
+	})
+
 	usrRepo := user.NewUserRepository(db.DB)
 	postRepo := post.NewPostRepository(db.DB)
+	notificationRepo := notification.NewNotificationRepository(db.DB)
+	adminRepo := admin.NewAdminRepository(db.DB)
+	reportRepo := report.NewReportRepository(db.DB)
+	blockRepo := block.NewBlockRepository(db.DB)
+	followRepo := follow.NewFollowRepository(db.DB)
+	commentRepo := comment.NewCommentRepository(db.DB)
+	searchRepo := search.NewSearchRepository(db.DB)
+	messageRepo := message.NewMessageRepository(db.DB)
+	reactionRepo := reaction.NewReactionRepository(db.DB)
+	bookmarkRepo := bookmark.NewBookmarkRepository(db.DB)
+	linkPreviewRepo := linkpreview.NewLinkPreviewRepository(db.DB)
+	filterRepo := filter.NewFilterRepository(db.DB)
 
 	usrSrv := user.NewUserService(usrRepo)
 	jwtSrv := auth.NewJwtService("test")
 	authSrv := auth.NewAuthService(usrSrv, jwtSrv)
-	postSrv := post.NewPostService(postRepo, usrSrv)
+	notificationSrv := notification.NewNotificationService(notificationRepo, broker)
+	previewTimeout := time.Duration(cfg.LinkPreview.TimeoutSeconds) * time.Second
+	previewSrv := linkpreview.NewLinkPreviewService(linkPreviewRepo,
+		linkpreview.NewFetcher(previewTimeout, int64(cfg.LinkPreview.MaxBytes)),
+		cfg.LinkPreview.Workers, cfg.LinkPreview.QueueSize, previewTimeout)
+	workers.Go("linkpreview", func() error {
+		previewSrv.Run(ctx)
+		return nil
+	})
+
+	adminSrv := admin.NewAdminService(adminRepo)
+	reportSrv := report.NewReportService(reportRepo, adminSrv, cfg.Moderation.ReportThreshold)
+	filterSrv := filter.NewFilterService(reportSrv,
+		filter.NewBlocklist(cfg.Filter.BlockedWords, filter.Reject),
+		filter.NewBlocklist(cfg.Filter.FlaggedWords, filter.Flag),
+		filter.NewLinkSpam(cfg.Filter.FlagLinks, cfg.Filter.MaxLinks),
+		filter.NewDuplicate(filterRepo, time.Duration(cfg.Filter.DuplicateWindowMinutes)*time.Minute),
+		filter.NewRate(filterRepo, map[string]int{
+			filter.TargetPost:    cfg.Filter.PostsPerMinute,
+			filter.TargetComment: cfg.Filter.CommentsPerMinute,
+		}),
+	)
+
+	postSrv := post.NewPostService(postRepo, usrSrv, notificationSrv, broker, previewSrv, filterSrv,
+		time.Duration(cfg.Post.EditWindowMinutes)*time.Minute,
+		util.TextRule{MaxLength: cfg.Content.PostMaxLength, Multiline: true})
+	blockSrv := block.NewBlockService(blockRepo, usrSrv)
+	followSrv := follow.NewFollowService(followRepo, usrSrv, blockSrv, notificationSrv)
+	commentSrv := comment.NewCommentService(commentRepo, blockSrv, usrSrv, notificationSrv, filterSrv,
+		util.TextRule{MaxLength: cfg.Content.CommentMaxLength, Multiline: true})
+	searchSrv := search.NewSearchService(searchRepo)
+	messageSrv := message.NewMessageService(messageRepo, usrSrv, blockSrv, broker)
+	reactionSrv := reaction.NewReactionService(reactionRepo, blockSrv, notificationSrv, broker, cfg.Reaction.Types)
+	bookmarkSrv := bookmark.NewBookmarkService(bookmarkRepo)
+
+	scheduler := post.NewScheduler(postSrv, time.Duration(cfg.Scheduler.IntervalSeconds)*time.Second, cfg.Scheduler.BatchSize)
+	workers.Go("scheduler", func() error {
+		scheduler.Run(ctx)
+		return nil
+	})
+	healthSrv := health.NewHealthService(health.NewHealthRepository(db.DB), workers,
+		time.Duration(cfg.Health.CheckTimeoutMillis)*time.Millisecond)
 
 	usrHandler := user.NewUserHandler(usrSrv)
-	authHandler := auth.NewAuthHandler(authSrv)
+	authHandler := auth.NewAuthHandler(authSrv, auth.SessionOptions{
+		Secure:   cfg.Session.CookieSecure,
+		SameSite: auth.ParseSameSite(cfg.Session.CookieSameSite),
+		Domain:   cfg.Session.CookieDomain,
+		MaxAge:   jwtSrv.ExpiresDuration(),
+	})
 	postHandler := post.NewPostHandler(postSrv)
+	adminHandler := admin.NewAdminHandler(adminSrv)
+	reportHandler := report.NewReportHandler(reportSrv)
+	blockHandler := block.NewBlockHandler(blockSrv)
+	followHandler := follow.NewFollowHandler(followSrv)
+	commentHandler := comment.NewCommentHandler(commentSrv)
+	searchHandler := search.NewSearchHandler(searchSrv)
+	notificationHandler := notification.NewNotificationHandler(notificationSrv)
+	messageHandler := message.NewMessageHandler(messageSrv)
+	reactionHandler := reaction.NewReactionHandler(reactionSrv)
+	bookmarkHandler := bookmark.NewBookmarkHandler(bookmarkSrv)
+	realtimeHandler := realtime.NewRealtimeHandler(hub, time.Duration(cfg.Realtime.HeartbeatSeconds)*time.Second)
+
+	authMiddleware := middleware.AuthMiddleware(jwtSrv, usrSrv)
+
+	var rateLimitStore ratelimit.IStore = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "postgres" {
+		rateLimitStore = ratelimit.NewRateLimitRepository(db.DB)
+	}
+	rateLimitRoutes := map[string]ratelimit.Limit{}
+	for route, rule := range cfg.RateLimit.Routes {
+		rateLimitRoutes[route] = ratelimit.Limit(rule)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, jwtSrv, ratelimit.Limit(cfg.RateLimit.Default),
+		rateLimitRoutes, cfg.RateLimit.TrustProxy)
 
-	router := mux.NewRouter()
-	router = router.PathPrefix("/api/v1").Subrouter()
+	rootRouter := mux.NewRouter()
+	health.RegisterHealthRouter(rootRouter, health.NewHealthHandler(healthSrv))
+	router := rootRouter.PathPrefix("/api/v1").Subrouter()
+	router.Use(limiter.Middleware)
 
-	user.RegisterUserRouter(router, usrHandler)
+	user.RegisterUserRouter(router, usrHandler, authMiddleware)
 	auth.RegisterAuthRouter(router, authHandler)
-	post.RegisterPostRouter(router, postHandler, jwtSrv)
+	post.RegisterPostRouter(router, postHandler, authMiddleware)
+	admin.RegisterAdminRouter(router, adminHandler, authMiddleware)
+	report.RegisterReportRouter(router, reportHandler, authMiddleware)
+	block.RegisterBlockRouter(router, blockHandler, authMiddleware)
+	follow.RegisterFollowRouter(router, followHandler, authMiddleware)
+	comment.RegisterCommentRouter(router, commentHandler, authMiddleware)
+	search.RegisterSearchRouter(router, searchHandler, authMiddleware)
+	notification.RegisterNotificationRouter(router, notificationHandler, authMiddleware)
+	realtime.RegisterRealtimeRouter(router, realtimeHandler, authMiddleware)
+	message.RegisterMessageRouter(router, messageHandler, authMiddleware)
+	reaction.RegisterReactionRouter(router, reactionHandler, authMiddleware)
+	bookmark.RegisterBookmarkRouter(router, bookmarkHandler, authMiddleware)
 
 	router.HandleFunc("/healtcheck", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -57,7 +189,56 @@ func main() {
 	zlog, _ := zlogConfig.Build()
 	defer zlog.Sync()
 	logMiddleware := logger.Middleware(zlog)
+	corsOpts := pkg.CorsOptions(cfg.Cors)
 
-	fmt.Printf("Running server with port %d\n", cfg.Server.Port)
-	http.ListenAndServe(fmt.Sprintf(":%v", cfg.Server.Port), logMiddleware(pkg.CorsMiddleware(router)))
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%v", cfg.Server.Port),
+		Handler:           logMiddleware(pkg.CorsMiddleware(corsOpts, rootRouter)),
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeoutSeconds) * time.Second,
+	}
+	// Shutdown does not wait out hijacked or streaming connections on its own,
+	// closing the hub ends the realtime streams
+	server.RegisterOnShutdown(hub.Close)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("Running server with port %d\n", cfg.Server.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		zlog.Error("server stopped", zap.Error(err))
+	case sig := <-signals:
+		zlog.Info("shutting down", zap.String("signal", sig.String()))
+	}
+	signal.Stop(signals)
+
+	healthSrv.SetDraining()
+	time.Sleep(time.Duration(cfg.Health.DrainDelaySeconds) * time.Second)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(),
+		time.Duration(cfg.Server.ShutdownTimeoutSeconds)*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		zlog.Error("failed to drain requests", zap.Error(err))
+		server.Close()
+	}
+
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		zlog.Error("background workers did not stop in time")
+	}
 }
@@ -94,6 +94,35 @@ func TestBoolEnvironment(t *testing.T) {
 	}
 }
 
+var defaultServer = Server{
+	Port: 1323, ReadTimeoutSeconds: 15, ReadHeaderTimeoutSeconds: 5,
+	WriteTimeoutSeconds: 30, IdleTimeoutSeconds: 120, ShutdownTimeoutSeconds: 20,
+}
+
+var defaultRateLimit = RateLimit{
+	Store:   "memory",
+	Default: RateLimitRule{PerMinute: 120, Burst: 60},
+	Routes: map[string]RateLimitRule{
+		"POST /api/v1/auth/login":  {PerMinute: 10, Burst: 5},
+		"POST /api/v1/auth/signup": {PerMinute: 5, Burst: 3},
+		"POST /api/v1/post":        {PerMinute: 30, Burst: 10},
+		"POST /api/v1/comment":     {PerMinute: 60, Burst: 20},
+	},
+}
+
+var defaultCors = Cors{
+	AllowedOrigins:   []string{"http://localhost:3000"},
+	AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE"},
+	AllowedHeaders:   []string{"Content-Type", "Authorization", "X-CSRF-Token"},
+	ExposedHeaders:   []string{"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+	AllowCredentials: true,
+	MaxAgeSeconds:    600,
+}
+
+var defaultSession = Session{CookieSecure: true, CookieSameSite: "lax"}
+
+var defaultHealth = Health{CheckTimeoutMillis: 1000}
+
 func TestGetAllConfig(t *testing.T) {
 	cfg := New()
 	tests := []struct {
@@ -105,40 +134,404 @@ func TestGetAllConfig(t *testing.T) {
 			"no config env should return default config",
 			map[string]string{cHostname: "", cPort: "", cDBConnection: ""},
 			Config{
-				Server:       Server{Port: 1323},
+				Server:       defaultServer,
 				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
 			},
 		},
 		{
 			"config hostname env should return as changed",
 			map[string]string{cHostname: "test-hostname"},
 			Config{
-				Server:       Server{Hostname: "test-hostname", Port: 1323},
+				Server: Server{
+					Hostname: "test-hostname", Port: 1323,
+					ReadTimeoutSeconds: 15, ReadHeaderTimeoutSeconds: 5,
+					WriteTimeoutSeconds: 30, IdleTimeoutSeconds: 120, ShutdownTimeoutSeconds: 20,
+				},
 				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
 			},
 		},
 		{
 			"config port env should return as changed",
 			map[string]string{cPort: "4444"},
 			Config{
-				Server:       Server{Port: 4444},
+				Server: Server{
+					Port:               4444,
+					ReadTimeoutSeconds: 15, ReadHeaderTimeoutSeconds: 5,
+					WriteTimeoutSeconds: 30, IdleTimeoutSeconds: 120, ShutdownTimeoutSeconds: 20,
+				},
 				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
 			},
 		},
 		{
 			"config DB_CONNECTION env should return as changed",
 			map[string]string{cDBConnection: "test-db-connection"},
 			Config{
-				Server:       Server{Port: 1323},
+				Server:       defaultServer,
 				DBConnection: "test-db-connection",
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
 			},
 		},
 		{
 			"config all env should return as changed",
 			map[string]string{cHostname: "test-hostname", cPort: "4444", cDBConnection: "test-db-connection"},
 			Config{
-				Server:       Server{Hostname: "test-hostname", Port: 4444},
+				Server: Server{
+					Hostname: "test-hostname", Port: 4444,
+					ReadTimeoutSeconds: 15, ReadHeaderTimeoutSeconds: 5,
+					WriteTimeoutSeconds: 30, IdleTimeoutSeconds: 120, ShutdownTimeoutSeconds: 20,
+				},
 				DBConnection: "test-db-connection",
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config realtime env should return as changed",
+			map[string]string{cRealtimeBufferSize: "16", cRealtimeHeartbeat: "5"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 16, HeartbeatSeconds: 5},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config REACTION_TYPES env should return as changed",
+			map[string]string{cReactionTypes: "like, fire,,clap"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "fire", "clap"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config SCHEDULER env should return as changed",
+			map[string]string{cSchedulerInterval: "5", cSchedulerBatchSize: "10"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 5, BatchSize: 10},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config POST_EDIT_WINDOW_MINUTES env should return as changed",
+			map[string]string{cPostEditWindow: "15"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 15},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config LINK_PREVIEW env should return as changed",
+			map[string]string{cLinkPreviewTimeout: "2", cLinkPreviewMaxBytes: "65536", cLinkPreviewWorkers: "4", cLinkPreviewQueueSize: "10"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 2, MaxBytes: 65536, Workers: 4, QueueSize: 10},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config CONTENT env should return as changed",
+			map[string]string{cPostMaxLength: "280", cCommentMaxLength: "140"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 280, CommentMaxLength: 140},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config FILTER env should return as changed",
+			map[string]string{
+				cFilterBlockedWords: "scam, buy now", cFilterFlaggedWords: "crypto", cFilterFlagLinks: "1", cFilterMaxLinks: "3",
+				cFilterDuplicateWindow: "0", cFilterPostsPerMinute: "2", cFilterCommentsPerMinute: "4",
+			},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter: Filter{
+					BlockedWords: []string{"scam", "buy now"}, FlaggedWords: []string{"crypto"}, FlagLinks: 1, MaxLinks: 3,
+					DuplicateWindowMinutes: 0, PostsPerMinute: 2, CommentsPerMinute: 4,
+				},
+				RateLimit: defaultRateLimit,
+				Cors:      defaultCors,
+				Session:   defaultSession,
+				Health:    defaultHealth,
+			},
+		},
+		{
+			"config RATE_LIMIT env should return as changed",
+			map[string]string{
+				cRateLimitStore: "postgres", cRateLimitTrustProxy: "true", cRateLimitPerMinute: "600", cRateLimitBurst: "100",
+				cRateLimitRoutes: "POST  /api/v1/post=5:2, GET /api/v1/search=bad, broken, PUT /api/v1/post/{uuid}=10:4",
+			},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit: RateLimit{
+					Store:      "postgres",
+					TrustProxy: true,
+					Default:    RateLimitRule{PerMinute: 600, Burst: 100},
+					Routes: map[string]RateLimitRule{
+						"POST /api/v1/post":       {PerMinute: 5, Burst: 2},
+						"PUT /api/v1/post/{uuid}": {PerMinute: 10, Burst: 4},
+					},
+				},
+				Cors:    defaultCors,
+				Session: defaultSession,
+				Health:  defaultHealth,
+			},
+		},
+		{
+			"config CORS env should return as changed",
+			map[string]string{
+				cCorsAllowedOrigins: "https://example.com, https://*.example.com", cCorsAllowedMethods: "GET,POST",
+				cCorsAllowedHeaders: "Content-Type", cCorsExposedHeaders: " ", cCorsAllowCredentials: "true", cCorsMaxAge: "60",
+			},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors: Cors{
+					AllowedOrigins:   []string{"https://example.com", "https://*.example.com"},
+					AllowedMethods:   []string{"GET", "POST"},
+					AllowedHeaders:   []string{"Content-Type"},
+					AllowCredentials: true,
+					MaxAgeSeconds:    60,
+				},
+				Session: defaultSession,
+				Health:  defaultHealth,
+			},
+		},
+		{
+			"config SESSION env should return as changed",
+			map[string]string{cSessionCookieSecure: "false", cSessionCookieSameSite: "strict", cSessionCookieDomain: "example.com"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      Session{CookieDomain: "example.com", CookieSameSite: "strict"},
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config SERVER timeout env should return as changed",
+			map[string]string{
+				cServerReadTimeout: "1", cServerReadHeaderTimeout: "2", cServerWriteTimeout: "3",
+				cServerIdleTimeout: "4", cServerShutdownTimeout: "5",
+			},
+			Config{
+				Server: Server{
+					Port:               1323,
+					ReadTimeoutSeconds: 1, ReadHeaderTimeoutSeconds: 2, WriteTimeoutSeconds: 3, IdleTimeoutSeconds: 4, ShutdownTimeoutSeconds: 5,
+				},
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
+			},
+		},
+		{
+			"config HEALTH env should return as changed",
+			map[string]string{cHealthCheckTimeout: "250", cHealthDrainDelay: "5"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 5},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       Health{CheckTimeoutMillis: 250, DrainDelaySeconds: 5},
+			},
+		},
+		{
+			"config REPORT_THRESHOLD env should return as changed",
+			map[string]string{cReportThreshold: "3"},
+			Config{
+				Server:       defaultServer,
+				DBConnection: dDBConnection,
+				Moderation:   Moderation{ReportThreshold: 3},
+				Realtime:     Realtime{BufferSize: 64, HeartbeatSeconds: 25},
+				Reaction:     Reaction{Types: []string{"like", "love", "haha", "wow", "sad", "angry"}},
+				Scheduler:    Scheduler{IntervalSeconds: 30, BatchSize: 100},
+				Post:         Post{EditWindowMinutes: 60},
+				LinkPreview:  LinkPreview{TimeoutSeconds: 5, MaxBytes: 1 << 20, Workers: 2, QueueSize: 100},
+				Content:      Content{PostMaxLength: 500, CommentMaxLength: 300},
+				Filter:       Filter{FlagLinks: 2, MaxLinks: 5, DuplicateWindowMinutes: 10, PostsPerMinute: 5, CommentsPerMinute: 10},
+				RateLimit:    defaultRateLimit,
+				Cors:         defaultCors,
+				Session:      defaultSession,
+				Health:       defaultHealth,
 			},
 		},
 	}
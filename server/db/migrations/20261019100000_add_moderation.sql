@@ -0,0 +1,72 @@
+-- migrate:up
+ALTER TABLE app_user
+  ADD COLUMN role varchar(20) NOT NULL DEFAULT 'user';
+
+ALTER TABLE app_user
+  ADD COLUMN suspended_at timestamp;
+
+ALTER TABLE app_user
+  ADD COLUMN suspended_reason text;
+
+ALTER TABLE post
+  ADD COLUMN hidden_at timestamp;
+
+ALTER TABLE post
+  ADD COLUMN hidden_reason text;
+
+ALTER TABLE comment
+  ADD COLUMN hidden_at timestamp;
+
+ALTER TABLE comment
+  ADD COLUMN hidden_reason text;
+
+CREATE TABLE IF NOT EXISTS moderation_log (
+  id SERIAL PRIMARY KEY,
+  uuid uuid UNIQUE,
+  moderator_id int, -- NULL when the action was taken by the system
+  action varchar(50) NOT NULL,
+  target_type varchar(20) NOT NULL,
+  target_uuid uuid NOT NULL,
+  reason text,
+  created_at timestamp DEFAULT current_timestamp,
+
+  FOREIGN KEY(moderator_id) REFERENCES app_user(id)
+);
+
+CREATE INDEX moderation_log_target_idx ON moderation_log (target_type, target_uuid);
+
+CREATE FUNCTION moderation_log_immutable() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'moderation_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER moderation_log_immutable
+  BEFORE UPDATE OR DELETE ON moderation_log
+  FOR EACH ROW EXECUTE FUNCTION moderation_log_immutable();
+
+-- migrate:down
+DROP TRIGGER IF EXISTS moderation_log_immutable ON moderation_log;
+DROP FUNCTION IF EXISTS moderation_log_immutable();
+DROP TABLE IF EXISTS moderation_log;
+
+ALTER TABLE comment
+  DROP COLUMN hidden_reason;
+
+ALTER TABLE comment
+  DROP COLUMN hidden_at;
+
+ALTER TABLE post
+  DROP COLUMN hidden_reason;
+
+ALTER TABLE post
+  DROP COLUMN hidden_at;
+
+ALTER TABLE app_user
+  DROP COLUMN suspended_reason;
+
+ALTER TABLE app_user
+  DROP COLUMN suspended_at;
+
+ALTER TABLE app_user
+  DROP COLUMN role;
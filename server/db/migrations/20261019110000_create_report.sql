@@ -0,0 +1,23 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS report (
+  id SERIAL PRIMARY KEY,
+  uuid uuid UNIQUE,
+  reporter_id int NOT NULL,
+  target_type varchar(20) NOT NULL,
+  target_uuid uuid NOT NULL,
+  category varchar(30) NOT NULL,
+  detail text,
+  status varchar(20) NOT NULL DEFAULT 'open',
+  resolved_by int,
+  resolved_at timestamp,
+  created_at timestamp DEFAULT current_timestamp,
+
+  UNIQUE(reporter_id, target_type, target_uuid),
+  FOREIGN KEY(reporter_id) REFERENCES app_user(id),
+  FOREIGN KEY(resolved_by) REFERENCES app_user(id)
+);
+
+CREATE INDEX report_queue_idx ON report (status, target_type, target_uuid);
+
+-- migrate:down
+DROP TABLE IF EXISTS report;
@@ -0,0 +1,34 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS blocks (
+  id SERIAL PRIMARY KEY,
+  blocker_id int NOT NULL,
+  blocked_id int NOT NULL,
+  created_at timestamp DEFAULT current_timestamp,
+
+  UNIQUE(blocker_id, blocked_id),
+  FOREIGN KEY(blocker_id) REFERENCES app_user(id),
+  FOREIGN KEY(blocked_id) REFERENCES app_user(id)
+);
+
+CREATE TABLE IF NOT EXISTS mutes (
+  id SERIAL PRIMARY KEY,
+  muter_id int NOT NULL,
+  muted_id int NOT NULL,
+  created_at timestamp DEFAULT current_timestamp,
+
+  UNIQUE(muter_id, muted_id),
+  FOREIGN KEY(muter_id) REFERENCES app_user(id),
+  FOREIGN KEY(muted_id) REFERENCES app_user(id)
+);
+
+CREATE INDEX blocks_blocked_id_idx ON blocks (blocked_id);
+
+DELETE FROM follows AS a USING follows AS b
+WHERE a.id > b.id AND a.follower_id = b.follower_id AND a.followed_id = b.followed_id;
+
+ALTER TABLE follows ADD CONSTRAINT follows_follower_id_followed_id_key UNIQUE (follower_id, followed_id);
+
+-- migrate:down
+ALTER TABLE follows DROP CONSTRAINT IF EXISTS follows_follower_id_followed_id_key;
+DROP TABLE IF EXISTS mutes;
+DROP TABLE IF EXISTS blocks;
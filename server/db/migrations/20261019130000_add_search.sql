@@ -0,0 +1,18 @@
+-- migrate:up
+INSERT INTO visibility_type (id, name) VALUES (1, 'public'), (2, 'followers'), (3, 'private')
+ON CONFLICT (id) DO NOTHING;
+SELECT setval('visibility_type_id_seq', GREATEST((SELECT max(id) FROM visibility_type), 1));
+
+ALTER TABLE post ADD COLUMN content_tsv tsvector
+  GENERATED ALWAYS AS (to_tsvector('english', coalesce(content, ''))) STORED;
+ALTER TABLE app_user ADD COLUMN username_tsv tsvector
+  GENERATED ALWAYS AS (to_tsvector('simple', username)) STORED;
+
+CREATE INDEX post_content_tsv_idx ON post USING gin (content_tsv);
+CREATE INDEX app_user_username_tsv_idx ON app_user USING gin (username_tsv);
+
+-- migrate:down
+DROP INDEX IF EXISTS app_user_username_tsv_idx;
+DROP INDEX IF EXISTS post_content_tsv_idx;
+ALTER TABLE app_user DROP COLUMN IF EXISTS username_tsv;
+ALTER TABLE post DROP COLUMN IF EXISTS content_tsv;
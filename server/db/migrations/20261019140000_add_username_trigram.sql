@@ -0,0 +1,7 @@
+-- migrate:up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX app_user_username_trgm_idx ON app_user USING gin (username gin_trgm_ops);
+
+-- migrate:down
+DROP INDEX IF EXISTS app_user_username_trgm_idx;
@@ -0,0 +1,23 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS hashtag (
+  id SERIAL PRIMARY KEY,
+  name varchar(50) NOT NULL UNIQUE,
+  created_at timestamp DEFAULT current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS post_hashtag (
+  post_id int NOT NULL,
+  hashtag_id int NOT NULL,
+  created_at timestamp NOT NULL DEFAULT current_timestamp,
+
+  PRIMARY KEY(post_id, hashtag_id),
+  FOREIGN KEY(post_id) REFERENCES post(id) ON DELETE CASCADE,
+  FOREIGN KEY(hashtag_id) REFERENCES hashtag(id) ON DELETE CASCADE
+);
+
+CREATE INDEX post_hashtag_timeline_idx ON post_hashtag (hashtag_id, post_id DESC);
+CREATE INDEX post_hashtag_created_at_idx ON post_hashtag (created_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS post_hashtag;
+DROP TABLE IF EXISTS hashtag;
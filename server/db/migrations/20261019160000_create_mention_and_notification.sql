@@ -0,0 +1,47 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS post_mention (
+  id SERIAL PRIMARY KEY,
+  post_id int NOT NULL,
+  user_id int NOT NULL,
+  start_index int NOT NULL,
+  length int NOT NULL,
+
+  FOREIGN KEY(post_id) REFERENCES post(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES app_user(id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_mention (
+  id SERIAL PRIMARY KEY,
+  comment_id int NOT NULL,
+  user_id int NOT NULL,
+  start_index int NOT NULL,
+  length int NOT NULL,
+
+  FOREIGN KEY(comment_id) REFERENCES comment(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES app_user(id)
+);
+
+CREATE INDEX post_mention_post_id_idx ON post_mention (post_id);
+CREATE INDEX comment_mention_comment_id_idx ON comment_mention (comment_id);
+
+CREATE TABLE IF NOT EXISTS notification (
+  id SERIAL PRIMARY KEY,
+  uuid uuid UNIQUE,
+  recipient_id int NOT NULL,
+  actor_id int NOT NULL,
+  type varchar(20) NOT NULL,
+  target_type varchar(20) NOT NULL,
+  target_uuid uuid NOT NULL,
+  read_at timestamp,
+  created_at timestamp DEFAULT current_timestamp,
+
+  FOREIGN KEY(recipient_id) REFERENCES app_user(id),
+  FOREIGN KEY(actor_id) REFERENCES app_user(id)
+);
+
+CREATE INDEX notification_recipient_idx ON notification (recipient_id, id DESC);
+
+-- migrate:down
+DROP TABLE IF EXISTS notification;
+DROP TABLE IF EXISTS comment_mention;
+DROP TABLE IF EXISTS post_mention;
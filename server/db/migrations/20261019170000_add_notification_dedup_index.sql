@@ -0,0 +1,12 @@
+-- migrate:up
+DELETE FROM notification AS a
+USING notification AS b
+WHERE a.read_at IS NULL AND b.read_at IS NULL
+AND a.recipient_id = b.recipient_id AND a.actor_id = b.actor_id
+AND a.type = b.type AND a.target_uuid = b.target_uuid
+AND a.id < b.id;
+
+CREATE UNIQUE INDEX notification_unread_event_idx ON notification (recipient_id, actor_id, type, target_uuid) WHERE read_at IS NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS notification_unread_event_idx;
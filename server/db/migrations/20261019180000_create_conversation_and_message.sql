@@ -0,0 +1,45 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS conversation (
+  id SERIAL PRIMARY KEY,
+  uuid uuid NOT NULL UNIQUE,
+  is_group boolean NOT NULL DEFAULT false,
+  title varchar(100),
+  created_by int NOT NULL,
+  created_at timestamp NOT NULL DEFAULT current_timestamp,
+  updated_at timestamp NOT NULL DEFAULT current_timestamp,
+
+  FOREIGN KEY(created_by) REFERENCES app_user(id)
+);
+
+CREATE TABLE IF NOT EXISTS message (
+  id SERIAL PRIMARY KEY,
+  uuid uuid NOT NULL UNIQUE,
+  conversation_id int NOT NULL,
+  sender_id int NOT NULL,
+  content text NOT NULL,
+  created_at timestamp NOT NULL DEFAULT current_timestamp,
+
+  FOREIGN KEY(conversation_id) REFERENCES conversation(id) ON DELETE CASCADE,
+  FOREIGN KEY(sender_id) REFERENCES app_user(id)
+);
+
+CREATE TABLE IF NOT EXISTS conversation_member (
+  conversation_id int NOT NULL,
+  user_id int NOT NULL,
+  joined_at timestamp NOT NULL DEFAULT current_timestamp,
+  last_read_message_id int,
+  last_read_at timestamp,
+
+  PRIMARY KEY(conversation_id, user_id),
+  FOREIGN KEY(conversation_id) REFERENCES conversation(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES app_user(id),
+  FOREIGN KEY(last_read_message_id) REFERENCES message(id) ON DELETE SET NULL
+);
+
+CREATE INDEX conversation_member_user_idx ON conversation_member (user_id);
+CREATE INDEX message_conversation_idx ON message (conversation_id, id DESC);
+
+-- migrate:down
+DROP TABLE IF EXISTS conversation_member;
+DROP TABLE IF EXISTS message;
+DROP TABLE IF EXISTS conversation;
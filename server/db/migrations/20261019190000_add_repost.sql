@@ -0,0 +1,15 @@
+-- migrate:up
+ALTER TABLE post ADD COLUMN original_post_id integer REFERENCES post(id);
+ALTER TABLE post ADD COLUMN is_quote boolean NOT NULL DEFAULT false;
+
+CREATE INDEX post_original_post_idx ON post (original_post_id) WHERE original_post_id IS NOT NULL;
+
+-- a user reposts a post at most once, quotes are unlimited
+CREATE UNIQUE INDEX post_repost_unique_idx ON post (app_user_id, original_post_id)
+  WHERE NOT is_quote AND deleted_at IS NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS post_repost_unique_idx;
+DROP INDEX IF EXISTS post_original_post_idx;
+ALTER TABLE post DROP COLUMN IF EXISTS is_quote;
+ALTER TABLE post DROP COLUMN IF EXISTS original_post_id;
@@ -0,0 +1,8 @@
+-- migrate:up
+ALTER TABLE comment ADD COLUMN parent_id integer REFERENCES comment(id);
+
+CREATE INDEX comment_parent_idx ON comment (parent_id, id) WHERE parent_id IS NOT NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS comment_parent_idx;
+ALTER TABLE comment DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,20 @@
+-- migrate:up
+-- type is not constrained here, the accepted set comes from REACTION_TYPES
+CREATE TABLE reaction (
+  id serial PRIMARY KEY,
+  app_user_id integer NOT NULL REFERENCES app_user(id),
+  post_id integer REFERENCES post(id) ON DELETE CASCADE,
+  comment_id integer REFERENCES comment(id) ON DELETE CASCADE,
+  type varchar(20) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT reaction_one_target CHECK ((post_id IS NULL) <> (comment_id IS NULL))
+);
+
+CREATE UNIQUE INDEX reaction_user_post_idx ON reaction (app_user_id, post_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX reaction_user_comment_idx ON reaction (app_user_id, comment_id) WHERE comment_id IS NOT NULL;
+CREATE INDEX reaction_post_type_idx ON reaction (post_id, type) WHERE post_id IS NOT NULL;
+CREATE INDEX reaction_comment_type_idx ON reaction (comment_id, type) WHERE comment_id IS NOT NULL;
+
+-- migrate:down
+DROP TABLE IF EXISTS reaction;
@@ -0,0 +1,84 @@
+-- migrate:up
+CREATE TABLE bookmark_collection (
+  id serial PRIMARY KEY,
+  uuid uuid NOT NULL UNIQUE,
+  app_user_id integer NOT NULL REFERENCES app_user(id),
+  name varchar(50) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (app_user_id, name)
+);
+
+-- a post is saved once per user, collection_id NULL keeps it outside of any
+-- collection
+CREATE TABLE bookmark (
+  id serial PRIMARY KEY,
+  app_user_id integer NOT NULL REFERENCES app_user(id),
+  post_id integer NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+  collection_id integer REFERENCES bookmark_collection(id) ON DELETE SET NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (app_user_id, post_id)
+);
+
+CREATE INDEX bookmark_user_idx ON bookmark (app_user_id, id DESC);
+CREATE INDEX bookmark_collection_idx ON bookmark (collection_id, id DESC) WHERE collection_id IS NOT NULL;
+CREATE INDEX bookmark_post_idx ON bookmark (post_id);
+
+-- drop bookmarks once the post is deleted, hidden, or its new visibility
+-- shuts the saver out
+CREATE FUNCTION bookmark_purge_post() RETURNS trigger AS $$
+BEGIN
+  IF NEW.deleted_at IS NOT NULL OR NEW.hidden_at IS NOT NULL THEN
+    DELETE FROM bookmark WHERE post_id = NEW.id;
+  ELSIF NEW.visibility_type_id IS DISTINCT FROM OLD.visibility_type_id THEN
+    DELETE FROM bookmark AS b
+    WHERE b.post_id = NEW.id AND b.app_user_id <> NEW.app_user_id
+    AND NOT (NEW.visibility_type_id = 1 OR (NEW.visibility_type_id = 2 AND EXISTS (
+      SELECT 1 FROM follows AS f WHERE f.follower_id = b.app_user_id AND f.followed_id = NEW.app_user_id
+    )));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookmark_purge_post
+  AFTER UPDATE OF deleted_at, hidden_at, visibility_type_id ON post
+  FOR EACH ROW EXECUTE FUNCTION bookmark_purge_post();
+
+-- unfollowing loses access to the author's followers-only posts
+CREATE FUNCTION bookmark_purge_follow() RETURNS trigger AS $$
+BEGIN
+  DELETE FROM bookmark AS b USING post AS p
+  WHERE b.post_id = p.id AND b.app_user_id = OLD.follower_id
+  AND p.app_user_id = OLD.followed_id AND p.visibility_type_id <> 1;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookmark_purge_follow
+  AFTER DELETE ON follows
+  FOR EACH ROW EXECUTE FUNCTION bookmark_purge_follow();
+
+-- a block hides posts both ways
+CREATE FUNCTION bookmark_purge_block() RETURNS trigger AS $$
+BEGIN
+  DELETE FROM bookmark AS b USING post AS p
+  WHERE b.post_id = p.id
+  AND ((b.app_user_id = NEW.blocker_id AND p.app_user_id = NEW.blocked_id)
+    OR (b.app_user_id = NEW.blocked_id AND p.app_user_id = NEW.blocker_id));
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookmark_purge_block
+  AFTER INSERT ON blocks
+  FOR EACH ROW EXECUTE FUNCTION bookmark_purge_block();
+
+-- migrate:down
+DROP TRIGGER IF EXISTS bookmark_purge_block ON blocks;
+DROP FUNCTION IF EXISTS bookmark_purge_block();
+DROP TRIGGER IF EXISTS bookmark_purge_follow ON follows;
+DROP FUNCTION IF EXISTS bookmark_purge_follow();
+DROP TRIGGER IF EXISTS bookmark_purge_post ON post;
+DROP FUNCTION IF EXISTS bookmark_purge_post();
+DROP TABLE IF EXISTS bookmark;
+DROP TABLE IF EXISTS bookmark_collection;
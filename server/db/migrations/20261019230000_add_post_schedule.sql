@@ -0,0 +1,14 @@
+-- migrate:up
+-- a post with published_at NULL is a draft, or scheduled when publish_at is set
+ALTER TABLE post ADD COLUMN publish_at timestamp without time zone;
+ALTER TABLE post ADD COLUMN published_at timestamp without time zone;
+
+UPDATE post SET published_at = COALESCE(updated_at, CURRENT_TIMESTAMP);
+
+CREATE INDEX post_publish_due_idx ON post (publish_at)
+  WHERE published_at IS NULL AND publish_at IS NOT NULL AND deleted_at IS NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS post_publish_due_idx;
+ALTER TABLE post DROP COLUMN IF EXISTS published_at;
+ALTER TABLE post DROP COLUMN IF EXISTS publish_at;
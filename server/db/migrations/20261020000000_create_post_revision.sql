@@ -0,0 +1,17 @@
+-- migrate:up
+ALTER TABLE post ADD COLUMN edited_at timestamp;
+
+-- each row keeps a replaced version of a post's content, created_at is when
+-- that version was written
+CREATE TABLE post_revision (
+  id serial PRIMARY KEY,
+  post_id integer NOT NULL REFERENCES post(id),
+  content text NOT NULL,
+  created_at timestamp NOT NULL
+);
+
+CREATE INDEX post_revision_post_id_idx ON post_revision (post_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS post_revision;
+ALTER TABLE post DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,31 @@
+-- migrate:up
+CREATE TABLE poll (
+  id serial PRIMARY KEY,
+  post_id integer NOT NULL UNIQUE REFERENCES post(id),
+  closes_at timestamp NOT NULL
+);
+
+-- position orders the options of a poll starting at 1
+CREATE TABLE poll_option (
+  id serial PRIMARY KEY,
+  poll_id integer NOT NULL REFERENCES poll(id) ON DELETE CASCADE,
+  position smallint NOT NULL,
+  text varchar(25) NOT NULL,
+  UNIQUE (poll_id, position)
+);
+
+CREATE TABLE poll_vote (
+  id serial PRIMARY KEY,
+  poll_id integer NOT NULL REFERENCES poll(id) ON DELETE CASCADE,
+  option_id integer NOT NULL REFERENCES poll_option(id) ON DELETE CASCADE,
+  app_user_id integer NOT NULL REFERENCES app_user(id),
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (poll_id, app_user_id)
+);
+
+CREATE INDEX poll_vote_option_id_idx ON poll_vote (option_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS poll_vote;
+DROP TABLE IF EXISTS poll_option;
+DROP TABLE IF EXISTS poll;
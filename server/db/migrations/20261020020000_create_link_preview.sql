@@ -0,0 +1,15 @@
+-- migrate:up
+-- one card per post, for the first link in its content
+CREATE TABLE link_preview (
+  id serial PRIMARY KEY,
+  post_id integer NOT NULL UNIQUE REFERENCES post(id),
+  url text NOT NULL,
+  title text NOT NULL,
+  description text NOT NULL DEFAULT '',
+  image_url text NOT NULL DEFAULT '',
+  site_name text NOT NULL DEFAULT '',
+  fetched_at timestamp NOT NULL
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS link_preview;
@@ -0,0 +1,18 @@
+-- migrate:up
+-- updated_at moves on edits, the content filter counts new posts by created_at
+ALTER TABLE post ADD COLUMN created_at timestamp without time zone;
+UPDATE post SET created_at = COALESCE(updated_at, CURRENT_TIMESTAMP);
+ALTER TABLE post ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX post_app_user_created_idx ON post (app_user_id, created_at);
+CREATE INDEX comment_app_user_updated_idx ON comment (app_user_id, updated_at);
+
+-- reports the content filter files have no reporter
+ALTER TABLE report ALTER COLUMN reporter_id DROP NOT NULL;
+
+-- migrate:down
+DELETE FROM report WHERE reporter_id IS NULL;
+ALTER TABLE report ALTER COLUMN reporter_id SET NOT NULL;
+DROP INDEX IF EXISTS comment_app_user_updated_idx;
+DROP INDEX IF EXISTS post_app_user_created_idx;
+ALTER TABLE post DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,13 @@
+-- migrate:up
+-- token buckets shared by all instances, keyed by limit and client. Unlogged,
+-- losing them in a crash only resets the limits.
+CREATE UNLOGGED TABLE rate_limit_bucket (
+  key text PRIMARY KEY,
+  tokens double precision NOT NULL,
+  updated_at timestamp NOT NULL
+);
+
+CREATE INDEX rate_limit_bucket_updated_at_idx ON rate_limit_bucket (updated_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS rate_limit_bucket;
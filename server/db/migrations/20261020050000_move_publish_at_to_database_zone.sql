@@ -0,0 +1,6 @@
+-- migrate:up
+-- publish_at was written in UTC, store it in the database zone like published_at
+UPDATE post SET publish_at = (publish_at AT TIME ZONE 'UTC')::timestamp WHERE publish_at IS NOT NULL;
+
+-- migrate:down
+UPDATE post SET publish_at = publish_at::timestamptz AT TIME ZONE 'UTC' WHERE publish_at IS NOT NULL;
@@ -9,6 +9,88 @@ SET xmloption = content;
 SET client_min_messages = warning;
 SET row_security = off;
 
+--
+-- Name: pg_trgm; Type: EXTENSION; Schema: -; Owner: -
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+
+--
+-- Name: EXTENSION pg_trgm; Type: COMMENT; Schema: -; Owner: -
+--
+
+COMMENT ON EXTENSION pg_trgm IS 'text similarity measurement and index searching based on trigrams';
+
+
+--
+-- Name: bookmark_purge_block(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.bookmark_purge_block() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  DELETE FROM bookmark AS b USING post AS p
+  WHERE b.post_id = p.id
+  AND ((b.app_user_id = NEW.blocker_id AND p.app_user_id = NEW.blocked_id)
+    OR (b.app_user_id = NEW.blocked_id AND p.app_user_id = NEW.blocker_id));
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: bookmark_purge_follow(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.bookmark_purge_follow() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  DELETE FROM bookmark AS b USING post AS p
+  WHERE b.post_id = p.id AND b.app_user_id = OLD.follower_id
+  AND p.app_user_id = OLD.followed_id AND p.visibility_type_id <> 1;
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: bookmark_purge_post(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.bookmark_purge_post() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  IF NEW.deleted_at IS NOT NULL OR NEW.hidden_at IS NOT NULL THEN
+    DELETE FROM bookmark WHERE post_id = NEW.id;
+  ELSIF NEW.visibility_type_id IS DISTINCT FROM OLD.visibility_type_id THEN
+    DELETE FROM bookmark AS b
+    WHERE b.post_id = NEW.id AND b.app_user_id <> NEW.app_user_id
+    AND NOT (NEW.visibility_type_id = 1 OR (NEW.visibility_type_id = 2 AND EXISTS (
+      SELECT 1 FROM follows AS f WHERE f.follower_id = b.app_user_id AND f.followed_id = NEW.app_user_id
+    )));
+  END IF;
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: moderation_log_immutable(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.moderation_log_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  RAISE EXCEPTION 'moderation_log is append-only';
+END;
+$$;
+
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
@@ -27,7 +109,11 @@ CREATE TABLE public.app_user (
     profile_image character varying(250),
     is_deleted boolean DEFAULT false,
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    delete_at date
+    delete_at date,
+    role character varying(20) DEFAULT 'user'::character varying NOT NULL,
+    suspended_at timestamp without time zone,
+    suspended_reason text,
+    username_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple'::regconfig, (username)::text)) STORED
 );
 
 
@@ -52,25 +138,22 @@ ALTER SEQUENCE public.app_user_id_seq OWNED BY public.app_user.id;
 
 
 --
--- Name: comment; Type: TABLE; Schema: public; Owner: -
+-- Name: blocks; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.comment (
+CREATE TABLE public.blocks (
     id integer NOT NULL,
-    uuid uuid,
-    content text,
-    app_user_id integer,
-    post_id integer,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    deleted_at date
+    blocker_id integer NOT NULL,
+    blocked_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
 );
 
 
 --
--- Name: comment_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: blocks_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.comment_id_seq
+CREATE SEQUENCE public.blocks_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -80,29 +163,43 @@ CREATE SEQUENCE public.comment_id_seq
 
 
 --
--- Name: comment_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: blocks_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER SEQUENCE public.comment_id_seq OWNED BY public.comment.id;
+ALTER SEQUENCE public.blocks_id_seq OWNED BY public.blocks.id;
 
 
 --
--- Name: follows; Type: TABLE; Schema: public; Owner: -
+-- Name: bookmark; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.follows (
+CREATE TABLE public.bookmark (
     id integer NOT NULL,
-    follower_id integer NOT NULL,
-    followed_id integer NOT NULL,
-    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+    app_user_id integer NOT NULL,
+    post_id integer NOT NULL,
+    collection_id integer,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
 
 --
--- Name: follows_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: bookmark_collection; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.follows_id_seq
+CREATE TABLE public.bookmark_collection (
+    id integer NOT NULL,
+    uuid uuid NOT NULL,
+    app_user_id integer NOT NULL,
+    name character varying(50) NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+--
+-- Name: bookmark_collection_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.bookmark_collection_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -112,33 +209,55 @@ CREATE SEQUENCE public.follows_id_seq
 
 
 --
--- Name: follows_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: bookmark_collection_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER SEQUENCE public.follows_id_seq OWNED BY public.follows.id;
+ALTER SEQUENCE public.bookmark_collection_id_seq OWNED BY public.bookmark_collection.id;
 
 
 --
--- Name: post; Type: TABLE; Schema: public; Owner: -
+-- Name: bookmark_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.post (
+CREATE SEQUENCE public.bookmark_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: bookmark_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.bookmark_id_seq OWNED BY public.bookmark.id;
+
+
+--
+-- Name: comment; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.comment (
     id integer NOT NULL,
     uuid uuid,
     content text,
-    num_like integer,
-    visibility_type_id integer,
     app_user_id integer,
+    post_id integer,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     deleted_at date,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+    hidden_at timestamp without time zone,
+    hidden_reason text,
+    parent_id integer
 );
 
 
 --
--- Name: post_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: comment_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.post_id_seq
+CREATE SEQUENCE public.comment_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -148,29 +267,30 @@ CREATE SEQUENCE public.post_id_seq
 
 
 --
--- Name: post_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: comment_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER SEQUENCE public.post_id_seq OWNED BY public.post.id;
+ALTER SEQUENCE public.comment_id_seq OWNED BY public.comment.id;
 
 
 --
--- Name: post_image; Type: TABLE; Schema: public; Owner: -
+-- Name: comment_mention; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.post_image (
+CREATE TABLE public.comment_mention (
     id integer NOT NULL,
-    uuid uuid,
-    img_url character varying(250),
-    post_id integer
+    comment_id integer NOT NULL,
+    user_id integer NOT NULL,
+    start_index integer NOT NULL,
+    length integer NOT NULL
 );
 
 
 --
--- Name: post_image_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: comment_mention_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.post_image_id_seq
+CREATE SEQUENCE public.comment_mention_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -180,36 +300,32 @@ CREATE SEQUENCE public.post_image_id_seq
 
 
 --
--- Name: post_image_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
---
-
-ALTER SEQUENCE public.post_image_id_seq OWNED BY public.post_image.id;
-
-
---
--- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+-- Name: comment_mention_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-CREATE TABLE public.schema_migrations (
-    version character varying(128) NOT NULL
-);
+ALTER SEQUENCE public.comment_mention_id_seq OWNED BY public.comment_mention.id;
 
 
 --
--- Name: visibility_type; Type: TABLE; Schema: public; Owner: -
+-- Name: conversation; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.visibility_type (
+CREATE TABLE public.conversation (
     id integer NOT NULL,
-    name character varying(250)
+    uuid uuid NOT NULL,
+    is_group boolean DEFAULT false NOT NULL,
+    title character varying(100),
+    created_by integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
 
 --
--- Name: visibility_type_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: conversation_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.visibility_type_id_seq
+CREATE SEQUENCE public.conversation_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -219,196 +335,1792 @@ CREATE SEQUENCE public.visibility_type_id_seq
 
 
 --
--- Name: visibility_type_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: conversation_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER SEQUENCE public.visibility_type_id_seq OWNED BY public.visibility_type.id;
+ALTER SEQUENCE public.conversation_id_seq OWNED BY public.conversation.id;
 
 
 --
--- Name: app_user id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: conversation_member; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.app_user ALTER COLUMN id SET DEFAULT nextval('public.app_user_id_seq'::regclass);
+CREATE TABLE public.conversation_member (
+    conversation_id integer NOT NULL,
+    user_id integer NOT NULL,
+    joined_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_read_message_id integer,
+    last_read_at timestamp without time zone
+);
 
 
 --
--- Name: comment id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: follows; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.comment ALTER COLUMN id SET DEFAULT nextval('public.comment_id_seq'::regclass);
+CREATE TABLE public.follows (
+    id integer NOT NULL,
+    follower_id integer NOT NULL,
+    followed_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
 
 
 --
--- Name: follows id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: follows_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.follows ALTER COLUMN id SET DEFAULT nextval('public.follows_id_seq'::regclass);
+CREATE SEQUENCE public.follows_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: post id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: follows_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post ALTER COLUMN id SET DEFAULT nextval('public.post_id_seq'::regclass);
+ALTER SEQUENCE public.follows_id_seq OWNED BY public.follows.id;
 
 
 --
--- Name: post_image id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: hashtag; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post_image ALTER COLUMN id SET DEFAULT nextval('public.post_image_id_seq'::regclass);
+CREATE TABLE public.hashtag (
+    id integer NOT NULL,
+    name character varying(50) NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
 
 
 --
--- Name: visibility_type id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: hashtag_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.visibility_type ALTER COLUMN id SET DEFAULT nextval('public.visibility_type_id_seq'::regclass);
+CREATE SEQUENCE public.hashtag_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: app_user app_user_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: hashtag_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.app_user
-    ADD CONSTRAINT app_user_pkey PRIMARY KEY (id);
+ALTER SEQUENCE public.hashtag_id_seq OWNED BY public.hashtag.id;
 
 
 --
--- Name: app_user app_user_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: link_preview; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.app_user
-    ADD CONSTRAINT app_user_uuid_key UNIQUE (uuid);
+CREATE TABLE public.link_preview (
+    id integer NOT NULL,
+    post_id integer NOT NULL,
+    url text NOT NULL,
+    title text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    image_url text DEFAULT ''::text NOT NULL,
+    site_name text DEFAULT ''::text NOT NULL,
+    fetched_at timestamp without time zone NOT NULL
+);
 
 
 --
--- Name: comment comment_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: link_preview_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.comment
-    ADD CONSTRAINT comment_pkey PRIMARY KEY (id);
+CREATE SEQUENCE public.link_preview_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: comment comment_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: link_preview_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.comment
-    ADD CONSTRAINT comment_uuid_key UNIQUE (uuid);
+ALTER SEQUENCE public.link_preview_id_seq OWNED BY public.link_preview.id;
 
 
 --
--- Name: follows follows_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: message; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.follows
-    ADD CONSTRAINT follows_pkey PRIMARY KEY (id);
+CREATE TABLE public.message (
+    id integer NOT NULL,
+    uuid uuid NOT NULL,
+    conversation_id integer NOT NULL,
+    sender_id integer NOT NULL,
+    content text NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
 
 
 --
--- Name: post_image post_image_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: message_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post_image
-    ADD CONSTRAINT post_image_pkey PRIMARY KEY (id);
+CREATE SEQUENCE public.message_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: post_image post_image_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: message_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post_image
-    ADD CONSTRAINT post_image_uuid_key UNIQUE (uuid);
+ALTER SEQUENCE public.message_id_seq OWNED BY public.message.id;
 
 
 --
--- Name: post post_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: moderation_log; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post
-    ADD CONSTRAINT post_pkey PRIMARY KEY (id);
+CREATE TABLE public.moderation_log (
+    id integer NOT NULL,
+    uuid uuid,
+    moderator_id integer,
+    action character varying(50) NOT NULL,
+    target_type character varying(20) NOT NULL,
+    target_uuid uuid NOT NULL,
+    reason text,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
 
 
 --
--- Name: post post_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: moderation_log_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post
-    ADD CONSTRAINT post_uuid_key UNIQUE (uuid);
+CREATE SEQUENCE public.moderation_log_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: moderation_log_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.schema_migrations
-    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+ALTER SEQUENCE public.moderation_log_id_seq OWNED BY public.moderation_log.id;
 
 
 --
--- Name: visibility_type visibility_type_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: mutes; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.visibility_type
-    ADD CONSTRAINT visibility_type_pkey PRIMARY KEY (id);
+CREATE TABLE public.mutes (
+    id integer NOT NULL,
+    muter_id integer NOT NULL,
+    muted_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
 
 
 --
--- Name: comment comment_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: mutes_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.comment
-    ADD CONSTRAINT comment_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+CREATE SEQUENCE public.mutes_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: comment comment_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: mutes_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.comment
-    ADD CONSTRAINT comment_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+ALTER SEQUENCE public.mutes_id_seq OWNED BY public.mutes.id;
 
 
 --
--- Name: follows follows_followed_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: notification; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.follows
-    ADD CONSTRAINT follows_followed_id_fkey FOREIGN KEY (followed_id) REFERENCES public.app_user(id);
+CREATE TABLE public.notification (
+    id integer NOT NULL,
+    uuid uuid,
+    recipient_id integer NOT NULL,
+    actor_id integer NOT NULL,
+    type character varying(20) NOT NULL,
+    target_type character varying(20) NOT NULL,
+    target_uuid uuid NOT NULL,
+    read_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
 
 
 --
--- Name: follows follows_follower_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: notification_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.follows
-    ADD CONSTRAINT follows_follower_id_fkey FOREIGN KEY (follower_id) REFERENCES public.app_user(id);
+CREATE SEQUENCE public.notification_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
 --
--- Name: post post_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: notification_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post
-    ADD CONSTRAINT post_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+ALTER SEQUENCE public.notification_id_seq OWNED BY public.notification.id;
 
 
 --
--- Name: post_image post_image_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: poll; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post_image
-    ADD CONSTRAINT post_image_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+CREATE TABLE public.poll (
+    id integer NOT NULL,
+    post_id integer NOT NULL,
+    closes_at timestamp without time zone NOT NULL
+);
 
 
 --
--- Name: post post_visibility_type_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: poll_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.post
-    ADD CONSTRAINT post_visibility_type_id_fkey FOREIGN KEY (visibility_type_id) REFERENCES public.visibility_type(id);
+CREATE SEQUENCE public.poll_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: poll_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.poll_id_seq OWNED BY public.poll.id;
+
+
+--
+-- Name: poll_option; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.poll_option (
+    id integer NOT NULL,
+    poll_id integer NOT NULL,
+    "position" smallint NOT NULL,
+    text character varying(25) NOT NULL
+);
+
+
+--
+-- Name: poll_option_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.poll_option_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: poll_option_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.poll_option_id_seq OWNED BY public.poll_option.id;
+
+
+--
+-- Name: poll_vote; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.poll_vote (
+    id integer NOT NULL,
+    poll_id integer NOT NULL,
+    option_id integer NOT NULL,
+    app_user_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+--
+-- Name: poll_vote_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.poll_vote_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: poll_vote_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.poll_vote_id_seq OWNED BY public.poll_vote.id;
+
+
+--
+-- Name: post; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.post (
+    id integer NOT NULL,
+    uuid uuid,
+    content text,
+    num_like integer,
+    visibility_type_id integer,
+    app_user_id integer,
+    deleted_at date,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    hidden_at timestamp without time zone,
+    hidden_reason text,
+    content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('english'::regconfig, COALESCE(content, ''::text))) STORED,
+    original_post_id integer,
+    is_quote boolean DEFAULT false NOT NULL,
+    publish_at timestamp without time zone,
+    published_at timestamp without time zone,
+    edited_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: post_hashtag; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.post_hashtag (
+    post_id integer NOT NULL,
+    hashtag_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+--
+-- Name: post_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.post_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: post_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.post_id_seq OWNED BY public.post.id;
+
+
+--
+-- Name: post_image; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.post_image (
+    id integer NOT NULL,
+    uuid uuid,
+    img_url character varying(250),
+    post_id integer
+);
+
+
+--
+-- Name: post_image_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.post_image_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: post_image_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.post_image_id_seq OWNED BY public.post_image.id;
+
+
+--
+-- Name: post_mention; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.post_mention (
+    id integer NOT NULL,
+    post_id integer NOT NULL,
+    user_id integer NOT NULL,
+    start_index integer NOT NULL,
+    length integer NOT NULL
+);
+
+
+--
+-- Name: post_mention_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.post_mention_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: post_mention_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.post_mention_id_seq OWNED BY public.post_mention.id;
+
+
+--
+-- Name: post_revision; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.post_revision (
+    id integer NOT NULL,
+    post_id integer NOT NULL,
+    content text NOT NULL,
+    created_at timestamp without time zone NOT NULL
+);
+
+
+--
+-- Name: post_revision_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.post_revision_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: post_revision_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.post_revision_id_seq OWNED BY public.post_revision.id;
+
+
+--
+-- Name: rate_limit_bucket; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE UNLOGGED TABLE public.rate_limit_bucket (
+    key text NOT NULL,
+    tokens double precision NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+--
+-- Name: reaction; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.reaction (
+    id integer NOT NULL,
+    app_user_id integer NOT NULL,
+    post_id integer,
+    comment_id integer,
+    type character varying(20) NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+--
+-- Name: reaction_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.reaction_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: reaction_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.reaction_id_seq OWNED BY public.reaction.id;
+
+
+--
+-- Name: report; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.report (
+    id integer NOT NULL,
+    uuid uuid,
+    reporter_id integer,
+    target_type character varying(20) NOT NULL,
+    target_uuid uuid NOT NULL,
+    category character varying(30) NOT NULL,
+    detail text,
+    status character varying(20) DEFAULT 'open'::character varying NOT NULL,
+    resolved_by integer,
+    resolved_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: report_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.report_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: report_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.report_id_seq OWNED BY public.report.id;
+
+
+--
+-- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.schema_migrations (
+    version character varying(128) NOT NULL
+);
+
+
+--
+-- Name: visibility_type; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.visibility_type (
+    id integer NOT NULL,
+    name character varying(250)
+);
+
+
+--
+-- Name: visibility_type_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.visibility_type_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: visibility_type_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.visibility_type_id_seq OWNED BY public.visibility_type.id;
+
+
+--
+-- Name: app_user id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.app_user ALTER COLUMN id SET DEFAULT nextval('public.app_user_id_seq'::regclass);
+
+
+--
+-- Name: blocks id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.blocks ALTER COLUMN id SET DEFAULT nextval('public.blocks_id_seq'::regclass);
+
+
+--
+-- Name: bookmark id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark ALTER COLUMN id SET DEFAULT nextval('public.bookmark_id_seq'::regclass);
+
+
+--
+-- Name: bookmark_collection id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark_collection ALTER COLUMN id SET DEFAULT nextval('public.bookmark_collection_id_seq'::regclass);
+
+
+--
+-- Name: comment id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment ALTER COLUMN id SET DEFAULT nextval('public.comment_id_seq'::regclass);
+
+
+--
+-- Name: comment_mention id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mention ALTER COLUMN id SET DEFAULT nextval('public.comment_mention_id_seq'::regclass);
+
+
+--
+-- Name: conversation id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation ALTER COLUMN id SET DEFAULT nextval('public.conversation_id_seq'::regclass);
+
+
+--
+-- Name: follows id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.follows ALTER COLUMN id SET DEFAULT nextval('public.follows_id_seq'::regclass);
+
+
+--
+-- Name: hashtag id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hashtag ALTER COLUMN id SET DEFAULT nextval('public.hashtag_id_seq'::regclass);
+
+
+--
+-- Name: link_preview id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link_preview ALTER COLUMN id SET DEFAULT nextval('public.link_preview_id_seq'::regclass);
+
+
+--
+-- Name: message id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.message ALTER COLUMN id SET DEFAULT nextval('public.message_id_seq'::regclass);
+
+
+--
+-- Name: moderation_log id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.moderation_log ALTER COLUMN id SET DEFAULT nextval('public.moderation_log_id_seq'::regclass);
+
+
+--
+-- Name: mutes id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.mutes ALTER COLUMN id SET DEFAULT nextval('public.mutes_id_seq'::regclass);
+
+
+--
+-- Name: notification id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification ALTER COLUMN id SET DEFAULT nextval('public.notification_id_seq'::regclass);
+
+
+--
+-- Name: poll id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll ALTER COLUMN id SET DEFAULT nextval('public.poll_id_seq'::regclass);
+
+
+--
+-- Name: poll_option id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_option ALTER COLUMN id SET DEFAULT nextval('public.poll_option_id_seq'::regclass);
+
+
+--
+-- Name: poll_vote id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_vote ALTER COLUMN id SET DEFAULT nextval('public.poll_vote_id_seq'::regclass);
+
+
+--
+-- Name: post id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post ALTER COLUMN id SET DEFAULT nextval('public.post_id_seq'::regclass);
+
+
+--
+-- Name: post_image id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_image ALTER COLUMN id SET DEFAULT nextval('public.post_image_id_seq'::regclass);
+
+
+--
+-- Name: post_mention id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_mention ALTER COLUMN id SET DEFAULT nextval('public.post_mention_id_seq'::regclass);
+
+
+--
+-- Name: post_revision id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_revision ALTER COLUMN id SET DEFAULT nextval('public.post_revision_id_seq'::regclass);
+
+
+--
+-- Name: reaction id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.reaction ALTER COLUMN id SET DEFAULT nextval('public.reaction_id_seq'::regclass);
+
+
+--
+-- Name: report id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report ALTER COLUMN id SET DEFAULT nextval('public.report_id_seq'::regclass);
+
+
+--
+-- Name: visibility_type id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.visibility_type ALTER COLUMN id SET DEFAULT nextval('public.visibility_type_id_seq'::regclass);
+
+
+--
+-- Name: app_user app_user_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.app_user
+    ADD CONSTRAINT app_user_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: app_user app_user_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.app_user
+    ADD CONSTRAINT app_user_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: blocks blocks_blocker_id_blocked_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.blocks
+    ADD CONSTRAINT blocks_blocker_id_blocked_id_key UNIQUE (blocker_id, blocked_id);
+
+
+--
+-- Name: blocks blocks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.blocks
+    ADD CONSTRAINT blocks_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: bookmark bookmark_app_user_id_post_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark
+    ADD CONSTRAINT bookmark_app_user_id_post_id_key UNIQUE (app_user_id, post_id);
+
+
+--
+-- Name: bookmark_collection bookmark_collection_app_user_id_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark_collection
+    ADD CONSTRAINT bookmark_collection_app_user_id_name_key UNIQUE (app_user_id, name);
+
+
+--
+-- Name: bookmark_collection bookmark_collection_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark_collection
+    ADD CONSTRAINT bookmark_collection_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: bookmark_collection bookmark_collection_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark_collection
+    ADD CONSTRAINT bookmark_collection_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: bookmark bookmark_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark
+    ADD CONSTRAINT bookmark_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: comment_mention comment_mention_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mention
+    ADD CONSTRAINT comment_mention_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: comment comment_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: comment comment_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: conversation_member conversation_member_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation_member
+    ADD CONSTRAINT conversation_member_pkey PRIMARY KEY (conversation_id, user_id);
+
+
+--
+-- Name: conversation conversation_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation
+    ADD CONSTRAINT conversation_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: conversation conversation_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation
+    ADD CONSTRAINT conversation_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: follows follows_follower_id_followed_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.follows
+    ADD CONSTRAINT follows_follower_id_followed_id_key UNIQUE (follower_id, followed_id);
+
+
+--
+-- Name: follows follows_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.follows
+    ADD CONSTRAINT follows_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: hashtag hashtag_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hashtag
+    ADD CONSTRAINT hashtag_name_key UNIQUE (name);
+
+
+--
+-- Name: hashtag hashtag_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hashtag
+    ADD CONSTRAINT hashtag_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: link_preview link_preview_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link_preview
+    ADD CONSTRAINT link_preview_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: link_preview link_preview_post_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link_preview
+    ADD CONSTRAINT link_preview_post_id_key UNIQUE (post_id);
+
+
+--
+-- Name: message message_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.message
+    ADD CONSTRAINT message_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: message message_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.message
+    ADD CONSTRAINT message_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: moderation_log moderation_log_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.moderation_log
+    ADD CONSTRAINT moderation_log_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: moderation_log moderation_log_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.moderation_log
+    ADD CONSTRAINT moderation_log_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: mutes mutes_muter_id_muted_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.mutes
+    ADD CONSTRAINT mutes_muter_id_muted_id_key UNIQUE (muter_id, muted_id);
+
+
+--
+-- Name: mutes mutes_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.mutes
+    ADD CONSTRAINT mutes_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notification notification_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notification notification_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: poll_option poll_option_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_option
+    ADD CONSTRAINT poll_option_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: poll_option poll_option_poll_id_position_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_option
+    ADD CONSTRAINT poll_option_poll_id_position_key UNIQUE (poll_id, "position");
+
+
+--
+-- Name: poll poll_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll
+    ADD CONSTRAINT poll_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: poll poll_post_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll
+    ADD CONSTRAINT poll_post_id_key UNIQUE (post_id);
+
+
+--
+-- Name: poll_vote poll_vote_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_vote
+    ADD CONSTRAINT poll_vote_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: poll_vote poll_vote_poll_id_app_user_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_vote
+    ADD CONSTRAINT poll_vote_poll_id_app_user_id_key UNIQUE (poll_id, app_user_id);
+
+
+--
+-- Name: post_hashtag post_hashtag_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_hashtag
+    ADD CONSTRAINT post_hashtag_pkey PRIMARY KEY (post_id, hashtag_id);
+
+
+--
+-- Name: post_image post_image_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_image
+    ADD CONSTRAINT post_image_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: post_image post_image_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_image
+    ADD CONSTRAINT post_image_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: post_mention post_mention_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_mention
+    ADD CONSTRAINT post_mention_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: post post_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post
+    ADD CONSTRAINT post_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: post_revision post_revision_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_revision
+    ADD CONSTRAINT post_revision_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: post post_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post
+    ADD CONSTRAINT post_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: rate_limit_bucket rate_limit_bucket_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.rate_limit_bucket
+    ADD CONSTRAINT rate_limit_bucket_pkey PRIMARY KEY (key);
+
+
+--
+-- Name: reaction reaction_one_target; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.reaction
+    ADD CONSTRAINT reaction_one_target CHECK (((post_id IS NULL) <> (comment_id IS NULL)));
+
+
+--
+-- Name: reaction reaction_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.reaction
+    ADD CONSTRAINT reaction_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: report report_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report
+    ADD CONSTRAINT report_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: report report_reporter_id_target_type_target_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report
+    ADD CONSTRAINT report_reporter_id_target_type_target_uuid_key UNIQUE (reporter_id, target_type, target_uuid);
+
+
+--
+-- Name: report report_uuid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report
+    ADD CONSTRAINT report_uuid_key UNIQUE (uuid);
+
+
+--
+-- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.schema_migrations
+    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+
+
+--
+-- Name: visibility_type visibility_type_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.visibility_type
+    ADD CONSTRAINT visibility_type_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: app_user_username_trgm_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX app_user_username_trgm_idx ON public.app_user USING gin (username public.gin_trgm_ops);
+
+
+--
+-- Name: app_user_username_tsv_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX app_user_username_tsv_idx ON public.app_user USING gin (username_tsv);
+
+
+--
+-- Name: blocks_blocked_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX blocks_blocked_id_idx ON public.blocks USING btree (blocked_id);
+
+
+--
+-- Name: bookmark_collection_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX bookmark_collection_idx ON public.bookmark USING btree (collection_id, id DESC) WHERE (collection_id IS NOT NULL);
+
+
+--
+-- Name: bookmark_post_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX bookmark_post_idx ON public.bookmark USING btree (post_id);
+
+
+--
+-- Name: bookmark_user_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX bookmark_user_idx ON public.bookmark USING btree (app_user_id, id DESC);
+
+
+--
+-- Name: comment_app_user_updated_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX comment_app_user_updated_idx ON public.comment USING btree (app_user_id, updated_at);
+
+
+--
+-- Name: comment_mention_comment_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX comment_mention_comment_id_idx ON public.comment_mention USING btree (comment_id);
+
+
+--
+-- Name: comment_parent_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX comment_parent_idx ON public.comment USING btree (parent_id, id) WHERE (parent_id IS NOT NULL);
+
+
+--
+-- Name: conversation_member_user_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX conversation_member_user_idx ON public.conversation_member USING btree (user_id);
+
+
+--
+-- Name: message_conversation_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX message_conversation_idx ON public.message USING btree (conversation_id, id DESC);
+
+
+--
+-- Name: moderation_log_target_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX moderation_log_target_idx ON public.moderation_log USING btree (target_type, target_uuid);
+
+
+--
+-- Name: notification_recipient_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX notification_recipient_idx ON public.notification USING btree (recipient_id, id DESC);
+
+
+--
+-- Name: notification_unread_event_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX notification_unread_event_idx ON public.notification USING btree (recipient_id, actor_id, type, target_uuid) WHERE (read_at IS NULL);
+
+
+--
+-- Name: poll_vote_option_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX poll_vote_option_id_idx ON public.poll_vote USING btree (option_id);
+
+
+--
+-- Name: post_app_user_created_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_app_user_created_idx ON public.post USING btree (app_user_id, created_at);
+
+
+--
+-- Name: post_content_tsv_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_content_tsv_idx ON public.post USING gin (content_tsv);
+
+
+--
+-- Name: post_hashtag_created_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_hashtag_created_at_idx ON public.post_hashtag USING btree (created_at);
+
+
+--
+-- Name: post_hashtag_timeline_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_hashtag_timeline_idx ON public.post_hashtag USING btree (hashtag_id, post_id DESC);
+
+
+--
+-- Name: post_mention_post_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_mention_post_id_idx ON public.post_mention USING btree (post_id);
+
+
+--
+-- Name: post_original_post_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_original_post_idx ON public.post USING btree (original_post_id) WHERE (original_post_id IS NOT NULL);
+
+
+--
+-- Name: post_publish_due_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_publish_due_idx ON public.post USING btree (publish_at) WHERE ((published_at IS NULL) AND (publish_at IS NOT NULL) AND (deleted_at IS NULL));
+
+
+--
+-- Name: post_repost_unique_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX post_repost_unique_idx ON public.post USING btree (app_user_id, original_post_id) WHERE ((NOT is_quote) AND (deleted_at IS NULL));
+
+
+--
+-- Name: post_revision_post_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX post_revision_post_id_idx ON public.post_revision USING btree (post_id);
+
+
+--
+-- Name: rate_limit_bucket_updated_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX rate_limit_bucket_updated_at_idx ON public.rate_limit_bucket USING btree (updated_at);
+
+
+--
+-- Name: reaction_comment_type_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX reaction_comment_type_idx ON public.reaction USING btree (comment_id, type) WHERE (comment_id IS NOT NULL);
+
+
+--
+-- Name: reaction_post_type_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX reaction_post_type_idx ON public.reaction USING btree (post_id, type) WHERE (post_id IS NOT NULL);
+
+
+--
+-- Name: reaction_user_comment_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX reaction_user_comment_idx ON public.reaction USING btree (app_user_id, comment_id) WHERE (comment_id IS NOT NULL);
+
+
+--
+-- Name: reaction_user_post_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX reaction_user_post_idx ON public.reaction USING btree (app_user_id, post_id) WHERE (post_id IS NOT NULL);
+
+
+--
+-- Name: report_queue_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX report_queue_idx ON public.report USING btree (status, target_type, target_uuid);
+
+
+--
+-- Name: blocks bookmark_purge_block; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER bookmark_purge_block AFTER INSERT ON public.blocks FOR EACH ROW EXECUTE FUNCTION public.bookmark_purge_block();
+
+
+--
+-- Name: follows bookmark_purge_follow; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER bookmark_purge_follow AFTER DELETE ON public.follows FOR EACH ROW EXECUTE FUNCTION public.bookmark_purge_follow();
+
+
+--
+-- Name: post bookmark_purge_post; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER bookmark_purge_post AFTER UPDATE OF deleted_at, hidden_at, visibility_type_id ON public.post FOR EACH ROW EXECUTE FUNCTION public.bookmark_purge_post();
+
+
+--
+-- Name: moderation_log moderation_log_immutable; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER moderation_log_immutable BEFORE DELETE OR UPDATE ON public.moderation_log FOR EACH ROW EXECUTE FUNCTION public.moderation_log_immutable();
+
+
+--
+-- Name: blocks blocks_blocked_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.blocks
+    ADD CONSTRAINT blocks_blocked_id_fkey FOREIGN KEY (blocked_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: blocks blocks_blocker_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.blocks
+    ADD CONSTRAINT blocks_blocker_id_fkey FOREIGN KEY (blocker_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: bookmark bookmark_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark
+    ADD CONSTRAINT bookmark_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: bookmark_collection bookmark_collection_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark_collection
+    ADD CONSTRAINT bookmark_collection_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: bookmark bookmark_collection_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark
+    ADD CONSTRAINT bookmark_collection_id_fkey FOREIGN KEY (collection_id) REFERENCES public.bookmark_collection(id) ON DELETE SET NULL;
+
+
+--
+-- Name: bookmark bookmark_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.bookmark
+    ADD CONSTRAINT bookmark_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment comment_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: comment_mention comment_mention_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mention
+    ADD CONSTRAINT comment_mention_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.comment(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment_mention comment_mention_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mention
+    ADD CONSTRAINT comment_mention_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: comment comment_parent_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES public.comment(id);
+
+
+--
+-- Name: comment comment_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+
+
+--
+-- Name: conversation conversation_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation
+    ADD CONSTRAINT conversation_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.app_user(id);
+
+
+--
+-- Name: conversation_member conversation_member_conversation_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation_member
+    ADD CONSTRAINT conversation_member_conversation_id_fkey FOREIGN KEY (conversation_id) REFERENCES public.conversation(id) ON DELETE CASCADE;
+
+
+--
+-- Name: conversation_member conversation_member_last_read_message_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation_member
+    ADD CONSTRAINT conversation_member_last_read_message_id_fkey FOREIGN KEY (last_read_message_id) REFERENCES public.message(id) ON DELETE SET NULL;
+
+
+--
+-- Name: conversation_member conversation_member_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversation_member
+    ADD CONSTRAINT conversation_member_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: follows follows_followed_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.follows
+    ADD CONSTRAINT follows_followed_id_fkey FOREIGN KEY (followed_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: follows follows_follower_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.follows
+    ADD CONSTRAINT follows_follower_id_fkey FOREIGN KEY (follower_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: link_preview link_preview_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link_preview
+    ADD CONSTRAINT link_preview_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+
+
+--
+-- Name: message message_conversation_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.message
+    ADD CONSTRAINT message_conversation_id_fkey FOREIGN KEY (conversation_id) REFERENCES public.conversation(id) ON DELETE CASCADE;
+
+
+--
+-- Name: message message_sender_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.message
+    ADD CONSTRAINT message_sender_id_fkey FOREIGN KEY (sender_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: moderation_log moderation_log_moderator_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.moderation_log
+    ADD CONSTRAINT moderation_log_moderator_id_fkey FOREIGN KEY (moderator_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: mutes mutes_muted_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.mutes
+    ADD CONSTRAINT mutes_muted_id_fkey FOREIGN KEY (muted_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: mutes mutes_muter_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.mutes
+    ADD CONSTRAINT mutes_muter_id_fkey FOREIGN KEY (muter_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: notification notification_actor_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: notification notification_recipient_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_recipient_id_fkey FOREIGN KEY (recipient_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: poll_option poll_option_poll_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_option
+    ADD CONSTRAINT poll_option_poll_id_fkey FOREIGN KEY (poll_id) REFERENCES public.poll(id) ON DELETE CASCADE;
+
+
+--
+-- Name: poll poll_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll
+    ADD CONSTRAINT poll_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+
+
+--
+-- Name: poll_vote poll_vote_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_vote
+    ADD CONSTRAINT poll_vote_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: poll_vote poll_vote_option_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_vote
+    ADD CONSTRAINT poll_vote_option_id_fkey FOREIGN KEY (option_id) REFERENCES public.poll_option(id) ON DELETE CASCADE;
+
+
+--
+-- Name: poll_vote poll_vote_poll_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.poll_vote
+    ADD CONSTRAINT poll_vote_poll_id_fkey FOREIGN KEY (poll_id) REFERENCES public.poll(id) ON DELETE CASCADE;
+
+
+--
+-- Name: post post_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post
+    ADD CONSTRAINT post_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: post_hashtag post_hashtag_hashtag_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_hashtag
+    ADD CONSTRAINT post_hashtag_hashtag_id_fkey FOREIGN KEY (hashtag_id) REFERENCES public.hashtag(id) ON DELETE CASCADE;
+
+
+--
+-- Name: post_hashtag post_hashtag_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_hashtag
+    ADD CONSTRAINT post_hashtag_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id) ON DELETE CASCADE;
+
+
+--
+-- Name: post_image post_image_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_image
+    ADD CONSTRAINT post_image_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+
+
+--
+-- Name: post_mention post_mention_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_mention
+    ADD CONSTRAINT post_mention_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id) ON DELETE CASCADE;
+
+
+--
+-- Name: post_mention post_mention_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_mention
+    ADD CONSTRAINT post_mention_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: post post_original_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post
+    ADD CONSTRAINT post_original_post_id_fkey FOREIGN KEY (original_post_id) REFERENCES public.post(id);
+
+
+--
+-- Name: post_revision post_revision_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post_revision
+    ADD CONSTRAINT post_revision_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id);
+
+
+--
+-- Name: post post_visibility_type_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.post
+    ADD CONSTRAINT post_visibility_type_id_fkey FOREIGN KEY (visibility_type_id) REFERENCES public.visibility_type(id);
+
+
+--
+-- Name: reaction reaction_app_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.reaction
+    ADD CONSTRAINT reaction_app_user_id_fkey FOREIGN KEY (app_user_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: reaction reaction_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.reaction
+    ADD CONSTRAINT reaction_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.comment(id) ON DELETE CASCADE;
+
+
+--
+-- Name: reaction reaction_post_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.reaction
+    ADD CONSTRAINT reaction_post_id_fkey FOREIGN KEY (post_id) REFERENCES public.post(id) ON DELETE CASCADE;
+
+
+--
+-- Name: report report_reporter_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report
+    ADD CONSTRAINT report_reporter_id_fkey FOREIGN KEY (reporter_id) REFERENCES public.app_user(id);
+
+
+--
+-- Name: report report_resolved_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report
+    ADD CONSTRAINT report_resolved_by_fkey FOREIGN KEY (resolved_by) REFERENCES public.app_user(id);
 
 
 --
@@ -423,4 +2135,24 @@ ALTER TABLE ONLY public.post
 INSERT INTO public.schema_migrations (version) VALUES
     ('20240601034344'),
     ('20240601154833'),
-    ('20240606122747');
+    ('20240606122747'),
+    ('20261019100000'),
+    ('20261019110000'),
+    ('20261019120000'),
+    ('20261019130000'),
+    ('20261019140000'),
+    ('20261019150000'),
+    ('20261019160000'),
+    ('20261019170000'),
+    ('20261019180000'),
+    ('20261019190000'),
+    ('20261019200000'),
+    ('20261019210000'),
+    ('20261019220000'),
+    ('20261019230000'),
+    ('20261020000000'),
+    ('20261020010000'),
+    ('20261020020000'),
+    ('20261020030000'),
+    ('20261020040000'),
+    ('20261020050000');
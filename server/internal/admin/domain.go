@@ -0,0 +1,71 @@
+package admin
+
+import "time"
+
+const (
+	ActionSuspendUser   = "suspend_user"
+	ActionUnsuspendUser = "unsuspend_user"
+	ActionHidePost      = "hide_post"
+	ActionRemovePost    = "remove_post"
+	ActionHideComment   = "hide_comment"
+	ActionRemoveComment = "remove_comment"
+)
+
+const (
+	TargetUser    = "user"
+	TargetPost    = "post"
+	TargetComment = "comment"
+)
+
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+)
+
+type UserFilter struct {
+	Username string
+	Role     string
+	Status   string
+	Limit    int
+	Offset   int
+}
+
+type AdminUserResponse struct {
+	UUID            string     `json:"uuid"`
+	Username        string     `json:"username"`
+	Email           *string    `json:"email"`
+	Role            string     `json:"role"`
+	SuspendedAt     *time.Time `json:"suspended_at"`
+	SuspendedReason *string    `json:"suspended_reason"`
+	UpdateAt        time.Time  `json:"update_at"`
+}
+
+type Moderation struct {
+	ModeratorUUID string
+	Action        string
+	TargetType    string
+	TargetUUID    string
+	Reason        string
+}
+
+type ModerationReason struct {
+	Reason string `json:"reason"`
+}
+
+type ModerationLog struct {
+	UUID          string    `json:"uuid"`
+	ModeratorUUID *string   `json:"moderator_uuid"`
+	ModeratorName *string   `json:"moderator_username"`
+	Action        string    `json:"action"`
+	TargetType    string    `json:"target_type"`
+	TargetUUID    string    `json:"target_uuid"`
+	Reason        *string   `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LogFilter struct {
+	TargetType string
+	TargetUUID string
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IAdminService interface {
+	GetUsers(UserFilter) ([]AdminUserResponse, error)
+	SuspendUser(moderatorUUID, userUUID, reason string) error
+	UnsuspendUser(moderatorUUID, userUUID, reason string) error
+	HidePost(moderatorUUID, postUUID, reason string) error
+	RemovePost(moderatorUUID, postUUID, reason string) error
+	HideComment(moderatorUUID, commentUUID, reason string) error
+	RemoveComment(moderatorUUID, commentUUID, reason string) error
+	GetLogs(LogFilter) ([]ModerationLog, error)
+}
+
+type AdminHandler struct {
+	adminService IAdminService
+}
+
+func NewAdminHandler(adminService IAdminService) *AdminHandler {
+	return &AdminHandler{adminService}
+}
+
+func (h *AdminHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	users, err := h.adminService.GetUsers(UserFilter{
+		Username: q.Get("username"),
+		Role:     q.Get("role"),
+		Status:   q.Get("status"),
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	})
+	if err != nil {
+		if err == ErrInvalidFilter {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+		errRes := util.BuildErrResponse("service failed")
+		util.SendJson(w, errRes(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, users, http.StatusOK)
+}
+
+func (h *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.adminService.SuspendUser, "suspended user successful!")
+}
+
+func (h *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.adminService.UnsuspendUser, "unsuspended user successful!")
+}
+
+func (h *AdminHandler) HidePost(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.adminService.HidePost, "hid post successful!")
+}
+
+func (h *AdminHandler) RemovePost(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.adminService.RemovePost, "removed post successful!")
+}
+
+func (h *AdminHandler) HideComment(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.adminService.HideComment, "hid comment successful!")
+}
+
+func (h *AdminHandler) RemoveComment(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.adminService.RemoveComment, "removed comment successful!")
+}
+
+func (h *AdminHandler) moderate(w http.ResponseWriter, r *http.Request,
+	action func(moderatorUUID, targetUUID, reason string) error, message string,
+) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	targetUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(targetUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	moderatorUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || moderatorUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	var body ModerationReason
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := action(moderatorUUID, targetUUID, body.Reason); err != nil {
+		switch err {
+		case ErrReasonRequired, ErrSelfModeration:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrTargetNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse(message), http.StatusOK)
+}
+
+func (h *AdminHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	targetUUID := q.Get("target_uuid")
+	if targetUUID != "" && !util.IsValidUUID(targetUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	logs, err := h.adminService.GetLogs(LogFilter{
+		TargetType: q.Get("target_type"),
+		TargetUUID: targetUUID,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+	})
+	if err != nil {
+		if err == ErrInvalidFilter {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+		errRes := util.BuildErrResponse("service failed")
+		util.SendJson(w, errRes(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, logs, http.StatusOK)
+}
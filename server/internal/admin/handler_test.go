@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	users []AdminUserResponse
+	logs  []ModerationLog
+	isErr error
+}
+
+func (m *mService) GetUsers(UserFilter) ([]AdminUserResponse, error) {
+	return m.users, m.isErr
+}
+
+func (m *mService) SuspendUser(string, string, string) error {
+	return m.isErr
+}
+
+func (m *mService) UnsuspendUser(string, string, string) error {
+	return m.isErr
+}
+
+func (m *mService) HidePost(string, string, string) error {
+	return m.isErr
+}
+
+func (m *mService) RemovePost(string, string, string) error {
+	return m.isErr
+}
+
+func (m *mService) HideComment(string, string, string) error {
+	return m.isErr
+}
+
+func (m *mService) RemoveComment(string, string, string) error {
+	return m.isErr
+}
+
+func (m *mService) GetLogs(LogFilter) ([]ModerationLog, error) {
+	return m.logs, m.isErr
+}
+
+func TestHandlerGetUsers(t *testing.T) {
+	testTable := []struct {
+		title      string
+		url        string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return users", "/?username=on&status=active", nil, http.StatusOK},
+		{"should bad request cause invalid limit", "/?limit=abc", nil, http.StatusBadRequest},
+		{"should bad request cause invalid filter", "/?status=abc", ErrInvalidFilter, http.StatusBadRequest},
+		{"should service failed", "/", errors.New("service err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewAdminHandler(&mService{users: []AdminUserResponse{}, isErr: v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, v.url, nil)
+			rec := httptest.NewRecorder()
+			h.GetUsers(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerHidePost(t *testing.T) {
+	body, _ := json.Marshal(ModerationReason{Reason: "spam"})
+	testTable := []struct {
+		title      string
+		uuid       string
+		body       []byte
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{
+			"should hide post", "f6630558-b800-48ff-9a09-5863d6055154", body, nil, http.StatusOK,
+			util.BuildResponse("hid post successful!"),
+		},
+		{
+			"should bad request cause invalid uuid", "abc", body, nil, http.StatusBadRequest,
+			util.BuildErrResponse("invalid request")(ErrInvalidUUID),
+		},
+		{
+			"should bad request cause invalid body", "f6630558-b800-48ff-9a09-5863d6055154", []byte("abc"), nil, http.StatusBadRequest,
+			util.BuildErrResponse("invalid request")(nil),
+		},
+		{
+			"should bad request cause reason required", "f6630558-b800-48ff-9a09-5863d6055154", []byte("{}"), ErrReasonRequired, http.StatusBadRequest,
+			util.BuildErrResponse("invalid request")(ErrReasonRequired),
+		},
+		{
+			"should not found", "f6630558-b800-48ff-9a09-5863d6055154", body, ErrTargetNotFound, http.StatusNotFound,
+			util.BuildErrResponse("not found")(ErrTargetNotFound),
+		},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewAdminHandler(&mService{isErr: v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader(v.body))
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "0ee1abd0-a330-488d-b170-b33f58dd6178"))
+			rec := httptest.NewRecorder()
+			h.HidePost(rec, req)
+
+			var res map[string]string
+			json.NewDecoder(rec.Body).Decode(&res)
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantBody["message"], res["message"], "Want %v but got %v", v.wantBody, res)
+		})
+	}
+}
+
+func TestHandlerGetLogs(t *testing.T) {
+	testTable := []struct {
+		title      string
+		url        string
+		wantStatus int
+	}{
+		{"should return logs", "/?target_type=post", http.StatusOK},
+		{"should bad request cause invalid target uuid", "/?target_uuid=abc", http.StatusBadRequest},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewAdminHandler(&mService{logs: []ModerationLog{}})
+
+			req, _ := http.NewRequest(http.MethodGet, v.url, nil)
+			rec := httptest.NewRecorder()
+			h.GetLogs(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/google/uuid"
+)
+
+var ErrTargetNotFound = errors.New("target not found")
+
+type AdminRepository struct {
+	db *sql.DB
+}
+
+func NewAdminRepository(db *sql.DB) *AdminRepository {
+	return &AdminRepository{db}
+}
+
+func (r *AdminRepository) GetUsers(f UserFilter) ([]AdminUserResponse, error) {
+	var conds []string
+	var args []interface{}
+	if f.Username != "" {
+		args = append(args, util.LikeEscaper.Replace(f.Username)+"%")
+		conds = append(conds, fmt.Sprintf(`username ILIKE $%d ESCAPE '\'`, len(args)))
+	}
+	if f.Role != "" {
+		args = append(args, f.Role)
+		conds = append(conds, fmt.Sprintf("role = $%d", len(args)))
+	}
+	switch f.Status {
+	case StatusActive:
+		conds = append(conds, "suspended_at IS NULL")
+	case StatusSuspended:
+		conds = append(conds, "suspended_at IS NOT NULL")
+	}
+
+	query := `
+  SELECT uuid, username, email, role, suspended_at, suspended_reason, updated_at
+  FROM app_user
+  WHERE delete_at IS NULL`
+	if len(conds) > 0 {
+		query += " AND " + strings.Join(conds, " AND ")
+	}
+	args = append(args, f.Limit, f.Offset)
+	query += fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []AdminUserResponse{}
+	for rows.Next() {
+		var u AdminUserResponse
+		err := rows.Scan(&u.UUID, &u.Username, &u.Email, &u.Role, &u.SuspendedAt, &u.SuspendedReason, &u.UpdateAt)
+		if err != nil {
+			return users, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *AdminRepository) SuspendUser(m Moderation) error {
+	return r.moderate(m, `UPDATE app_user SET suspended_at = current_timestamp, suspended_reason = $2
+    WHERE uuid = $1 AND delete_at IS NULL`, m.TargetUUID, m.Reason)
+}
+
+func (r *AdminRepository) UnsuspendUser(m Moderation) error {
+	return r.moderate(m, `UPDATE app_user SET suspended_at = NULL, suspended_reason = NULL
+    WHERE uuid = $1 AND suspended_at IS NOT NULL`, m.TargetUUID)
+}
+
+func (r *AdminRepository) HidePost(m Moderation) error {
+	return r.hide(m, "post")
+}
+
+func (r *AdminRepository) RemovePost(m Moderation) error {
+	return r.moderate(m, `UPDATE post SET deleted_at = current_date
+    WHERE uuid = $1 AND deleted_at IS NULL`, m.TargetUUID)
+}
+
+func (r *AdminRepository) HideComment(m Moderation) error {
+	return r.hide(m, "comment")
+}
+
+// hide is idempotent, hiding content that already is hidden changes nothing
+// and writes no log entry. Only content that is gone is not found.
+func (r *AdminRepository) hide(m Moderation, table string) error {
+	err := r.moderate(m, fmt.Sprintf(`UPDATE %s SET hidden_at = current_timestamp, hidden_reason = $2
+    WHERE uuid = $1 AND deleted_at IS NULL AND hidden_at IS NULL`, table), m.TargetUUID, m.Reason)
+	if err != ErrTargetNotFound {
+		return err
+	}
+
+	var exists bool
+	err = r.db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE uuid = $1 AND deleted_at IS NULL)`, table),
+		m.TargetUUID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+func (r *AdminRepository) RemoveComment(m Moderation) error {
+	return r.moderate(m, `UPDATE comment SET deleted_at = current_date
+    WHERE uuid = $1 AND deleted_at IS NULL`, m.TargetUUID)
+}
+
+// moderate applies the change and writes its log entry in one transaction so
+// the log never records an action that did not happen, or misses one that did.
+func (r *AdminRepository) moderate(m Moderation, query string, args ...interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	numAffect, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numAffect == 0 {
+		return ErrTargetNotFound
+	}
+
+	if err := insertLog(tx, m); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertLog(tx *sql.Tx, m Moderation) error {
+	query := `INSERT INTO moderation_log (uuid, moderator_id, action, target_type, target_uuid, reason)
+    VALUES (
+        $1,
+        (SELECT id FROM app_user WHERE uuid = $2),
+        $3,
+        $4,
+        $5,
+        $6
+    )`
+
+	_, err := tx.Exec(query, uuid.NewString(), nullString(m.ModeratorUUID), m.Action,
+		m.TargetType, m.TargetUUID, nullString(m.Reason))
+	return err
+}
+
+func (r *AdminRepository) GetLogs(f LogFilter) ([]ModerationLog, error) {
+	var conds []string
+	var args []interface{}
+	if f.TargetType != "" {
+		args = append(args, f.TargetType)
+		conds = append(conds, fmt.Sprintf("l.target_type = $%d", len(args)))
+	}
+	if f.TargetUUID != "" {
+		args = append(args, f.TargetUUID)
+		conds = append(conds, fmt.Sprintf("l.target_uuid = $%d", len(args)))
+	}
+
+	query := `
+  SELECT l.uuid, u.uuid, u.username, l.action, l.target_type, l.target_uuid, l.reason, l.created_at
+  FROM moderation_log AS l
+  LEFT JOIN app_user AS u ON u.id = l.moderator_id`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, f.Limit, f.Offset)
+	query += fmt.Sprintf(" ORDER BY l.id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []ModerationLog{}
+	for rows.Next() {
+		var l ModerationLog
+		err := rows.Scan(&l.UUID, &l.ModeratorUUID, &l.ModeratorName, &l.Action, &l.TargetType, &l.TargetUUID, &l.Reason, &l.CreatedAt)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUsers(t *testing.T) {
+	updateAt := time.Now()
+	testTable := []struct {
+		title    string
+		filter   UserFilter
+		wantArgs int
+		want     []AdminUserResponse
+	}{
+		{
+			"should return users", UserFilter{Limit: 20}, 2,
+			[]AdminUserResponse{{UUID: "f6630558-b800-48ff-9a09-5863d6055154", Username: "ong", Role: "user", UpdateAt: updateAt}},
+		},
+		{
+			"should return users with filter", UserFilter{Username: "on", Role: "user", Status: StatusActive, Limit: 20}, 4,
+			[]AdminUserResponse{{UUID: "f6630558-b800-48ff-9a09-5863d6055154", Username: "ong", Role: "user", UpdateAt: updateAt}},
+		},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			args := make([]driver.Value, v.wantArgs)
+			for i := range args {
+				args[i] = sqlmock.AnyArg()
+			}
+			mock.ExpectQuery("SELECT uuid, username, email, role, suspended_at, suspended_reason, updated_at").
+				WithArgs(args...).
+				WillReturnRows(sqlmock.NewRows([]string{"uuid", "username", "email", "role", "suspended_at", "suspended_reason", "updated_at"}).
+					AddRow(v.want[0].UUID, v.want[0].Username, nil, v.want[0].Role, nil, nil, updateAt))
+
+			repo := NewAdminRepository(db)
+			users, err := repo.GetUsers(v.filter)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, users, "Want %v but got %v", v.want, users)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetUsers_EscapeWildcard(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery(`username ILIKE \$1 ESCAPE '\\'`).
+		WithArgs(`a\_b\%%`, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "username", "email", "role", "suspended_at", "suspended_reason", "updated_at"}))
+
+	users, err := NewAdminRepository(db).GetUsers(UserFilter{Username: "a_b%", Limit: 20})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Empty(t, users)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestSuspendUser(t *testing.T) {
+	m := Moderation{
+		ModeratorUUID: "0ee1abd0-a330-488d-b170-b33f58dd6178",
+		Action:        ActionSuspendUser,
+		TargetType:    TargetUser,
+		TargetUUID:    "f6630558-b800-48ff-9a09-5863d6055154",
+		Reason:        "spam",
+	}
+
+	t.Run("should suspend and write log", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE app_user SET suspended_at").WithArgs(m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO moderation_log").
+			WithArgs(sqlmock.AnyArg(), m.ModeratorUUID, m.Action, m.TargetType, m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		repo := NewAdminRepository(db)
+		err := repo.SuspendUser(m)
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not found and rollback", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE app_user SET suspended_at").WithArgs(m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := NewAdminRepository(db)
+		err := repo.SuspendUser(m)
+		assert.Equalf(t, ErrTargetNotFound, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHidePost(t *testing.T) {
+	m := Moderation{
+		Action:     ActionHidePost,
+		TargetType: TargetPost,
+		TargetUUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		Reason:     "auto-hidden after 3 reports",
+	}
+
+	t.Run("should hide and write log", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE post SET hidden_at (.+) AND hidden_at IS NULL").WithArgs(m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO moderation_log").
+			WithArgs(sqlmock.AnyArg(), nil, m.Action, m.TargetType, m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := NewAdminRepository(db).HidePost(m)
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should skip log when already hidden", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE post SET hidden_at").WithArgs(m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM post").WithArgs(m.TargetUUID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := NewAdminRepository(db).HidePost(m)
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not found when gone", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE post SET hidden_at").WithArgs(m.TargetUUID, m.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM post").WithArgs(m.TargetUUID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := NewAdminRepository(db).HidePost(m)
+		assert.Equalf(t, ErrTargetNotFound, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetLogs(t *testing.T) {
+	createdAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT l.uuid").WithArgs(TargetPost, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "uuid", "username", "action", "target_type", "target_uuid", "reason", "created_at"}).
+			AddRow("0ee1abd0-a330-488d-b170-b33f58dd6178", nil, nil, ActionHidePost, TargetPost, "f6630558-b800-48ff-9a09-5863d6055154", "spam", createdAt))
+
+	repo := NewAdminRepository(db)
+	logs, err := repo.GetLogs(LogFilter{TargetType: TargetPost, Limit: 20})
+	want := []ModerationLog{{
+		UUID:       "0ee1abd0-a330-488d-b170-b33f58dd6178",
+		Action:     ActionHidePost,
+		TargetType: TargetPost,
+		TargetUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+		Reason:     util.Ptr("spam"),
+		CreatedAt:  createdAt,
+	}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, logs, "Want %v but got %v", want, logs)
+}
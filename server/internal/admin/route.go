@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/middleware"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/gorilla/mux"
+)
+
+type IAdminHandler interface {
+	GetUsers(http.ResponseWriter, *http.Request)
+	SuspendUser(http.ResponseWriter, *http.Request)
+	UnsuspendUser(http.ResponseWriter, *http.Request)
+	HidePost(http.ResponseWriter, *http.Request)
+	RemovePost(http.ResponseWriter, *http.Request)
+	HideComment(http.ResponseWriter, *http.Request)
+	RemoveComment(http.ResponseWriter, *http.Request)
+	GetLogs(http.ResponseWriter, *http.Request)
+}
+
+func RegisterAdminRouter(router *mux.Router, adminHandler IAdminHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/admin").Subrouter()
+	srouter.Use(authMiddleware, middleware.RequireRole(user.RoleAdmin))
+
+	srouter.HandleFunc("/users", adminHandler.GetUsers).Methods(http.MethodGet)
+	srouter.HandleFunc("/users/{uuid}/suspend", adminHandler.SuspendUser).Methods(http.MethodPost)
+	srouter.HandleFunc("/users/{uuid}/unsuspend", adminHandler.UnsuspendUser).Methods(http.MethodPost)
+	srouter.HandleFunc("/posts/{uuid}/hide", adminHandler.HidePost).Methods(http.MethodPost)
+	srouter.HandleFunc("/posts/{uuid}/remove", adminHandler.RemovePost).Methods(http.MethodPost)
+	srouter.HandleFunc("/comments/{uuid}/hide", adminHandler.HideComment).Methods(http.MethodPost)
+	srouter.HandleFunc("/comments/{uuid}/remove", adminHandler.RemoveComment).Methods(http.MethodPost)
+	srouter.HandleFunc("/logs", adminHandler.GetLogs).Methods(http.MethodGet)
+}
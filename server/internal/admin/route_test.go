@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) GetUsers(http.ResponseWriter, *http.Request) {
+	m.called["GetUsers"] = true
+}
+
+func (m *MockHandler) SuspendUser(http.ResponseWriter, *http.Request) {
+	m.called["SuspendUser"] = true
+}
+
+func (m *MockHandler) UnsuspendUser(http.ResponseWriter, *http.Request) {
+	m.called["UnsuspendUser"] = true
+}
+
+func (m *MockHandler) HidePost(http.ResponseWriter, *http.Request) {
+	m.called["HidePost"] = true
+}
+
+func (m *MockHandler) RemovePost(http.ResponseWriter, *http.Request) {
+	m.called["RemovePost"] = true
+}
+
+func (m *MockHandler) HideComment(http.ResponseWriter, *http.Request) {
+	m.called["HideComment"] = true
+}
+
+func (m *MockHandler) RemoveComment(http.ResponseWriter, *http.Request) {
+	m.called["RemoveComment"] = true
+}
+
+func (m *MockHandler) GetLogs(http.ResponseWriter, *http.Request) {
+	m.called["GetLogs"] = true
+}
+
+func fakeAuth(role string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "userRole", role)))
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	uuid := "f6630558-b800-48ff-9a09-5863d6055154"
+	routes := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/admin/users", "GetUsers"},
+		{http.MethodPost, "/admin/users/" + uuid + "/suspend", "SuspendUser"},
+		{http.MethodPost, "/admin/users/" + uuid + "/unsuspend", "UnsuspendUser"},
+		{http.MethodPost, "/admin/posts/" + uuid + "/hide", "HidePost"},
+		{http.MethodPost, "/admin/posts/" + uuid + "/remove", "RemovePost"},
+		{http.MethodPost, "/admin/comments/" + uuid + "/hide", "HideComment"},
+		{http.MethodPost, "/admin/comments/" + uuid + "/remove", "RemoveComment"},
+		{http.MethodGet, "/admin/logs", "GetLogs"},
+	}
+
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterAdminRouter(router, &mHandler, fakeAuth(user.RoleAdmin))
+
+	for _, v := range routes {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.handler], "%v not called", v.handler)
+	}
+}
+
+func TestRoute_Forbidden(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterAdminRouter(router, &mHandler, fakeAuth(user.RoleUser))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, mHandler.called["GetUsers"], "get users should not called")
+}
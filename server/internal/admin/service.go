@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/dsypasit/social-clone/server/internal/user"
+)
+
+var (
+	ErrReasonRequired = errors.New("reason is required")
+	ErrSelfModeration = errors.New("cannot moderate yourself")
+	ErrInvalidFilter  = errors.New("invalid filter")
+)
+
+type IAdminRepository interface {
+	GetUsers(UserFilter) ([]AdminUserResponse, error)
+	SuspendUser(Moderation) error
+	UnsuspendUser(Moderation) error
+	HidePost(Moderation) error
+	RemovePost(Moderation) error
+	HideComment(Moderation) error
+	RemoveComment(Moderation) error
+	GetLogs(LogFilter) ([]ModerationLog, error)
+}
+
+type AdminService struct {
+	adminRepo IAdminRepository
+}
+
+func NewAdminService(adminRepo IAdminRepository) *AdminService {
+	return &AdminService{adminRepo}
+}
+
+func (s *AdminService) GetUsers(f UserFilter) ([]AdminUserResponse, error) {
+	if f.Status != "" && f.Status != StatusActive && f.Status != StatusSuspended {
+		return nil, ErrInvalidFilter
+	}
+	if f.Role != "" && f.Role != user.RoleUser && f.Role != user.RoleAdmin {
+		return nil, ErrInvalidFilter
+	}
+	return s.adminRepo.GetUsers(f)
+}
+
+func (s *AdminService) SuspendUser(moderatorUUID, userUUID, reason string) error {
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	if moderatorUUID == userUUID {
+		return ErrSelfModeration
+	}
+	return s.adminRepo.SuspendUser(Moderation{
+		ModeratorUUID: moderatorUUID,
+		Action:        ActionSuspendUser,
+		TargetType:    TargetUser,
+		TargetUUID:    userUUID,
+		Reason:        reason,
+	})
+}
+
+func (s *AdminService) UnsuspendUser(moderatorUUID, userUUID, reason string) error {
+	return s.adminRepo.UnsuspendUser(Moderation{
+		ModeratorUUID: moderatorUUID,
+		Action:        ActionUnsuspendUser,
+		TargetType:    TargetUser,
+		TargetUUID:    userUUID,
+		Reason:        reason,
+	})
+}
+
+func (s *AdminService) HidePost(moderatorUUID, postUUID, reason string) error {
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	return s.adminRepo.HidePost(Moderation{
+		ModeratorUUID: moderatorUUID,
+		Action:        ActionHidePost,
+		TargetType:    TargetPost,
+		TargetUUID:    postUUID,
+		Reason:        reason,
+	})
+}
+
+func (s *AdminService) RemovePost(moderatorUUID, postUUID, reason string) error {
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	return s.adminRepo.RemovePost(Moderation{
+		ModeratorUUID: moderatorUUID,
+		Action:        ActionRemovePost,
+		TargetType:    TargetPost,
+		TargetUUID:    postUUID,
+		Reason:        reason,
+	})
+}
+
+func (s *AdminService) HideComment(moderatorUUID, commentUUID, reason string) error {
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	return s.adminRepo.HideComment(Moderation{
+		ModeratorUUID: moderatorUUID,
+		Action:        ActionHideComment,
+		TargetType:    TargetComment,
+		TargetUUID:    commentUUID,
+		Reason:        reason,
+	})
+}
+
+func (s *AdminService) RemoveComment(moderatorUUID, commentUUID, reason string) error {
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	return s.adminRepo.RemoveComment(Moderation{
+		ModeratorUUID: moderatorUUID,
+		Action:        ActionRemoveComment,
+		TargetType:    TargetComment,
+		TargetUUID:    commentUUID,
+		Reason:        reason,
+	})
+}
+
+func (s *AdminService) GetLogs(f LogFilter) ([]ModerationLog, error) {
+	if f.TargetType != "" && f.TargetType != TargetUser && f.TargetType != TargetPost && f.TargetType != TargetComment {
+		return nil, ErrInvalidFilter
+	}
+	return s.adminRepo.GetLogs(f)
+}
@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	repoErr    error
+	users      []AdminUserResponse
+	logs       []ModerationLog
+	moderation Moderation
+}
+
+func (m *MockRepo) GetUsers(UserFilter) ([]AdminUserResponse, error) {
+	return m.users, m.repoErr
+}
+
+func (m *MockRepo) SuspendUser(mod Moderation) error {
+	m.moderation = mod
+	return m.repoErr
+}
+
+func (m *MockRepo) UnsuspendUser(mod Moderation) error {
+	m.moderation = mod
+	return m.repoErr
+}
+
+func (m *MockRepo) HidePost(mod Moderation) error {
+	m.moderation = mod
+	return m.repoErr
+}
+
+func (m *MockRepo) RemovePost(mod Moderation) error {
+	m.moderation = mod
+	return m.repoErr
+}
+
+func (m *MockRepo) HideComment(mod Moderation) error {
+	m.moderation = mod
+	return m.repoErr
+}
+
+func (m *MockRepo) RemoveComment(mod Moderation) error {
+	m.moderation = mod
+	return m.repoErr
+}
+
+func (m *MockRepo) GetLogs(LogFilter) ([]ModerationLog, error) {
+	return m.logs, m.repoErr
+}
+
+func TestServiceGetUsers(t *testing.T) {
+	testTable := []struct {
+		title   string
+		filter  UserFilter
+		wantErr error
+	}{
+		{"should return users", UserFilter{Status: StatusSuspended, Role: "admin"}, nil},
+		{"should invalid status", UserFilter{Status: "deleted"}, ErrInvalidFilter},
+		{"should invalid role", UserFilter{Role: "root"}, ErrInvalidFilter},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewAdminService(&MockRepo{})
+			_, err := s.GetUsers(v.filter)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceSuspendUser(t *testing.T) {
+	testTable := []struct {
+		title     string
+		moderator string
+		target    string
+		reason    string
+		wantErr   error
+	}{
+		{"should suspend user", "0ee1abd0-a330-488d-b170-b33f58dd6178", "f6630558-b800-48ff-9a09-5863d6055154", "spam", nil},
+		{"should require reason", "0ee1abd0-a330-488d-b170-b33f58dd6178", "f6630558-b800-48ff-9a09-5863d6055154", "", ErrReasonRequired},
+		{"should not suspend yourself", "0ee1abd0-a330-488d-b170-b33f58dd6178", "0ee1abd0-a330-488d-b170-b33f58dd6178", "spam", ErrSelfModeration},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			m := MockRepo{}
+			s := NewAdminService(&m)
+			err := s.SuspendUser(v.moderator, v.target, v.reason)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			if v.wantErr == nil {
+				want := Moderation{v.moderator, ActionSuspendUser, TargetUser, v.target, v.reason}
+				assert.Equalf(t, want, m.moderation, "Want %v but got %v", want, m.moderation)
+			}
+		})
+	}
+}
+
+func TestServiceHidePost(t *testing.T) {
+	testTable := []struct {
+		title   string
+		reason  string
+		repoErr error
+		wantErr error
+	}{
+		{"should hide post", "spam", nil, nil},
+		{"should require reason", "", nil, ErrReasonRequired},
+		{"should return not found", "spam", ErrTargetNotFound, ErrTargetNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			m := MockRepo{repoErr: v.repoErr}
+			s := NewAdminService(&m)
+			err := s.HidePost("0ee1abd0-a330-488d-b170-b33f58dd6178", "f6630558-b800-48ff-9a09-5863d6055154", v.reason)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/dsypasit/social-clone/server/internal/user"
@@ -12,6 +11,7 @@ import (
 
 type AuthHandler struct {
 	authService AuthServiceInterface
+	session     SessionOptions
 }
 
 type AuthServiceInterface interface {
@@ -20,40 +20,81 @@ type AuthServiceInterface interface {
 	CheckToken(token string) bool
 }
 
-func NewAuthHandler(authService AuthServiceInterface) *AuthHandler {
-	return &AuthHandler{authService}
+func NewAuthHandler(authService AuthServiceInterface, session SessionOptions) *AuthHandler {
+	return &AuthHandler{authService, session}
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.login(w, r)
+	if !ok {
+		return
+	}
+	util.SendJson(w, map[string]string{"token": token}, http.StatusOK)
+}
+
+// CreateSession logs in like Login but keeps the token in an HttpOnly
+// cookie, answering only the CSRF token to echo in CSRFHeader.
+func (h *AuthHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.login(w, r)
+	if !ok {
+		return
+	}
+	csrf, err := h.session.setSession(w, token)
+	if err != nil {
+		util.SendJson(w, map[string]string{"error": err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	util.SendJson(w, map[string]string{"csrfToken": csrf}, http.StatusOK)
+}
+
+// DeleteSession clears the session cookies. It is reached with the cookie
+// alone, so it needs the CSRF header like any cookie authenticated change.
+func (h *AuthHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	if err := CheckCSRF(r); err != nil {
+		util.SendJson(w, map[string]string{"message": "invalid csrf token"}, http.StatusForbidden)
+		return
+	}
+	h.session.clearSession(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// login writes the error response itself and reports whether it got a
+// token.
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) (string, bool) {
 	var loginedUser User
 	if err := json.NewDecoder(r.Body).Decode(&loginedUser); err != nil {
 		util.SendJson(w, map[string]string{"message": "invalid structure format"}, http.StatusBadRequest)
-		return
+		return "", false
 	}
 	var empty User
 	if loginedUser == empty {
 		util.SendJson(w, map[string]string{"message": "invalid structure format"}, http.StatusBadRequest)
-		return
+		return "", false
 	}
 	if loginedUser.Username == "" || loginedUser.Password == "" {
 		util.SendJson(w, map[string]string{"message": "username or password empty or invalid email format"}, http.StatusBadRequest)
-		return
+		return "", false
 	}
 	token, err := h.authService.Login(loginedUser)
 	if err != nil {
 		if err == ErrInvalidPassword {
 			util.SendJson(w, map[string]string{"message": "invalid password"}, http.StatusBadRequest)
-			return
+			return "", false
 		}
 
 		if err == ErrUserNotFound {
 			util.SendJson(w, map[string]string{"message": "user not found"}, http.StatusBadRequest)
-			return
+			return "", false
+		}
+
+		if err == ErrUserSuspended {
+			util.SendJson(w, map[string]string{"message": "account suspended"}, http.StatusForbidden)
+			return "", false
 		}
 		util.SendJson(w, map[string]string{"error": err.Error()}, http.StatusInternalServerError)
-		return
+		return "", false
 	}
-	util.SendJson(w, map[string]string{"token": token}, http.StatusOK)
+	return token, true
 }
 
 func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
@@ -77,6 +118,10 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 			util.SendJson(w, map[string]string{"message": "duplicate username"}, http.StatusBadRequest)
 			return
 		}
+		if err == user.ErrInvalidUsername || err == util.ErrTextEmpty || err == util.ErrTextTooLong || err == util.ErrZeroWidthAbuse {
+			util.SendJson(w, map[string]string{"message": "invalid username"}, http.StatusBadRequest)
+			return
+		}
 		util.SendJson(w, map[string]string{"message": fmt.Sprintf("%v", err)}, http.StatusInternalServerError)
 		return
 	}
@@ -84,26 +129,15 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandler) CheckToken(w http.ResponseWriter, r *http.Request) {
-	authValue := r.Header.Get("Authorization")
-	if authValue == "" {
-		w.WriteHeader(http.StatusUnauthorized)
-
-		json.NewEncoder(w).Encode(map[string]string{
-			"message": "Missing authorization token",
-		})
-		return
-	}
-
-	parts := strings.SplitN(authValue, "Bearer ", 2)
-	if len(parts) != 2 {
-		w.WriteHeader(http.StatusUnauthorized)
-
-		json.NewEncoder(w).Encode(map[string]string{
-			"message": "Invalid authorization format",
-		})
+	token, _, err := TokenFromRequest(r)
+	if err != nil {
+		message := "Missing authorization token"
+		if err == ErrInvalidAuthFormat {
+			message = "Invalid authorization format"
+		}
+		util.SendJson(w, map[string]string{"message": message}, http.StatusUnauthorized)
 		return
 	}
-	token := parts[1]
 
 	if !h.authService.CheckToken(token) {
 		res := util.BuildResponse("Unauthorized")
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/dsypasit/social-clone/server/internal/user"
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,8 @@ func (m *MockAuthService) Login(u User) (string, error) {
 	return "token", nil
 }
 
+var testSession = SessionOptions{Secure: true, SameSite: http.SameSiteLaxMode, MaxAge: time.Hour}
+
 func TestSignup_InvalidFormat(t *testing.T) {
 	testTable := []struct {
 		title      string
@@ -60,7 +63,7 @@ func TestSignup_InvalidFormat(t *testing.T) {
 			rec := httptest.NewRecorder()
 
 			mockService := MockAuthService{}
-			authHandler := NewAuthHandler(&mockService)
+			authHandler := NewAuthHandler(&mockService, testSession)
 			authHandler.Signup(rec, req)
 
 			expected := map[string]string{"message": "invalid structure format"}
@@ -85,6 +88,7 @@ func TestSignup_ServiceNotWorking(t *testing.T) {
 	}{
 		{"should internal error cause service not working", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\", \"email\": \"a@gmail.com\"}")), errors.New("error"), http.StatusInternalServerError, map[string]string{"message": "error"}},
 		{"should bad request cause duplicate user", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"asdf\", \"email\":\"a@gmail.com\"}")), user.ErrDupUsername, http.StatusBadRequest, map[string]string{"message": "duplicate username"}},
+		{"should bad request cause invalid username", bytes.NewReader([]byte("{\"username\":\"a.b\", \"password\":\"asdf\", \"email\":\"a@gmail.com\"}")), user.ErrInvalidUsername, http.StatusBadRequest, map[string]string{"message": "invalid username"}},
 		{"should get token", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abcd\", \"email\": \"a@gmail.com\"}")), nil, http.StatusCreated, map[string]string{"token": "token"}},
 	}
 
@@ -96,7 +100,7 @@ func TestSignup_ServiceNotWorking(t *testing.T) {
 			rec := httptest.NewRecorder()
 
 			mockService := MockAuthService{User{}, v.serviceErr}
-			authHandler := NewAuthHandler(&mockService)
+			authHandler := NewAuthHandler(&mockService, testSession)
 			authHandler.Signup(rec, req)
 
 			expected := map[string]string{"message": "invalid structure format"}
@@ -132,7 +136,7 @@ func TestLogin_InvalidFormat(t *testing.T) {
 			rec := httptest.NewRecorder()
 
 			mockService := MockAuthService{}
-			authHandler := NewAuthHandler(&mockService)
+			authHandler := NewAuthHandler(&mockService, testSession)
 			authHandler.Login(rec, req)
 
 			expected := map[string]string{"message": "invalid structure format"}
@@ -159,6 +163,7 @@ func TestLogin_Service(t *testing.T) {
 		{"should bad request cause password invalid", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\"}")), nil, User{Password: "abcd"}, http.StatusBadRequest, map[string]string{"message": "invalid password"}},
 		{"should internal error cause service not working", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\", \"email\":\"a@gmail.com\"}")), errors.New("error"), User{Password: "abc"}, http.StatusInternalServerError, map[string]string{"error": "error"}},
 		{"should get token", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\", \"email\":\"a@gmail.com\"}")), nil, User{Password: "abc"}, http.StatusOK, map[string]string{"token": "token"}},
+		{"should forbidden cause account suspended", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\"}")), ErrUserSuspended, User{Password: "abc"}, http.StatusForbidden, map[string]string{"message": "account suspended"}},
 	}
 
 	for _, v := range testTable {
@@ -169,7 +174,7 @@ func TestLogin_Service(t *testing.T) {
 			rec := httptest.NewRecorder()
 
 			mockService := MockAuthService{v.initialUser, v.isErr}
-			authHandler := NewAuthHandler(&mockService)
+			authHandler := NewAuthHandler(&mockService, testSession)
 			authHandler.Login(rec, req)
 
 			expected := map[string]string{"message": "invalid structure format"}
@@ -184,10 +189,101 @@ func TestLogin_Service(t *testing.T) {
 	}
 }
 
+func TestCreateSession(t *testing.T) {
+	t.Run("should set session and csrf cookies", func(t *testing.T) {
+		mService := MockAuthService{user: User{Password: "abc"}}
+		aHandler := NewAuthHandler(&mService, testSession)
+
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\"}")))
+		rec := httptest.NewRecorder()
+		aHandler.CreateSession(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.NotContains(t, body, "token")
+
+		cookies := map[string]*http.Cookie{}
+		for _, c := range rec.Result().Cookies() {
+			cookies[c.Name] = c
+		}
+		session, csrf := cookies[SessionCookie], cookies[CSRFCookie]
+		if assert.NotNil(t, session) && assert.NotNil(t, csrf) {
+			assert.Equal(t, "token", session.Value)
+			assert.True(t, session.HttpOnly)
+			assert.True(t, session.Secure)
+			assert.Equal(t, http.SameSiteLaxMode, session.SameSite)
+			assert.Equal(t, 3600, session.MaxAge)
+			assert.False(t, csrf.HttpOnly)
+			assert.NotEmpty(t, csrf.Value)
+			assert.Equal(t, csrf.Value, body["csrfToken"])
+		}
+	})
+
+	t.Run("should not set cookies when login fails", func(t *testing.T) {
+		mService := MockAuthService{user: User{Password: "abcd"}}
+		aHandler := NewAuthHandler(&mService, testSession)
+
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{\"username\":\"abc\", \"password\":\"abc\"}")))
+		rec := httptest.NewRecorder()
+		aHandler.CreateSession(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, rec.Result().Cookies())
+	})
+}
+
+func TestDeleteSession(t *testing.T) {
+	aHandler := NewAuthHandler(&MockAuthService{}, testSession)
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookie, Value: "token"})
+	req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "csrf"})
+	req.Header.Set(CSRFHeader, "csrf")
+	rec := httptest.NewRecorder()
+	aHandler.DeleteSession(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 2)
+	for _, c := range cookies {
+		assert.Empty(t, c.Value)
+		assert.Negative(t, c.MaxAge)
+	}
+}
+
+func TestDeleteSession_CSRF(t *testing.T) {
+	testTable := []struct {
+		title  string
+		header string
+	}{
+		{"should forbid missing header", ""},
+		{"should forbid mismatched header", "other"},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			aHandler := NewAuthHandler(&MockAuthService{}, testSession)
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.AddCookie(&http.Cookie{Name: SessionCookie, Value: "token"})
+			req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "csrf"})
+			if v.header != "" {
+				req.Header.Set(CSRFHeader, v.header)
+			}
+			rec := httptest.NewRecorder()
+			aHandler.DeleteSession(rec, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Empty(t, rec.Result().Cookies(), "the session must survive")
+			assert.JSONEq(t, `{"message":"invalid csrf token"}`, rec.Body.String())
+		})
+	}
+}
+
 func TestHandlerCheckToken(t *testing.T) {
 	t.Run("Should return no content status", func(t *testing.T) {
 		mService := MockAuthService{isErr: nil}
-		aHandler := NewAuthHandler(&mService)
+		aHandler := NewAuthHandler(&mService, testSession)
 
 		req, _ := http.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Add("Authorization", "Bearer validtoken")
@@ -201,7 +297,7 @@ func TestHandlerCheckToken(t *testing.T) {
 
 	t.Run("should return unautorized", func(t *testing.T) {
 		mService := MockAuthService{isErr: errors.New("")}
-		aHandler := NewAuthHandler(&mService)
+		aHandler := NewAuthHandler(&mService, testSession)
 
 		req, _ := http.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Add("Authorization", "Bearer invalid")
@@ -213,9 +309,23 @@ func TestHandlerCheckToken(t *testing.T) {
 		assert.Equalf(t, wantStatus, rec.Code, "Want %v but got %v", wantStatus, rec.Code)
 	})
 
+	t.Run("should accept session cookie", func(t *testing.T) {
+		mService := MockAuthService{isErr: nil}
+		aHandler := NewAuthHandler(&mService, testSession)
+
+		req, _ := http.NewRequest(http.MethodPost, "/", nil)
+		req.AddCookie(&http.Cookie{Name: SessionCookie, Value: "validtoken"})
+
+		rec := httptest.NewRecorder()
+		aHandler.CheckToken(rec, req)
+
+		wantStatus := http.StatusNoContent
+		assert.Equalf(t, wantStatus, rec.Code, "Want %v but got %v", wantStatus, rec.Code)
+	})
+
 	t.Run("should return unautorized cause invalid header", func(t *testing.T) {
 		mService := MockAuthService{isErr: nil}
-		aHandler := NewAuthHandler(&mService)
+		aHandler := NewAuthHandler(&mService, testSession)
 
 		req, _ := http.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Add("Authorization", "")
@@ -10,6 +10,8 @@ type AuthHandlerInterface interface {
 	Login(http.ResponseWriter, *http.Request)
 	Signup(http.ResponseWriter, *http.Request)
 	CheckToken(http.ResponseWriter, *http.Request)
+	CreateSession(http.ResponseWriter, *http.Request)
+	DeleteSession(http.ResponseWriter, *http.Request)
 }
 
 func RegisterAuthRouter(router *mux.Router, authHandler AuthHandlerInterface) {
@@ -17,4 +19,6 @@ func RegisterAuthRouter(router *mux.Router, authHandler AuthHandlerInterface) {
 	authRouter.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
 	authRouter.HandleFunc("/signup", authHandler.Signup).Methods(http.MethodPost)
 	authRouter.HandleFunc("/checktoken", authHandler.CheckToken).Methods(http.MethodPost)
+	authRouter.HandleFunc("/session", authHandler.CreateSession).Methods(http.MethodPost)
+	authRouter.HandleFunc("/session", authHandler.DeleteSession).Methods(http.MethodDelete)
 }
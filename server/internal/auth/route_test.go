@@ -13,6 +13,8 @@ type MockHandler struct {
 	signupCalled bool
 	loginCalled  bool
 	tokenCalled  bool
+	createCalled bool
+	deleteCalled bool
 }
 
 func (m *MockHandler) Login(http.ResponseWriter, *http.Request) {
@@ -27,9 +29,17 @@ func (m *MockHandler) CheckToken(http.ResponseWriter, *http.Request) {
 	m.tokenCalled = true
 }
 
+func (m *MockHandler) CreateSession(http.ResponseWriter, *http.Request) {
+	m.createCalled = true
+}
+
+func (m *MockHandler) DeleteSession(http.ResponseWriter, *http.Request) {
+	m.deleteCalled = true
+}
+
 func TestRoute(t *testing.T) {
 	router := mux.NewRouter()
-	authHandler := MockHandler{false, false, false}
+	authHandler := MockHandler{}
 	RegisterAuthRouter(router, &authHandler)
 
 	// Test signup route
@@ -43,4 +53,10 @@ func TestRoute(t *testing.T) {
 	// Test check token route
 	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/auth/checktoken", nil))
 	assert.True(t, authHandler.tokenCalled, "login handler not called")
+
+	// Test session routes
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/auth/session", nil))
+	assert.True(t, authHandler.createCalled, "create session handler not called")
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/auth/session", nil))
+	assert.True(t, authHandler.deleteCalled, "delete session handler not called")
 }
@@ -12,12 +12,14 @@ import (
 var (
 	ErrInvalidPassword = errors.New("invalid password")
 	ErrUserNotFound    = errors.New("username not found")
+	ErrUserSuspended   = errors.New("account suspended")
 )
 
 type UserServiceForAuth interface {
 	CreateUser(user.UserCreated) (int64, error)
 	GetPasswordByUsername(string) (string, error)
 	GetUserUUIDByUsername(string) (string, error)
+	GetUserByUUID(string) (user.User, error)
 }
 
 type JwtServiceInterface interface {
@@ -34,7 +36,12 @@ func NewAuthService(usrService UserServiceForAuth, jwtService *JwtService) *Auth
 	return &AuthService{usrService: usrService, jwtService: jwtService}
 }
 
+// Signup and Login look the user up by the sanitized username, the one
+// CreateUser stores, and leave rejecting a bad one to CreateUser.
 func (as *AuthService) Signup(u user.UserCreated) (string, error) {
+	if username, err := user.UsernameRule.Sanitize(u.Username); err == nil {
+		u.Username = username
+	}
 	_, err := as.usrService.CreateUser(u)
 	if err != nil {
 		return "", err
@@ -47,6 +54,9 @@ func (as *AuthService) Signup(u user.UserCreated) (string, error) {
 }
 
 func (as *AuthService) Login(u User) (string, error) {
+	if username, err := user.UsernameRule.Sanitize(u.Username); err == nil {
+		u.Username = username
+	}
 	pass, err := as.usrService.GetPasswordByUsername(u.Username)
 	if err != nil {
 		if err == user.ErrUserNotFound {
@@ -64,6 +74,14 @@ func (as *AuthService) Login(u User) (string, error) {
 		return "", err
 	}
 
+	usr, err := as.usrService.GetUserByUUID(uuid)
+	if err != nil {
+		return "", err
+	}
+	if usr.IsSuspended() {
+		return "", ErrUserSuspended
+	}
+
 	return as.jwtService.GenerateToken(uuid)
 }
 
@@ -85,6 +103,10 @@ func NewJwtServiceWithExpireDuration(secretKey string, expiresDuration time.Dura
 	return &JwtService{secretKey: secretKey, expiresDuration: expiresDuration}
 }
 
+func (jService *JwtService) ExpiresDuration() time.Duration {
+	return jService.expiresDuration
+}
+
 func (jService *JwtService) GenerateToken(userUUID string) (string, error) {
 	claims := AuthJWTClaim{userUUID, jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(jService.expiresDuration))}}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims, nil)
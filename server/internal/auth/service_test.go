@@ -3,6 +3,7 @@ package auth
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/dsypasit/social-clone/server/internal/user"
@@ -28,6 +29,19 @@ func (us *MockUserService) GetUserUUIDByUsername(s string) (string, error) {
 	return "b3c5d2af-5cd3-4164-979d-1dcc705411bc", nil
 }
 
+func (us *MockUserService) GetUserByUUID(s string) (user.User, error) {
+	return user.User{UUID: s, Role: user.RoleUser}, nil
+}
+
+type MockSuspendedUserService struct {
+	MockUserService
+}
+
+func (us *MockSuspendedUserService) GetUserByUUID(s string) (user.User, error) {
+	suspendedAt := time.Now()
+	return user.User{UUID: s, Role: user.RoleUser, SuspendedAt: &suspendedAt}, nil
+}
+
 func TestJwtService(t *testing.T) {
 	t.Run("jwt service should generate token", func(t *testing.T) {
 		jwtService := NewJwtService(secretKey)
@@ -101,6 +115,22 @@ func TestJwtLogin(t *testing.T) {
 		assert.NotNil(t, err, "err should be nil")
 		assert.Equal(t, ErrInvalidPassword, err, "err should be invalid password")
 	})
+
+	t.Run("should got account suspended", func(t *testing.T) {
+		loginedUser := User{
+			Username: "ong",
+		}
+		loginedUser.Password, _ = util.GeneratePassword("1234")
+
+		userService := MockSuspendedUserService{MockUserService{nil, loginedUser}}
+		jwtService := NewJwtService(secretKey)
+		authService := NewAuthService(&userService, jwtService)
+
+		loginedUser.Password = "1234"
+		_, err := authService.Login(loginedUser)
+
+		assert.Equal(t, ErrUserSuspended, err, "err should be account suspended")
+	})
 }
 
 func TestCheckToken(t *testing.T) {
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// A session keeps the token in an HttpOnly cookie the page script cannot
+// read. Browsers send it on their own, so state changing requests must
+// echo the script readable CSRF cookie in CSRFHeader, a cross site form
+// cannot read the cookie to do so.
+const (
+	SessionCookie = "session"
+	CSRFCookie    = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+)
+
+var (
+	ErrMissingToken      = errors.New("missing authorization token")
+	ErrInvalidAuthFormat = errors.New("invalid authorization format")
+	ErrInvalidCSRFToken  = errors.New("invalid csrf token")
+)
+
+type SessionOptions struct {
+	Secure   bool
+	SameSite http.SameSite
+	Domain   string
+	MaxAge   time.Duration
+}
+
+// ParseSameSite maps "strict", "lax" and "none", anything else is lax.
+func ParseSameSite(v string) http.SameSite {
+	switch strings.ToLower(v) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// TokenFromRequest takes the bearer token, or the session cookie when there
+// is no Authorization header. fromCookie tells the caller to check CSRF.
+func TokenFromRequest(r *http.Request) (token string, fromCookie bool, err error) {
+	if authValue := r.Header.Get("Authorization"); authValue != "" {
+		token, ok := strings.CutPrefix(authValue, "Bearer ")
+		if !ok || token == "" {
+			return "", false, ErrInvalidAuthFormat
+		}
+		return token, false, nil
+	}
+	if c, err := r.Cookie(SessionCookie); err == nil && c.Value != "" {
+		return c.Value, true, nil
+	}
+	return "", false, ErrMissingToken
+}
+
+// CheckCSRF passes safe methods, others need CSRFHeader to match the CSRF
+// cookie.
+func CheckCSRF(r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+	c, err := r.Cookie(CSRFCookie)
+	header := r.Header.Get(CSRFHeader)
+	if err != nil || c.Value == "" || subtle.ConstantTimeCompare([]byte(c.Value), []byte(header)) != 1 {
+		return ErrInvalidCSRFToken
+	}
+	return nil
+}
+
+// setSession sets the session and a fresh CSRF cookie, returning the CSRF
+// token.
+func (o SessionOptions) setSession(w http.ResponseWriter, token string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	csrf := base64.RawURLEncoding.EncodeToString(b)
+
+	maxAge := int(o.MaxAge.Seconds())
+	http.SetCookie(w, o.cookie(SessionCookie, token, maxAge, true))
+	http.SetCookie(w, o.cookie(CSRFCookie, csrf, maxAge, false))
+	return csrf, nil
+}
+
+func (o SessionOptions) clearSession(w http.ResponseWriter) {
+	http.SetCookie(w, o.cookie(SessionCookie, "", -1, true))
+	http.SetCookie(w, o.cookie(CSRFCookie, "", -1, false))
+}
+
+func (o SessionOptions) cookie(name, value string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   o.Domain,
+		MaxAge:   maxAge,
+		Secure:   o.Secure,
+		HttpOnly: httpOnly,
+		SameSite: o.SameSite,
+	}
+}
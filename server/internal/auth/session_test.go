@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenFromRequest(t *testing.T) {
+	testTable := []struct {
+		title      string
+		header     string
+		cookie     string
+		wantToken  string
+		wantCookie bool
+		wantErr    error
+	}{
+		{"should take bearer token", "Bearer abc", "", "abc", false, nil},
+		{"should prefer header over cookie", "Bearer abc", "def", "abc", false, nil},
+		{"should take session cookie", "", "def", "def", true, nil},
+		{"should reject invalid header", "Basic abc", "def", "", false, ErrInvalidAuthFormat},
+		{"should reject empty bearer", "Bearer ", "", "", false, ErrInvalidAuthFormat},
+		{"should reject missing token", "", "", "", false, ErrMissingToken},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if v.header != "" {
+				req.Header.Set("Authorization", v.header)
+			}
+			if v.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: SessionCookie, Value: v.cookie})
+			}
+
+			token, fromCookie, err := TokenFromRequest(req)
+			assert.Equal(t, v.wantErr, err)
+			assert.Equal(t, v.wantToken, token)
+			assert.Equal(t, v.wantCookie, fromCookie)
+		})
+	}
+}
+
+func TestCheckCSRF(t *testing.T) {
+	testTable := []struct {
+		title   string
+		method  string
+		cookie  string
+		header  string
+		wantErr error
+	}{
+		{"should pass safe method", http.MethodGet, "", "", nil},
+		{"should pass matching token", http.MethodPost, "abc", "abc", nil},
+		{"should reject missing header", http.MethodPost, "abc", "", ErrInvalidCSRFToken},
+		{"should reject missing cookie", http.MethodDelete, "", "abc", ErrInvalidCSRFToken},
+		{"should reject both empty", http.MethodPatch, "", "", ErrInvalidCSRFToken},
+		{"should reject mismatch", http.MethodPut, "abc", "abd", ErrInvalidCSRFToken},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			req := httptest.NewRequest(v.method, "/", nil)
+			if v.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: v.cookie})
+			}
+			if v.header != "" {
+				req.Header.Set(CSRFHeader, v.header)
+			}
+			assert.Equal(t, v.wantErr, CheckCSRF(req))
+		})
+	}
+}
+
+func TestParseSameSite(t *testing.T) {
+	assert.Equal(t, http.SameSiteStrictMode, ParseSameSite("Strict"))
+	assert.Equal(t, http.SameSiteNoneMode, ParseSameSite("none"))
+	assert.Equal(t, http.SameSiteLaxMode, ParseSameSite("lax"))
+	assert.Equal(t, http.SameSiteLaxMode, ParseSameSite(""))
+}
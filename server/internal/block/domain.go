@@ -0,0 +1,9 @@
+package block
+
+import "time"
+
+type Relation struct {
+	UserUUID  string    `json:"user_uuid"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,99 @@
+package block
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IBlockService interface {
+	Block(blockerUUID, blockedUUID string) error
+	Unblock(blockerUUID, blockedUUID string) error
+	Mute(muterUUID, mutedUUID string) error
+	Unmute(muterUUID, mutedUUID string) error
+	GetBlocked(userUUID string) ([]Relation, error)
+	GetMuted(userUUID string) ([]Relation, error)
+}
+
+type BlockHandler struct {
+	blockService IBlockService
+}
+
+func NewBlockHandler(blockService IBlockService) *BlockHandler {
+	return &BlockHandler{blockService}
+}
+
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.blockService.Block, "blocked user successful!")
+}
+
+func (h *BlockHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.blockService.Unblock, "unblocked user successful!")
+}
+
+func (h *BlockHandler) Mute(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.blockService.Mute, "muted user successful!")
+}
+
+func (h *BlockHandler) Unmute(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.blockService.Unmute, "unmuted user successful!")
+}
+
+func (h *BlockHandler) change(w http.ResponseWriter, r *http.Request,
+	action func(userUUID, targetUUID string) error, message string,
+) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	targetUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(targetUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := action(userUUID, targetUUID); err != nil {
+		switch err {
+		case ErrSelfRelation:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrUserNotFound, ErrRelationNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse(message), http.StatusOK)
+}
+
+func (h *BlockHandler) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.blockService.GetBlocked)
+}
+
+func (h *BlockHandler) GetMuted(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.blockService.GetMuted)
+}
+
+func (h *BlockHandler) list(w http.ResponseWriter, r *http.Request, get func(userUUID string) ([]Relation, error)) {
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	relations, err := get(userUUID)
+	if err != nil {
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, relations, http.StatusOK)
+}
@@ -0,0 +1,99 @@
+package block
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr error
+}
+
+func (m *mService) Block(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) Unblock(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) Mute(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) Unmute(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) GetBlocked(string) ([]Relation, error) {
+	return []Relation{}, m.isErr
+}
+
+func (m *mService) GetMuted(string) ([]Relation, error) {
+	return []Relation{}, m.isErr
+}
+
+func TestHandlerBlock(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{"should block user", "f6630558-b800-48ff-9a09-5863d6055154", nil, http.StatusOK, util.BuildResponse("blocked user successful!")},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause yourself", "f6630558-b800-48ff-9a09-5863d6055154", ErrSelfRelation, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should not found", "f6630558-b800-48ff-9a09-5863d6055154", ErrUserNotFound, http.StatusNotFound, util.BuildErrResponse("not found")(nil)},
+		{"should service error", "f6630558-b800-48ff-9a09-5863d6055154", errors.New("service err"), http.StatusInternalServerError, util.BuildErrResponse("service error")(nil)},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewBlockHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.Block(rec, req)
+
+			var res map[string]string
+			json.NewDecoder(rec.Body).Decode(&res)
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantBody["message"], res["message"], "Want %v but got %v", v.wantBody, res)
+		})
+	}
+}
+
+func TestHandlerGetMuted(t *testing.T) {
+	testTable := []struct {
+		title      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return muted users", nil, http.StatusOK},
+		{"should service failed", errors.New("service err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewBlockHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.GetMuted(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package block
+
+import (
+	"database/sql"
+	"errors"
+)
+
+var ErrRelationNotFound = errors.New("relation not found")
+
+type BlockRepository struct {
+	db *sql.DB
+}
+
+func NewBlockRepository(db *sql.DB) *BlockRepository {
+	return &BlockRepository{db}
+}
+
+// Block also drops follows in both directions, otherwise the blocked user
+// would keep receiving the blocker's posts through an old follow.
+func (r *BlockRepository) Block(blockerUUID, blockedUUID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO blocks (blocker_id, blocked_id)
+    VALUES ((SELECT id FROM app_user WHERE uuid = $1), (SELECT id FROM app_user WHERE uuid = $2))
+    ON CONFLICT (blocker_id, blocked_id) DO NOTHING`, blockerUUID, blockedUUID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM follows
+    WHERE (follower_id = (SELECT id FROM app_user WHERE uuid = $1) AND followed_id = (SELECT id FROM app_user WHERE uuid = $2))
+    OR (follower_id = (SELECT id FROM app_user WHERE uuid = $2) AND followed_id = (SELECT id FROM app_user WHERE uuid = $1))`,
+		blockerUUID, blockedUUID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *BlockRepository) Unblock(blockerUUID, blockedUUID string) error {
+	return r.deleteRelation(`DELETE FROM blocks
+    WHERE blocker_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND blocked_id = (SELECT id FROM app_user WHERE uuid = $2)`, blockerUUID, blockedUUID)
+}
+
+func (r *BlockRepository) Mute(muterUUID, mutedUUID string) error {
+	_, err := r.db.Exec(`INSERT INTO mutes (muter_id, muted_id)
+    VALUES ((SELECT id FROM app_user WHERE uuid = $1), (SELECT id FROM app_user WHERE uuid = $2))
+    ON CONFLICT (muter_id, muted_id) DO NOTHING`, muterUUID, mutedUUID)
+	return err
+}
+
+func (r *BlockRepository) Unmute(muterUUID, mutedUUID string) error {
+	return r.deleteRelation(`DELETE FROM mutes
+    WHERE muter_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND muted_id = (SELECT id FROM app_user WHERE uuid = $2)`, muterUUID, mutedUUID)
+}
+
+func (r *BlockRepository) deleteRelation(query string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	numAffect, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numAffect == 0 {
+		return ErrRelationNotFound
+	}
+	return nil
+}
+
+func (r *BlockRepository) IsBlocked(userUUID, otherUUID string) (bool, error) {
+	query := `
+  SELECT EXISTS (
+    SELECT 1 FROM blocks AS b
+    JOIN app_user AS a ON a.id = b.blocker_id
+    JOIN app_user AS o ON o.id = b.blocked_id
+    WHERE (a.uuid = $1 AND o.uuid = $2) OR (a.uuid = $2 AND o.uuid = $1)
+  )`
+
+	var blocked bool
+	err := r.db.QueryRow(query, userUUID, otherUUID).Scan(&blocked)
+	return blocked, err
+}
+
+func (r *BlockRepository) GetBlocked(userUUID string) ([]Relation, error) {
+	return r.getRelations(`
+  SELECT u.uuid, u.username, b.created_at
+  FROM blocks AS b
+  JOIN app_user AS u ON u.id = b.blocked_id
+  WHERE b.blocker_id = (SELECT id FROM app_user WHERE uuid = $1)
+  ORDER BY b.created_at DESC`, userUUID)
+}
+
+func (r *BlockRepository) GetMuted(userUUID string) ([]Relation, error) {
+	return r.getRelations(`
+  SELECT u.uuid, u.username, m.created_at
+  FROM mutes AS m
+  JOIN app_user AS u ON u.id = m.muted_id
+  WHERE m.muter_id = (SELECT id FROM app_user WHERE uuid = $1)
+  ORDER BY m.created_at DESC`, userUUID)
+}
+
+func (r *BlockRepository) getRelations(query string, userUUID string) ([]Relation, error) {
+	rows, err := r.db.Query(query, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := []Relation{}
+	for rows.Next() {
+		var rel Relation
+		if err := rows.Scan(&rel.UserUUID, &rel.Username, &rel.CreatedAt); err != nil {
+			return relations, err
+		}
+		relations = append(relations, rel)
+	}
+	return relations, rows.Err()
+}
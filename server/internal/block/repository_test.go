@@ -0,0 +1,76 @@
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlock(t *testing.T) {
+	blocker := "e936e164-52fa-4fd5-b0e0-597c2f270245"
+	blocked := "f6630558-b800-48ff-9a09-5863d6055154"
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO blocks").WithArgs(blocker, blocked).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM follows").WithArgs(blocker, blocked).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewBlockRepository(db)
+	err := repo.Block(blocker, blocked)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUnblock(t *testing.T) {
+	testTable := []struct {
+		title   string
+		affect  int64
+		wantErr error
+	}{
+		{"should unblock", 1, nil},
+		{"should not found", 0, ErrRelationNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectExec("DELETE FROM blocks").WillReturnResult(sqlmock.NewResult(0, v.affect))
+
+			repo := NewBlockRepository(db)
+			err := repo.Unblock("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestIsBlocked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("SELECT EXISTS").WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	repo := NewBlockRepository(db)
+	blocked, err := repo.IsBlocked("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, blocked, "should be blocked")
+}
+
+func TestGetMuted(t *testing.T) {
+	createdAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("FROM mutes").WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245").
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "username", "created_at"}).
+			AddRow("f6630558-b800-48ff-9a09-5863d6055154", "ong", createdAt))
+
+	repo := NewBlockRepository(db)
+	muted, err := repo.GetMuted("e936e164-52fa-4fd5-b0e0-597c2f270245")
+	want := []Relation{{"f6630558-b800-48ff-9a09-5863d6055154", "ong", createdAt}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, muted, "Want %v but got %v", want, muted)
+}
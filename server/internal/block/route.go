@@ -0,0 +1,30 @@
+package block
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IBlockHandler interface {
+	Block(http.ResponseWriter, *http.Request)
+	Unblock(http.ResponseWriter, *http.Request)
+	Mute(http.ResponseWriter, *http.Request)
+	Unmute(http.ResponseWriter, *http.Request)
+	GetBlocked(http.ResponseWriter, *http.Request)
+	GetMuted(http.ResponseWriter, *http.Request)
+}
+
+func RegisterBlockRouter(router *mux.Router, blockHandler IBlockHandler, authMiddleware mux.MiddlewareFunc) {
+	brouter := router.PathPrefix("/block").Subrouter()
+	brouter.Use(authMiddleware)
+	brouter.HandleFunc("", blockHandler.GetBlocked).Methods(http.MethodGet)
+	brouter.HandleFunc("/{uuid}", blockHandler.Block).Methods(http.MethodPost)
+	brouter.HandleFunc("/{uuid}", blockHandler.Unblock).Methods(http.MethodDelete)
+
+	mrouter := router.PathPrefix("/mute").Subrouter()
+	mrouter.Use(authMiddleware)
+	mrouter.HandleFunc("", blockHandler.GetMuted).Methods(http.MethodGet)
+	mrouter.HandleFunc("/{uuid}", blockHandler.Mute).Methods(http.MethodPost)
+	mrouter.HandleFunc("/{uuid}", blockHandler.Unmute).Methods(http.MethodDelete)
+}
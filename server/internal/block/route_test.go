@@ -0,0 +1,63 @@
+package block
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) Block(http.ResponseWriter, *http.Request) {
+	m.called["Block"] = true
+}
+
+func (m *MockHandler) Unblock(http.ResponseWriter, *http.Request) {
+	m.called["Unblock"] = true
+}
+
+func (m *MockHandler) Mute(http.ResponseWriter, *http.Request) {
+	m.called["Mute"] = true
+}
+
+func (m *MockHandler) Unmute(http.ResponseWriter, *http.Request) {
+	m.called["Unmute"] = true
+}
+
+func (m *MockHandler) GetBlocked(http.ResponseWriter, *http.Request) {
+	m.called["GetBlocked"] = true
+}
+
+func (m *MockHandler) GetMuted(http.ResponseWriter, *http.Request) {
+	m.called["GetMuted"] = true
+}
+
+func TestRoute(t *testing.T) {
+	uuid := "f6630558-b800-48ff-9a09-5863d6055154"
+	routes := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/block", "GetBlocked"},
+		{http.MethodPost, "/block/" + uuid, "Block"},
+		{http.MethodDelete, "/block/" + uuid, "Unblock"},
+		{http.MethodGet, "/mute", "GetMuted"},
+		{http.MethodPost, "/mute/" + uuid, "Mute"},
+		{http.MethodDelete, "/mute/" + uuid, "Unmute"},
+	}
+
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterBlockRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	for _, v := range routes {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.handler], "%v not called", v.handler)
+	}
+}
@@ -0,0 +1,81 @@
+package block
+
+import (
+	"errors"
+
+	"github.com/dsypasit/social-clone/server/internal/user"
+)
+
+var (
+	ErrSelfRelation = errors.New("cannot block or mute yourself")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+type IBlockRepository interface {
+	Block(blockerUUID, blockedUUID string) error
+	Unblock(blockerUUID, blockedUUID string) error
+	Mute(muterUUID, mutedUUID string) error
+	Unmute(muterUUID, mutedUUID string) error
+	IsBlocked(userUUID, otherUUID string) (bool, error)
+	GetBlocked(userUUID string) ([]Relation, error)
+	GetMuted(userUUID string) ([]Relation, error)
+}
+
+type IUserServiceForBlock interface {
+	GetUserByUUID(string) (user.User, error)
+}
+
+type BlockService struct {
+	blockRepo   IBlockRepository
+	userService IUserServiceForBlock
+}
+
+func NewBlockService(blockRepo IBlockRepository, userService IUserServiceForBlock) *BlockService {
+	return &BlockService{blockRepo, userService}
+}
+
+func (s *BlockService) Block(blockerUUID, blockedUUID string) error {
+	if err := s.checkTarget(blockerUUID, blockedUUID); err != nil {
+		return err
+	}
+	return s.blockRepo.Block(blockerUUID, blockedUUID)
+}
+
+func (s *BlockService) Unblock(blockerUUID, blockedUUID string) error {
+	return s.blockRepo.Unblock(blockerUUID, blockedUUID)
+}
+
+func (s *BlockService) Mute(muterUUID, mutedUUID string) error {
+	if err := s.checkTarget(muterUUID, mutedUUID); err != nil {
+		return err
+	}
+	return s.blockRepo.Mute(muterUUID, mutedUUID)
+}
+
+func (s *BlockService) Unmute(muterUUID, mutedUUID string) error {
+	return s.blockRepo.Unmute(muterUUID, mutedUUID)
+}
+
+// IsBlocked reports whether either user has blocked the other.
+func (s *BlockService) IsBlocked(userUUID, otherUUID string) (bool, error) {
+	return s.blockRepo.IsBlocked(userUUID, otherUUID)
+}
+
+func (s *BlockService) GetBlocked(userUUID string) ([]Relation, error) {
+	return s.blockRepo.GetBlocked(userUUID)
+}
+
+func (s *BlockService) GetMuted(userUUID string) ([]Relation, error) {
+	return s.blockRepo.GetMuted(userUUID)
+}
+
+func (s *BlockService) checkTarget(userUUID, targetUUID string) error {
+	if userUUID == targetUUID {
+		return ErrSelfRelation
+	}
+	_, err := s.userService.GetUserByUUID(targetUUID)
+	if err == user.ErrUserNotFound {
+		return ErrUserNotFound
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	blocked bool
+	repoErr error
+}
+
+func (m *MockRepo) Block(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) Unblock(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) Mute(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) Unmute(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) IsBlocked(string, string) (bool, error) {
+	return m.blocked, m.repoErr
+}
+
+func (m *MockRepo) GetBlocked(string) ([]Relation, error) {
+	return []Relation{}, m.repoErr
+}
+
+func (m *MockRepo) GetMuted(string) ([]Relation, error) {
+	return []Relation{}, m.repoErr
+}
+
+type MockUserSrv struct {
+	err error
+}
+
+func (m *MockUserSrv) GetUserByUUID(s string) (user.User, error) {
+	return user.User{UUID: s}, m.err
+}
+
+func TestServiceBlock(t *testing.T) {
+	testTable := []struct {
+		title   string
+		blocker string
+		blocked string
+		userErr error
+		wantErr error
+	}{
+		{"should block user", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", nil, nil},
+		{"should not block yourself", "e936e164-52fa-4fd5-b0e0-597c2f270245", "e936e164-52fa-4fd5-b0e0-597c2f270245", nil, ErrSelfRelation},
+		{"should user not found", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", user.ErrUserNotFound, ErrUserNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewBlockService(&MockRepo{}, &MockUserSrv{v.userErr})
+			err := s.Block(v.blocker, v.blocked)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+
+			err = s.Mute(v.blocker, v.blocked)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceIsBlocked(t *testing.T) {
+	s := NewBlockService(&MockRepo{blocked: true}, &MockUserSrv{})
+	blocked, err := s.IsBlocked("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, blocked, "should be blocked")
+}
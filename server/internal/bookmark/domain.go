@@ -0,0 +1,51 @@
+package bookmark
+
+import (
+	"time"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+)
+
+const (
+	MaxCollectionName    = 50
+	DefaultBookmarkLimit = 20
+)
+
+type Collection struct {
+	UUID         string    `json:"uuid"`
+	Name         string    `json:"name"`
+	NumBookmarks int64     `json:"num_bookmarks"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CollectionCreated struct {
+	UUID     string `json:"-"`
+	Name     string `json:"name"`
+	UserUUID string `json:"-"`
+}
+
+// BookmarkCreated saves a post, an empty CollectionUUID keeps it outside of
+// any collection. Saving an already saved post moves it.
+type BookmarkCreated struct {
+	UserUUID       string `json:"-"`
+	PostUUID       string `json:"-"`
+	CollectionUUID string `json:"collection_uuid,omitempty"`
+}
+
+// BookmarkFilter pages newest first. Cursor is the post uuid of the last
+// bookmark of the previous page.
+type BookmarkFilter struct {
+	UserUUID       string
+	CollectionUUID string
+	Cursor         string
+	Limit          int
+}
+
+type BookmarkPage struct {
+	Posts      []post.PostResponse `json:"posts"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type CreatedResponse struct {
+	UUID string `json:"uuid"`
+}
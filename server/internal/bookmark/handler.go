@@ -0,0 +1,193 @@
+package bookmark
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IBookmarkService interface {
+	CreateCollection(CollectionCreated) (string, error)
+	GetCollections(userUUID string) ([]Collection, error)
+	DeleteCollection(userUUID, collectionUUID string) error
+	AddBookmark(BookmarkCreated) error
+	RemoveBookmark(userUUID, postUUID string) error
+	GetBookmarks(BookmarkFilter) (BookmarkPage, error)
+}
+
+type BookmarkHandler struct {
+	bookmarkService IBookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService IBookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkService}
+}
+
+func sendServiceErr(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInvalidName:
+		util.SendJson(w, util.BuildErrResponse("invalid request")(err), http.StatusBadRequest)
+	case ErrPostNotFound, ErrCollectionNotFound, ErrBookmarkNotFound:
+		util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+	case ErrDuplicateCollection:
+		util.SendJson(w, util.BuildErrResponse("duplicate collection")(err), http.StatusConflict)
+	default:
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+	}
+}
+
+func (h *BookmarkHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var c CollectionCreated
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+	c.UserUUID = userUUID
+
+	uuid, err := h.bookmarkService.CreateCollection(c)
+	if err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, CreatedResponse{uuid}, http.StatusCreated)
+}
+
+func (h *BookmarkHandler) GetCollections(w http.ResponseWriter, r *http.Request) {
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	collections, err := h.bookmarkService.GetCollections(userUUID)
+	if err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, collections, http.StatusOK)
+}
+
+func (h *BookmarkHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	collectionUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(collectionUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.bookmarkService.DeleteCollection(userUUID, collectionUUID); err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("deleted collection successful!"), http.StatusOK)
+}
+
+// AddBookmark takes an optional body, without one the post is saved outside
+// of any collection.
+func (h *BookmarkHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	var b BookmarkCreated
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil && err != io.EOF {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	b.PostUUID = mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(b.PostUUID) || (b.CollectionUUID != "" && !util.IsValidUUID(b.CollectionUUID)) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+	b.UserUUID = userUUID
+
+	if err := h.bookmarkService.AddBookmark(b); err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("bookmarked post successful!"), http.StatusCreated)
+}
+
+func (h *BookmarkHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.bookmarkService.RemoveBookmark(userUUID, postUUID); err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("removed bookmark successful!"), http.StatusOK)
+}
+
+func (h *BookmarkHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	collectionUUID, cursor := q.Get("collection"), q.Get("cursor")
+	if (collectionUUID != "" && !util.IsValidUUID(collectionUUID)) || (cursor != "" && !util.IsValidUUID(cursor)) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	bookmarks, err := h.bookmarkService.GetBookmarks(BookmarkFilter{
+		UserUUID:       userUUID,
+		CollectionUUID: collectionUUID,
+		Cursor:         cursor,
+		Limit:          page.Limit,
+	})
+	if err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, bookmarks, http.StatusOK)
+}
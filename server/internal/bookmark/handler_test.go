@@ -0,0 +1,186 @@
+package bookmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr     error
+	gotFilter BookmarkFilter
+	added     BookmarkCreated
+}
+
+func (m *mService) CreateCollection(CollectionCreated) (string, error) {
+	return collectionUUID, m.isErr
+}
+
+func (m *mService) GetCollections(string) ([]Collection, error) {
+	return []Collection{}, m.isErr
+}
+
+func (m *mService) DeleteCollection(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) AddBookmark(b BookmarkCreated) error {
+	m.added = b
+	return m.isErr
+}
+
+func (m *mService) RemoveBookmark(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) GetBookmarks(f BookmarkFilter) (BookmarkPage, error) {
+	m.gotFilter = f
+	return BookmarkPage{}, m.isErr
+}
+
+func withUser(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), "userUUID", ongUUID))
+}
+
+func TestHandlerCreateCollection(t *testing.T) {
+	testTable := []struct {
+		title      string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{"should create collection", `{"name":"recipes"}`, nil, http.StatusCreated, map[string]string{"uuid": collectionUUID}},
+		{"should bad request cause invalid body", `{`, nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause invalid name", `{"name":""}`, ErrInvalidName, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should conflict cause duplicate", `{"name":"recipes"}`, ErrDuplicateCollection, http.StatusConflict, util.BuildErrResponse("duplicate collection")(nil)},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewBookmarkHandler(&mService{isErr: v.serviceErr})
+			req := withUser(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(v.body)))
+			rec := httptest.NewRecorder()
+			h.CreateCollection(rec, req)
+
+			var res map[string]string
+			json.NewDecoder(rec.Body).Decode(&res)
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equal(t, v.wantBody["uuid"], res["uuid"])
+			assert.Equal(t, v.wantBody["message"], res["message"])
+		})
+	}
+}
+
+func TestHandlerDeleteCollection(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should delete collection", collectionUUID, nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest},
+		{"should not found", collectionUUID, ErrCollectionNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewBookmarkHandler(&mService{isErr: v.serviceErr})
+			req := withUser(mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/", nil), map[string]string{"uuid": v.uuid}))
+			rec := httptest.NewRecorder()
+			h.DeleteCollection(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerAddBookmark(t *testing.T) {
+	testTable := []struct {
+		title          string
+		uuid           string
+		body           string
+		serviceErr     error
+		wantStatus     int
+		wantCollection string
+	}{
+		{"should bookmark without body", postUUID, "", nil, http.StatusCreated, ""},
+		{"should bookmark into collection", postUUID, `{"collection_uuid":"` + collectionUUID + `"}`, nil, http.StatusCreated, collectionUUID},
+		{"should bad request cause invalid body", postUUID, `{`, nil, http.StatusBadRequest, ""},
+		{"should bad request cause invalid post uuid", "abc", "", nil, http.StatusBadRequest, ""},
+		{"should bad request cause invalid collection uuid", postUUID, `{"collection_uuid":"abc"}`, nil, http.StatusBadRequest, ""},
+		{"should not found", postUUID, "", ErrPostNotFound, http.StatusNotFound, ""},
+		{"should service error", postUUID, "", errors.New("service err"), http.StatusInternalServerError, ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mSrv := mService{isErr: v.serviceErr}
+			h := NewBookmarkHandler(&mSrv)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(v.body))
+			req = withUser(mux.SetURLVars(req, map[string]string{"uuid": v.uuid}))
+			rec := httptest.NewRecorder()
+			h.AddBookmark(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equal(t, v.wantCollection, mSrv.added.CollectionUUID)
+		})
+	}
+}
+
+func TestHandlerRemoveBookmark(t *testing.T) {
+	testTable := []struct {
+		title      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should remove bookmark", nil, http.StatusOK},
+		{"should not found", ErrBookmarkNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewBookmarkHandler(&mService{isErr: v.serviceErr})
+			req := withUser(mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/", nil), map[string]string{"uuid": postUUID}))
+			rec := httptest.NewRecorder()
+			h.RemoveBookmark(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetBookmarks(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		wantStatus int
+		wantFilter BookmarkFilter
+	}{
+		{"should list bookmarks", "", http.StatusOK, BookmarkFilter{UserUUID: ongUUID, Limit: util.DefaultPageLimit}},
+		{"should list with cursor", "?collection=" + collectionUUID + "&cursor=" + postUUID + "&limit=5", http.StatusOK, BookmarkFilter{ongUUID, collectionUUID, postUUID, 5}},
+		{"should bad request cause invalid cursor", "?cursor=abc", http.StatusBadRequest, BookmarkFilter{}},
+		{"should bad request cause invalid limit", "?limit=0", http.StatusBadRequest, BookmarkFilter{}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mSrv := mService{}
+			h := NewBookmarkHandler(&mSrv)
+			rec := httptest.NewRecorder()
+			h.GetBookmarks(rec, withUser(httptest.NewRequest(http.MethodGet, "/"+v.query, nil)))
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equal(t, v.wantFilter, mSrv.gotFilter)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package bookmark
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+)
+
+var (
+	ErrPostNotFound        = errors.New("post not found")
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrDuplicateCollection = errors.New("collection already exists")
+	ErrBookmarkNotFound    = errors.New("bookmark not found")
+)
+
+type BookmarkRepository struct {
+	db *sql.DB
+}
+
+func NewBookmarkRepository(db *sql.DB) *BookmarkRepository {
+	return &BookmarkRepository{db}
+}
+
+func (r *BookmarkRepository) CreateCollection(c CollectionCreated) (int64, error) {
+	query := `INSERT INTO bookmark_collection (uuid, name, app_user_id)
+    VALUES ($1, $2, (SELECT id FROM app_user WHERE uuid = $3))
+    ON CONFLICT (app_user_id, name) DO NOTHING
+    RETURNING id`
+
+	var id int64
+	err := r.db.QueryRow(query, c.UUID, c.Name, c.UserUUID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, ErrDuplicateCollection
+	}
+	return id, err
+}
+
+func (r *BookmarkRepository) GetCollections(userUUID string) ([]Collection, error) {
+	query := `
+  SELECT c.uuid, c.name, (SELECT count(*) FROM bookmark AS b WHERE b.collection_id = c.id), c.created_at
+  FROM bookmark_collection AS c
+  WHERE c.app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+  ORDER BY c.name`
+
+	rows, err := r.db.Query(query, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []Collection{}
+	for rows.Next() {
+		var c Collection
+		if err := rows.Scan(&c.UUID, &c.Name, &c.NumBookmarks, &c.CreatedAt); err != nil {
+			return collections, err
+		}
+		collections = append(collections, c)
+	}
+	return collections, rows.Err()
+}
+
+// DeleteCollection keeps its bookmarks, they fall back to no collection.
+func (r *BookmarkRepository) DeleteCollection(userUUID, collectionUUID string) error {
+	res, err := r.db.Exec(`DELETE FROM bookmark_collection
+    WHERE uuid = $2 AND app_user_id = (SELECT id FROM app_user WHERE uuid = $1)`, userUUID, collectionUUID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// AddBookmark saves a post the user can read, saving it again moves it to
+// the given collection.
+func (r *BookmarkRepository) AddBookmark(b BookmarkCreated) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var collectionID any
+	if b.CollectionUUID != "" {
+		var id int64
+		err := tx.QueryRow(`SELECT id FROM bookmark_collection
+      WHERE uuid = $2 AND app_user_id = (SELECT id FROM app_user WHERE uuid = $1)`, b.UserUUID, b.CollectionUUID).Scan(&id)
+		if err == sql.ErrNoRows {
+			return ErrCollectionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		collectionID = id
+	}
+
+	query := `INSERT INTO bookmark (app_user_id, post_id, collection_id)
+    SELECT (SELECT id FROM app_user WHERE uuid = $1), p.id, $3
+    FROM post AS p
+    JOIN app_user AS u ON u.id = p.app_user_id
+    WHERE p.uuid = $2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL
+    AND ` + post.VisibleTo("p", "u") + ` AND ` + post.NotBlocked("u") + `
+    ON CONFLICT (app_user_id, post_id) DO UPDATE SET collection_id = EXCLUDED.collection_id`
+
+	res, err := tx.Exec(query, b.UserUUID, b.PostUUID, collectionID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPostNotFound
+	}
+	return tx.Commit()
+}
+
+func (r *BookmarkRepository) RemoveBookmark(userUUID, postUUID string) error {
+	res, err := r.db.Exec(`DELETE FROM bookmark
+    WHERE app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND post_id = (SELECT id FROM post WHERE uuid = $2)`, userUUID, postUUID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// GetBookmarks returns saved posts newest saved first. Triggers purge
+// bookmarks whose post goes away, the visibility and block conditions here
+// cover what changes in between, like a suspended author.
+func (r *BookmarkRepository) GetBookmarks(f BookmarkFilter) ([]post.PostResponse, error) {
+	query := `
+  SELECT ` + post.PostColumns + `
+  FROM bookmark AS b
+  JOIN post AS p ON p.id = b.post_id
+  JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE b.app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+  AND ($2::uuid IS NULL OR b.collection_id = (SELECT id FROM bookmark_collection WHERE uuid = $2))
+  AND ($3::uuid IS NULL OR b.id < (
+    SELECT id FROM bookmark WHERE app_user_id = b.app_user_id AND post_id = (SELECT id FROM post WHERE uuid = $3)
+  ))
+  AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+  AND u.delete_at IS NULL AND u.suspended_at IS NULL
+  AND ` + post.VisibleTo("p", "u") + ` AND ` + post.NotBlocked("u") + `
+  ORDER BY b.id DESC
+  LIMIT $4`
+
+	var collectionUUID, cursor any
+	if f.CollectionUUID != "" {
+		collectionUUID = f.CollectionUUID
+	}
+	if f.Cursor != "" {
+		cursor = f.Cursor
+	}
+
+	rows, err := r.db.Query(query, f.UserUUID, collectionUUID, cursor, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []post.PostResponse{}
+	for rows.Next() {
+		p, err := post.ScanPost(rows)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
@@ -0,0 +1,186 @@
+package bookmark
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ongUUID        = "e936e164-52fa-4fd5-b0e0-597c2f270245"
+	postUUID       = "f307d2db-d2ea-4ec9-8d31-27b7443d7c72"
+	collectionUUID = "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"
+)
+
+var postColumnNames = []string{"uuid", "content", "num_like", "visibility_type_id", "uuid", "username", "updated_at", "mentions",
+	"is_repost", "is_quote", "num_repost", "num_quote", "reposted_by_me", "original", "reactions", "my_reaction", "bookmarked_by_me", "edited_at", "poll", "link_preview"}
+
+func TestCreateCollection(t *testing.T) {
+	testTable := []struct {
+		title    string
+		queryErr error
+		wantErr  error
+	}{
+		{"should create collection", nil, nil},
+		{"should duplicate collection", sql.ErrNoRows, ErrDuplicateCollection},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			q := mock.ExpectQuery("INSERT INTO bookmark_collection (.+) ON CONFLICT").WithArgs(collectionUUID, "recipes", ongUUID)
+			if v.queryErr != nil {
+				q.WillReturnError(v.queryErr)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			repo := NewBookmarkRepository(db)
+			_, err := repo.CreateCollection(CollectionCreated{collectionUUID, "recipes", ongUUID})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestGetCollections(t *testing.T) {
+	createdAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("FROM bookmark_collection AS c").WithArgs(ongUUID).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "name", "count", "created_at"}).AddRow(collectionUUID, "recipes", 3, createdAt))
+
+	repo := NewBookmarkRepository(db)
+	collections, err := repo.GetCollections(ongUUID)
+	want := []Collection{{collectionUUID, "recipes", 3, createdAt}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, collections, "Want %v but got %v", want, collections)
+}
+
+func TestDeleteCollection(t *testing.T) {
+	testTable := []struct {
+		title   string
+		affect  int64
+		wantErr error
+	}{
+		{"should delete collection", 1, nil},
+		{"should not found", 0, ErrCollectionNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectExec("DELETE FROM bookmark_collection").WithArgs(ongUUID, collectionUUID).
+				WillReturnResult(sqlmock.NewResult(0, v.affect))
+
+			repo := NewBookmarkRepository(db)
+			err := repo.DeleteCollection(ongUUID, collectionUUID)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestAddBookmark(t *testing.T) {
+	testTable := []struct {
+		title         string
+		b             BookmarkCreated
+		collectionErr error
+		affect        int64
+		wantErr       error
+	}{
+		{"should bookmark without collection", BookmarkCreated{ongUUID, postUUID, ""}, nil, 1, nil},
+		{"should bookmark into collection", BookmarkCreated{ongUUID, postUUID, collectionUUID}, nil, 1, nil},
+		{"should collection not found", BookmarkCreated{ongUUID, postUUID, collectionUUID}, sql.ErrNoRows, 0, ErrCollectionNotFound},
+		{"should post not found or invisible", BookmarkCreated{ongUUID, postUUID, ""}, nil, 0, ErrPostNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			var collectionID any
+			if v.b.CollectionUUID != "" {
+				q := mock.ExpectQuery("SELECT id FROM bookmark_collection").WithArgs(ongUUID, collectionUUID)
+				if v.collectionErr != nil {
+					q.WillReturnError(v.collectionErr)
+				} else {
+					q.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+					collectionID = int64(4)
+				}
+			}
+			if v.collectionErr == nil {
+				mock.ExpectExec("INSERT INTO bookmark (.+) ON CONFLICT (.+) DO UPDATE").
+					WithArgs(ongUUID, postUUID, collectionID).WillReturnResult(sqlmock.NewResult(0, v.affect))
+			}
+			if v.wantErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			repo := NewBookmarkRepository(db)
+			err := repo.AddBookmark(v.b)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRemoveBookmark(t *testing.T) {
+	testTable := []struct {
+		title   string
+		affect  int64
+		wantErr error
+	}{
+		{"should remove bookmark", 1, nil},
+		{"should not found", 0, ErrBookmarkNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectExec("DELETE FROM bookmark").WithArgs(ongUUID, postUUID).
+				WillReturnResult(sqlmock.NewResult(0, v.affect))
+
+			repo := NewBookmarkRepository(db)
+			err := repo.RemoveBookmark(ongUUID, postUUID)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestGetBookmarks(t *testing.T) {
+	testTable := []struct {
+		title          string
+		filter         BookmarkFilter
+		wantCollection any
+		wantCursor     any
+	}{
+		{"should list all bookmarks", BookmarkFilter{UserUUID: ongUUID, Limit: 21}, nil, nil},
+		{"should list collection after cursor", BookmarkFilter{ongUUID, collectionUUID, postUUID, 21}, collectionUUID, postUUID},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("FROM bookmark AS b (.+) ORDER BY b.id DESC").
+				WithArgs(ongUUID, v.wantCollection, v.wantCursor, 21).
+				WillReturnRows(sqlmock.NewRows(postColumnNames).
+					AddRow(postUUID, "saved", 0, 1, ongUUID, "ong", time.Now(), nil,
+						false, false, 0, 0, false, nil, nil, nil, true, nil, nil, nil))
+
+			repo := NewBookmarkRepository(db)
+			posts, err := repo.GetBookmarks(v.filter)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Len(t, posts, 1)
+			assert.True(t, posts[0].BookmarkedByMe)
+		})
+	}
+}
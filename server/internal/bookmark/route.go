@@ -0,0 +1,27 @@
+package bookmark
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IBookmarkHandler interface {
+	CreateCollection(http.ResponseWriter, *http.Request)
+	GetCollections(http.ResponseWriter, *http.Request)
+	DeleteCollection(http.ResponseWriter, *http.Request)
+	AddBookmark(http.ResponseWriter, *http.Request)
+	RemoveBookmark(http.ResponseWriter, *http.Request)
+	GetBookmarks(http.ResponseWriter, *http.Request)
+}
+
+func RegisterBookmarkRouter(router *mux.Router, bookmarkHandler IBookmarkHandler, authMiddleware mux.MiddlewareFunc) {
+	brouter := router.PathPrefix("/bookmarks").Subrouter()
+	brouter.Use(authMiddleware)
+	brouter.HandleFunc("", bookmarkHandler.GetBookmarks).Methods(http.MethodGet)
+	brouter.HandleFunc("/collections", bookmarkHandler.GetCollections).Methods(http.MethodGet)
+	brouter.HandleFunc("/collections", bookmarkHandler.CreateCollection).Methods(http.MethodPost)
+	brouter.HandleFunc("/collections/{uuid}", bookmarkHandler.DeleteCollection).Methods(http.MethodDelete)
+	brouter.HandleFunc("/{uuid}", bookmarkHandler.AddBookmark).Methods(http.MethodPost)
+	brouter.HandleFunc("/{uuid}", bookmarkHandler.RemoveBookmark).Methods(http.MethodDelete)
+}
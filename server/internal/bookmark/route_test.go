@@ -0,0 +1,62 @@
+package bookmark
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) CreateCollection(http.ResponseWriter, *http.Request) {
+	m.called["CreateCollection"] = true
+}
+
+func (m *MockHandler) GetCollections(http.ResponseWriter, *http.Request) {
+	m.called["GetCollections"] = true
+}
+
+func (m *MockHandler) DeleteCollection(http.ResponseWriter, *http.Request) {
+	m.called["DeleteCollection"] = true
+}
+
+func (m *MockHandler) AddBookmark(http.ResponseWriter, *http.Request) {
+	m.called["AddBookmark"] = true
+}
+
+func (m *MockHandler) RemoveBookmark(http.ResponseWriter, *http.Request) {
+	m.called["RemoveBookmark"] = true
+}
+
+func (m *MockHandler) GetBookmarks(http.ResponseWriter, *http.Request) {
+	m.called["GetBookmarks"] = true
+}
+
+func TestRoute(t *testing.T) {
+	routes := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/bookmarks", "GetBookmarks"},
+		{http.MethodGet, "/bookmarks/collections", "GetCollections"},
+		{http.MethodPost, "/bookmarks/collections", "CreateCollection"},
+		{http.MethodDelete, "/bookmarks/collections/0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "DeleteCollection"},
+		{http.MethodPost, "/bookmarks/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "AddBookmark"},
+		{http.MethodDelete, "/bookmarks/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "RemoveBookmark"},
+	}
+
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterBookmarkRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	for _, v := range routes {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.handler], "%v not called", v.handler)
+	}
+}
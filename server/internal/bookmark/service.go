@@ -0,0 +1,76 @@
+package bookmark
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidName = errors.New("invalid collection name")
+
+type IBookmarkRepository interface {
+	CreateCollection(CollectionCreated) (int64, error)
+	GetCollections(userUUID string) ([]Collection, error)
+	DeleteCollection(userUUID, collectionUUID string) error
+	AddBookmark(BookmarkCreated) error
+	RemoveBookmark(userUUID, postUUID string) error
+	GetBookmarks(BookmarkFilter) ([]post.PostResponse, error)
+}
+
+type BookmarkService struct {
+	bookmarkRepo IBookmarkRepository
+}
+
+func NewBookmarkService(bookmarkRepo IBookmarkRepository) *BookmarkService {
+	return &BookmarkService{bookmarkRepo}
+}
+
+func (s *BookmarkService) CreateCollection(c CollectionCreated) (string, error) {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" || utf8.RuneCountInString(c.Name) > MaxCollectionName {
+		return "", ErrInvalidName
+	}
+
+	c.UUID = uuid.NewString()
+	if _, err := s.bookmarkRepo.CreateCollection(c); err != nil {
+		return "", err
+	}
+	return c.UUID, nil
+}
+
+func (s *BookmarkService) GetCollections(userUUID string) ([]Collection, error) {
+	return s.bookmarkRepo.GetCollections(userUUID)
+}
+
+func (s *BookmarkService) DeleteCollection(userUUID, collectionUUID string) error {
+	return s.bookmarkRepo.DeleteCollection(userUUID, collectionUUID)
+}
+
+func (s *BookmarkService) AddBookmark(b BookmarkCreated) error {
+	return s.bookmarkRepo.AddBookmark(b)
+}
+
+func (s *BookmarkService) RemoveBookmark(userUUID, postUUID string) error {
+	return s.bookmarkRepo.RemoveBookmark(userUUID, postUUID)
+}
+
+// GetBookmarks reads one bookmark past the limit to know whether another
+// page follows.
+func (s *BookmarkService) GetBookmarks(f BookmarkFilter) (BookmarkPage, error) {
+	limit := f.Limit
+	f.Limit++
+	posts, err := s.bookmarkRepo.GetBookmarks(f)
+	if err != nil {
+		return BookmarkPage{}, err
+	}
+
+	page := BookmarkPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		page.NextCursor = *page.Posts[limit-1].UUID
+	}
+	return page, nil
+}
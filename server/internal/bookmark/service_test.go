@@ -0,0 +1,99 @@
+package bookmark
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	repoErr   error
+	created   CollectionCreated
+	posts     []post.PostResponse
+	gotFilter BookmarkFilter
+}
+
+func (m *MockRepo) CreateCollection(c CollectionCreated) (int64, error) {
+	m.created = c
+	return 1, m.repoErr
+}
+
+func (m *MockRepo) GetCollections(string) ([]Collection, error) {
+	return []Collection{}, m.repoErr
+}
+
+func (m *MockRepo) DeleteCollection(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) AddBookmark(BookmarkCreated) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) RemoveBookmark(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) GetBookmarks(f BookmarkFilter) ([]post.PostResponse, error) {
+	m.gotFilter = f
+	return m.posts[:min(f.Limit, len(m.posts))], m.repoErr
+}
+
+func TestServiceCreateCollection(t *testing.T) {
+	testTable := []struct {
+		title    string
+		name     string
+		wantName string
+		wantErr  error
+	}{
+		{"should create collection", "  recipes ", "recipes", nil},
+		{"should reject empty name", "   ", "", ErrInvalidName},
+		{"should reject long name", strings.Repeat("ก", MaxCollectionName+1), "", ErrInvalidName},
+		{"should accept name at limit", strings.Repeat("ก", MaxCollectionName), strings.Repeat("ก", MaxCollectionName), nil},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{}
+			s := NewBookmarkService(&mRepo)
+			uuid, err := s.CreateCollection(CollectionCreated{Name: v.name, UserUUID: ongUUID})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantName, mRepo.created.Name)
+			if v.wantErr == nil {
+				assert.True(t, util.IsValidUUID(uuid))
+			}
+		})
+	}
+}
+
+func TestServiceGetBookmarks(t *testing.T) {
+	posts := make([]post.PostResponse, 5)
+	for i := range posts {
+		posts[i].UUID = util.Ptr(fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+	}
+	testTable := []struct {
+		title      string
+		limit      int
+		wantLen    int
+		wantCursor string
+	}{
+		{"should return next cursor when more", 3, 3, "00000000-0000-0000-0000-000000000002"},
+		{"should not return cursor on last page", 5, 5, ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{posts: posts}
+			s := NewBookmarkService(&mRepo)
+			page, err := s.GetBookmarks(BookmarkFilter{UserUUID: ongUUID, Limit: v.limit})
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.limit+1, mRepo.gotFilter.Limit)
+			assert.Len(t, page.Posts, v.wantLen)
+			assert.Equal(t, v.wantCursor, page.NextCursor)
+		})
+	}
+}
@@ -1,11 +1,64 @@
 package comment
 
+import (
+	"time"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+)
+
 type comment struct {
 	ID        int    `json:"id"`
 	UUID      string `json:"uuid"`
 	Content   string `json:"content"`
 	UserId    int    `json:"user_id"`
 	PostId    int    `json:"post_id"`
+	ParentId  *int   `json:"parent_id"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
+
+type CommentCreated struct {
+	UUID       string    `json:"uuid"`
+	Content    string    `json:"content"`
+	UserUUID   string    `json:"user_uuid"`
+	PostUUID   string    `json:"post_uuid"`
+	ParentUUID string    `json:"parent_uuid,omitempty"`
+	Mentions   []Mention `json:"-"`
+}
+
+type Mention struct {
+	UserUUID string
+	Offset   int
+	Length   int
+}
+
+type CommentResponse struct {
+	UUID       string              `json:"uuid"`
+	Content    string              `json:"content"`
+	Username   string              `json:"username"`
+	UserUUID   string              `json:"user_uuid"`
+	PostUUID   string              `json:"post_uuid"`
+	UpdateAt   time.Time           `json:"update_at"`
+	ParentUUID *string             `json:"parent_uuid,omitempty"`
+	NumReply   int64               `json:"num_reply"`
+	Reactions  post.ReactionCounts `json:"reactions"`
+	MyReaction *string             `json:"my_reaction"`
+	Replies    []CommentResponse   `json:"replies,omitempty"`
+}
+
+// CommentRef locates a comment in its post, it is enough to check whether a
+// viewer may read or reply to it.
+type CommentRef struct {
+	UUID           string
+	AuthorUUID     string
+	PostUUID       string
+	PostAuthorUUID string
+}
+
+const (
+	DefaultThreadDepth = 3
+	MaxThreadDepth     = 5
+	// ThreadReplyLimit caps the replies loaded per comment in a thread, the
+	// rest are paged through the replies endpoint using num_reply.
+	ThreadReplyLimit = 5
+)
@@ -0,0 +1,161 @@
+package comment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dsypasit/social-clone/server/internal/filter"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var (
+	ErrInCompleteInfo = errors.New("incomplete information")
+	ErrInvalidUUID    = errors.New("invalid uuid format")
+	ErrInvalidDepth   = errors.New("invalid depth")
+)
+
+type ICommentService interface {
+	CreateComment(CommentCreated) (int64, error)
+	GetCommentsByPostUUID(postUUID, viewerUUID string) ([]CommentResponse, error)
+	GetReplies(commentUUID, viewerUUID string, page util.Page) ([]CommentResponse, error)
+	GetThread(commentUUID, viewerUUID string, depth int) (CommentResponse, error)
+}
+
+type CommentHandler struct {
+	commentService ICommentService
+}
+
+func NewCommentHandler(commentService ICommentService) *CommentHandler {
+	return &CommentHandler{commentService}
+}
+
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	var newComment CommentCreated
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if err := json.NewDecoder(r.Body).Decode(&newComment); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+	newComment.UserUUID = userUUID
+
+	if newComment.Content == "" || (newComment.PostUUID == "" && newComment.ParentUUID == "") {
+		util.SendJson(w, errInvalidReq(ErrInCompleteInfo), http.StatusBadRequest)
+		return
+	}
+
+	if (newComment.PostUUID != "" && !util.IsValidUUID(newComment.PostUUID)) ||
+		(newComment.ParentUUID != "" && !util.IsValidUUID(newComment.ParentUUID)) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.commentService.CreateComment(newComment); err != nil {
+		switch err {
+		case ErrPostNotFound, ErrCommentNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrParentMismatch, util.ErrTextEmpty, util.ErrTextTooLong, util.ErrZeroWidthAbuse,
+			filter.ErrBlockedWord, filter.ErrLinkSpam, filter.ErrDuplicate:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case filter.ErrTooFrequent:
+			util.SendJson(w, util.BuildErrResponse("too many requests")(err), http.StatusTooManyRequests)
+		case ErrBlocked:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("created comment successful!"), http.StatusCreated)
+}
+
+func (h *CommentHandler) GetCommentsByPostUUID(w http.ResponseWriter, r *http.Request) {
+	postUUID := r.URL.Query().Get("postuuid")
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	comments, err := h.commentService.GetCommentsByPostUUID(postUUID, viewerUUID)
+	if err != nil {
+		if err == ErrPostNotFound {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, comments, http.StatusOK)
+}
+
+func (h *CommentHandler) GetReplies(w http.ResponseWriter, r *http.Request) {
+	commentUUID := mux.Vars(r)["uuid"]
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if !util.IsValidUUID(commentUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	replies, err := h.commentService.GetReplies(commentUUID, viewerUUID, page)
+	if err != nil {
+		if err == ErrCommentNotFound {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, replies, http.StatusOK)
+}
+
+func (h *CommentHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	commentUUID := mux.Vars(r)["uuid"]
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if !util.IsValidUUID(commentUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	depth := DefaultThreadDepth
+	if v := r.URL.Query().Get("depth"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 || d > MaxThreadDepth {
+			util.SendJson(w, errInvalidReq(ErrInvalidDepth), http.StatusBadRequest)
+			return
+		}
+		depth = d
+	}
+
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	thread, err := h.commentService.GetThread(commentUUID, viewerUUID, depth)
+	if err != nil {
+		if err == ErrCommentNotFound {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, thread, http.StatusOK)
+}
@@ -0,0 +1,158 @@
+package comment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr error
+}
+
+func (m *mService) CreateComment(CommentCreated) (int64, error) {
+	return 1, m.isErr
+}
+
+func (m *mService) GetCommentsByPostUUID(string, string) ([]CommentResponse, error) {
+	return []CommentResponse{}, m.isErr
+}
+
+func (m *mService) GetReplies(string, string, util.Page) ([]CommentResponse, error) {
+	return []CommentResponse{}, m.isErr
+}
+
+func (m *mService) GetThread(string, string, int) (CommentResponse, error) {
+	return CommentResponse{}, m.isErr
+}
+
+func TestHandlerCreateComment(t *testing.T) {
+	testTable := []struct {
+		title      string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{"should create comment", `{"post_uuid":"f6630558-b800-48ff-9a09-5863d6055154","content":"nice"}`, nil, http.StatusCreated, util.BuildResponse("created comment successful!")},
+		{"should bad request cause invalid body", `{`, nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause empty content", `{"post_uuid":"f6630558-b800-48ff-9a09-5863d6055154"}`, nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause invalid uuid", `{"post_uuid":"abc","content":"nice"}`, nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should create reply", `{"parent_uuid":"a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f","content":"agree"}`, nil, http.StatusCreated, util.BuildResponse("created comment successful!")},
+		{"should bad request cause invalid parent uuid", `{"parent_uuid":"abc","content":"agree"}`, nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause parent on another post", `{"parent_uuid":"a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f","content":"agree"}`, ErrParentMismatch, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should not found cause parent missing", `{"parent_uuid":"a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f","content":"agree"}`, ErrCommentNotFound, http.StatusNotFound, util.BuildErrResponse("not found")(nil)},
+		{"should not found", `{"post_uuid":"f6630558-b800-48ff-9a09-5863d6055154","content":"nice"}`, ErrPostNotFound, http.StatusNotFound, util.BuildErrResponse("not found")(nil)},
+		{"should forbidden cause blocked", `{"post_uuid":"f6630558-b800-48ff-9a09-5863d6055154","content":"nice"}`, ErrBlocked, http.StatusForbidden, util.BuildErrResponse("forbidden")(nil)},
+		{"should service error", `{"post_uuid":"f6630558-b800-48ff-9a09-5863d6055154","content":"nice"}`, errors.New("service err"), http.StatusInternalServerError, util.BuildErrResponse("service error")(nil)},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewCommentHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(v.body))
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.CreateComment(rec, req)
+
+			var res map[string]string
+			json.NewDecoder(rec.Body).Decode(&res)
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantBody["message"], res["message"], "Want %v but got %v", v.wantBody, res)
+		})
+	}
+}
+
+func TestHandlerGetComments(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return comments", "?postuuid=f6630558-b800-48ff-9a09-5863d6055154", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "?postuuid=abc", nil, http.StatusBadRequest},
+		{"should not found", "?postuuid=f6630558-b800-48ff-9a09-5863d6055154", ErrPostNotFound, http.StatusNotFound},
+		{"should service failed", "?postuuid=f6630558-b800-48ff-9a09-5863d6055154", errors.New("service err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewCommentHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, "/"+v.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.GetCommentsByPostUUID(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetReplies(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return replies", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "?limit=10&offset=10", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", "", nil, http.StatusBadRequest},
+		{"should bad request cause invalid page", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "?limit=0", nil, http.StatusBadRequest},
+		{"should not found", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "", ErrCommentNotFound, http.StatusNotFound},
+		{"should service failed", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "", errors.New("service err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewCommentHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, "/"+v.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			rec := httptest.NewRecorder()
+			h.GetReplies(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetThread(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return thread", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "?depth=2", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", "", nil, http.StatusBadRequest},
+		{"should bad request cause depth too deep", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "?depth=6", nil, http.StatusBadRequest},
+		{"should bad request cause invalid depth", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "?depth=abc", nil, http.StatusBadRequest},
+		{"should not found", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "", ErrCommentNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewCommentHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, "/"+v.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			rec := httptest.NewRecorder()
+			h.GetThread(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
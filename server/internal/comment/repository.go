@@ -1,11 +1,236 @@
 package comment
 
-import "database/sql"
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrPostNotFound    = errors.New("post not found")
+	ErrCommentNotFound = errors.New("comment not found")
+)
 
 type CommentRepository struct {
 	db *sql.DB
 }
 
-func NewcommentRepository(db *sql.DB) *CommentRepository {
+func NewCommentRepository(db *sql.DB) *CommentRepository {
 	return &CommentRepository{db}
 }
+
+func (r *CommentRepository) CreateComment(c CommentCreated) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO comment (uuid, content, app_user_id, post_id, parent_id)
+    VALUES (
+        $1,
+        $2,
+        (SELECT id FROM app_user WHERE uuid = $3),
+        (SELECT id FROM post WHERE uuid = $4),
+        (SELECT id FROM comment WHERE uuid = $5)
+    ) RETURNING id`
+
+	var parentUUID any
+	if c.ParentUUID != "" {
+		parentUUID = c.ParentUUID
+	}
+
+	var id int64
+	err = tx.QueryRow(query, c.UUID, c.Content, c.UserUUID, c.PostUUID, parentUUID).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertMentions(tx, id, c.Mentions); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func insertMentions(tx *sql.Tx, commentID int64, mentions []Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	userUUIDs := make([]string, len(mentions))
+	offsets := make([]int64, len(mentions))
+	lengths := make([]int64, len(mentions))
+	for i, m := range mentions {
+		userUUIDs[i], offsets[i], lengths[i] = m.UserUUID, int64(m.Offset), int64(m.Length)
+	}
+
+	_, err := tx.Exec(`INSERT INTO comment_mention (comment_id, user_id, start_index, length)
+    SELECT $1, u.id, m.start_index, m.length
+    FROM unnest($2::uuid[], $3::int[], $4::int[]) AS m(user_uuid, start_index, length)
+    JOIN app_user AS u ON u.uuid = m.user_uuid`,
+		commentID, pq.Array(userUUIDs), pq.Array(offsets), pq.Array(lengths))
+	return err
+}
+
+// postVisible limits to posts the viewer ($1) can read, by the same rules as
+// the post queries. The query must alias the post as p and its author as pu.
+var postVisible = `p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL
+  AND ` + post.VisibleTo("p", "pu") + ` AND ` + post.NotBlocked("pu")
+
+// GetPostAuthorUUID answers ErrPostNotFound as well when the viewer cannot
+// see the post.
+func (r *CommentRepository) GetPostAuthorUUID(postUUID, viewerUUID string) (string, error) {
+	query := `
+  SELECT pu.uuid
+  FROM post AS p
+  JOIN app_user AS pu ON pu.id = p.app_user_id
+  WHERE p.uuid = $2 AND ` + postVisible
+
+	var authorUUID string
+	err := r.db.QueryRow(query, viewerUUID, postUUID).Scan(&authorUUID)
+	if err == sql.ErrNoRows {
+		return "", ErrPostNotFound
+	}
+	return authorUUID, err
+}
+
+// notBlocked is false when userAlias and the viewer have blocked each other
+// in either direction.
+func notBlocked(userAlias, viewer string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+    SELECT 1 FROM blocks AS b
+    WHERE (b.blocker_id = %[1]s.id AND b.blocked_id = (SELECT id FROM app_user WHERE uuid = %[2]s))
+    OR (b.blocked_id = %[1]s.id AND b.blocker_id = (SELECT id FROM app_user WHERE uuid = %[2]s))
+  )`, userAlias, viewer)
+}
+
+// commentColumns is the select list read back by scanComments. The query
+// must alias the comment as c, its author as u, its post as p, the post
+// author as pu, its parent as pc and pass the viewer as $1. Replies from users blocked either way are
+// left out of num_reply, the same as they are left out of the replies.
+var commentColumns = `c.uuid, c.content, u.username, u.uuid, p.uuid, c.updated_at, pc.uuid,
+  (SELECT count(*) FROM comment AS rc
+    JOIN app_user AS ru ON ru.id = rc.app_user_id
+    WHERE rc.parent_id = c.id AND rc.deleted_at IS NULL AND rc.hidden_at IS NULL
+    AND ` + notBlocked("ru", "$1") + `),
+  (SELECT json_object_agg(rt.type, rt.n)
+    FROM (SELECT type, count(*) AS n FROM reaction WHERE comment_id = c.id GROUP BY type) AS rt),
+  (SELECT type FROM reaction
+    WHERE comment_id = c.id AND app_user_id = (SELECT id FROM app_user WHERE uuid = $1))`
+
+const commentJoins = `
+  JOIN app_user AS u ON u.id = c.app_user_id
+  JOIN post AS p ON p.id = c.post_id
+  JOIN app_user AS pu ON pu.id = p.app_user_id
+  LEFT JOIN comment AS pc ON pc.id = c.parent_id`
+
+func scanComments(rows *sql.Rows) ([]CommentResponse, error) {
+	defer rows.Close()
+
+	comments := []CommentResponse{}
+	for rows.Next() {
+		var c CommentResponse
+		err := rows.Scan(&c.UUID, &c.Content, &c.Username, &c.UserUUID, &c.PostUUID, &c.UpdateAt, &c.ParentUUID, &c.NumReply,
+			&c.Reactions, &c.MyReaction)
+		if err != nil {
+			return comments, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// GetComment answers ErrCommentNotFound as well when the viewer cannot see
+// the post of the comment.
+func (r *CommentRepository) GetComment(commentUUID, viewerUUID string) (CommentRef, error) {
+	query := `
+  SELECT c.uuid, u.uuid, p.uuid, pu.uuid
+  FROM comment AS c
+  JOIN app_user AS u ON u.id = c.app_user_id
+  JOIN post AS p ON p.id = c.post_id
+  JOIN app_user AS pu ON pu.id = p.app_user_id
+  WHERE c.uuid = $2 AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+  AND ` + postVisible
+
+	var ref CommentRef
+	err := r.db.QueryRow(query, viewerUUID, commentUUID).Scan(&ref.UUID, &ref.AuthorUUID, &ref.PostUUID, &ref.PostAuthorUUID)
+	if err == sql.ErrNoRows {
+		return CommentRef{}, ErrCommentNotFound
+	}
+	return ref, err
+}
+
+// GetCommentsByPostUUID returns the top level comments of a post, replies
+// are reached through GetReplies and GetThread.
+func (r *CommentRepository) GetCommentsByPostUUID(postUUID, viewerUUID string) ([]CommentResponse, error) {
+	query := `
+  SELECT ` + commentColumns + `
+  FROM comment AS c` + commentJoins + `
+  WHERE p.uuid = $2 AND c.parent_id IS NULL AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+  AND ` + notBlocked("u", "$1") + ` AND ` + postVisible + `
+  ORDER BY c.id
+  `
+
+	rows, err := r.db.Query(query, viewerUUID, postUUID)
+	if err != nil {
+		return nil, err
+	}
+	return scanComments(rows)
+}
+
+func (r *CommentRepository) GetReplies(parentUUID, viewerUUID string, limit, offset int) ([]CommentResponse, error) {
+	query := `
+  SELECT ` + commentColumns + `
+  FROM comment AS c` + commentJoins + `
+  WHERE pc.uuid = $2 AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+  AND ` + notBlocked("u", "$1") + ` AND ` + postVisible + `
+  ORDER BY c.id
+  LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, viewerUUID, parentUUID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanComments(rows)
+}
+
+// GetThread walks down from a comment at most depth levels, loading the
+// first limit replies of every comment on the way. The comments come back
+// flat, ordered by level, the root first.
+func (r *CommentRepository) GetThread(commentUUID, viewerUUID string, depth, limit int) ([]CommentResponse, error) {
+	query := `
+  WITH RECURSIVE thread AS (
+    SELECT c.id, 0 AS depth
+    FROM comment AS c
+    JOIN app_user AS u ON u.id = c.app_user_id
+    WHERE c.uuid = $2 AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+    AND ` + notBlocked("u", "$1") + `
+    UNION ALL
+    SELECT rc.id, t.depth + 1
+    FROM thread AS t
+    CROSS JOIN LATERAL (
+      SELECT rc.id
+      FROM comment AS rc
+      JOIN app_user AS ru ON ru.id = rc.app_user_id
+      WHERE rc.parent_id = t.id AND rc.deleted_at IS NULL AND rc.hidden_at IS NULL
+      AND ` + notBlocked("ru", "$1") + `
+      ORDER BY rc.id
+      LIMIT $4
+    ) AS rc
+    WHERE t.depth < $3
+  )
+  SELECT ` + commentColumns + `
+  FROM thread AS t
+  JOIN comment AS c ON c.id = t.id` + commentJoins + `
+  WHERE ` + postVisible + `
+  ORDER BY t.depth, c.id`
+
+	rows, err := r.db.Query(query, viewerUUID, commentUUID, depth, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanComments(rows)
+}
@@ -0,0 +1,202 @@
+package comment
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateComment(t *testing.T) {
+	c := CommentCreated{
+		UUID:     "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11",
+		Content:  "nice",
+		UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		PostUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO comment").WithArgs(c.UUID, c.Content, c.UserUUID, c.PostUUID, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	repo := NewCommentRepository(db)
+	id, err := repo.CreateComment(c)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, int64(1), id, "Want %v but got %v", 1, id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateComment_WithMentions(t *testing.T) {
+	c := CommentCreated{
+		UUID:     "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11",
+		Content:  "@ong nice",
+		UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		PostUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+		Mentions: []Mention{{"f6630558-b800-48ff-9a09-5863d6055154", 0, 4}},
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO comment").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec("INSERT INTO comment_mention").
+		WithArgs(3, pq.Array([]string{"f6630558-b800-48ff-9a09-5863d6055154"}), pq.Array([]int64{0}), pq.Array([]int64{4})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewCommentRepository(db)
+	_, err := repo.CreateComment(c)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPostAuthorUUID(t *testing.T) {
+	testTable := []struct {
+		title   string
+		rows    *sqlmock.Rows
+		want    string
+		wantErr error
+	}{
+		{"should return author", sqlmock.NewRows([]string{"uuid"}).AddRow("e936e164-52fa-4fd5-b0e0-597c2f270245"), "e936e164-52fa-4fd5-b0e0-597c2f270245", nil},
+		{"should post not found", sqlmock.NewRows([]string{"uuid"}), "", ErrPostNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("SELECT pu.uuid (.+)visibility_type_id (.+) FROM blocks").
+				WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154").WillReturnRows(v.rows)
+
+			repo := NewCommentRepository(db)
+			author, err := repo.GetPostAuthorUUID("f6630558-b800-48ff-9a09-5863d6055154", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, author, "Want %v but got %v", v.want, author)
+		})
+	}
+}
+
+var commentColumnNames = []string{"uuid", "content", "username", "uuid", "uuid", "updated_at", "uuid", "count", "reactions", "type"}
+
+func TestCreateComment_Reply(t *testing.T) {
+	c := CommentCreated{
+		UUID:       "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11",
+		Content:    "agree",
+		UserUUID:   "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		PostUUID:   "f6630558-b800-48ff-9a09-5863d6055154",
+		ParentUUID: "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f",
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO comment").WithArgs(c.UUID, c.Content, c.UserUUID, c.PostUUID, c.ParentUUID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	repo := NewCommentRepository(db)
+	_, err := repo.CreateComment(c)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetComment(t *testing.T) {
+	testTable := []struct {
+		title   string
+		rows    *sqlmock.Rows
+		want    CommentRef
+		wantErr error
+	}{
+		{
+			"should return comment",
+			sqlmock.NewRows([]string{"uuid", "uuid", "uuid", "uuid"}).
+				AddRow("a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", "7a053eee-a70d-442c-81ba-c36d72d3f87b"),
+			CommentRef{"a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", "7a053eee-a70d-442c-81ba-c36d72d3f87b"},
+			nil,
+		},
+		{"should comment not found", sqlmock.NewRows([]string{"uuid", "uuid", "uuid", "uuid"}), CommentRef{}, ErrCommentNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("FROM comment AS c (.+)visibility_type_id (.+) FROM blocks").
+				WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f").WillReturnRows(v.rows)
+
+			repo := NewCommentRepository(db)
+			ref, err := repo.GetComment("a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, ref, "Want %v but got %v", v.want, ref)
+		})
+	}
+}
+
+func TestGetReplies(t *testing.T) {
+	updateAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("WHERE pc.uuid = (.+)visibility_type_id (.+) LIMIT").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", 20, 40).
+		WillReturnRows(sqlmock.NewRows(commentColumnNames).
+			AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "agree", "ong", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", updateAt, "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", 0, nil, nil))
+
+	repo := NewCommentRepository(db)
+	replies, err := repo.GetReplies("a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 40)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Len(t, replies, 1)
+	assert.Equal(t, "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", *replies[0].ParentUUID)
+}
+
+func TestGetThread(t *testing.T) {
+	updateAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("WITH RECURSIVE thread (.+) WHERE p.deleted_at IS NULL (.+)visibility_type_id").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", 3, ThreadReplyLimit).
+		WillReturnRows(sqlmock.NewRows(commentColumnNames).
+			AddRow("a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "nice", "bob", "7a053eee-a70d-442c-81ba-c36d72d3f87b", "f6630558-b800-48ff-9a09-5863d6055154", updateAt, nil, 1, nil, nil).
+			AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "agree", "ong", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", updateAt, "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", 0, nil, nil))
+
+	repo := NewCommentRepository(db)
+	comments, err := repo.GetThread("a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f", "e936e164-52fa-4fd5-b0e0-597c2f270245", 3, ThreadReplyLimit)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Len(t, comments, 2)
+	assert.Nil(t, comments[0].ParentUUID)
+	assert.Equal(t, int64(1), comments[0].NumReply)
+}
+
+func TestGetCommentsByPostUUID(t *testing.T) {
+	updateAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("c.parent_id IS NULL (.+)visibility_type_id").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154").
+		WillReturnRows(sqlmock.NewRows(commentColumnNames).
+			AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "nice", "ong", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", updateAt, nil, 2, `{"like":1}`, "like"))
+
+	repo := NewCommentRepository(db)
+	comments, err := repo.GetCommentsByPostUUID("f6630558-b800-48ff-9a09-5863d6055154", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+	want := []CommentResponse{{
+		UUID: "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", Content: "nice", Username: "ong", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		PostUUID: "f6630558-b800-48ff-9a09-5863d6055154", UpdateAt: updateAt, NumReply: 2,
+		Reactions: post.ReactionCounts{"like": 1}, MyReaction: util.Ptr("like"),
+	}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, comments, "Want %v but got %v", want, comments)
+}
+
+func TestGetCommentsByPostUUID_Error(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("FROM comment").WillReturnError(sql.ErrConnDone)
+
+	repo := NewCommentRepository(db)
+	_, err := repo.GetCommentsByPostUUID("f6630558-b800-48ff-9a09-5863d6055154", "")
+	assert.Equalf(t, sql.ErrConnDone, err, "Unexpected error: %v", err)
+}
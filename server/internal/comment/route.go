@@ -0,0 +1,24 @@
+package comment
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ICommentHandler interface {
+	CreateComment(http.ResponseWriter, *http.Request)
+	GetCommentsByPostUUID(http.ResponseWriter, *http.Request)
+	GetReplies(http.ResponseWriter, *http.Request)
+	GetThread(http.ResponseWriter, *http.Request)
+}
+
+func RegisterCommentRouter(router *mux.Router, commentHandler ICommentHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/comment").Subrouter()
+	srouter.Use(authMiddleware)
+
+	srouter.HandleFunc("", commentHandler.GetCommentsByPostUUID).Methods(http.MethodGet)
+	srouter.HandleFunc("", commentHandler.CreateComment).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}/replies", commentHandler.GetReplies).Methods(http.MethodGet)
+	srouter.HandleFunc("/{uuid}/thread", commentHandler.GetThread).Methods(http.MethodGet)
+}
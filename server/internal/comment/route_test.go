@@ -0,0 +1,52 @@
+package comment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) CreateComment(http.ResponseWriter, *http.Request) {
+	m.called["CreateComment"] = true
+}
+
+func (m *MockHandler) GetCommentsByPostUUID(http.ResponseWriter, *http.Request) {
+	m.called["GetCommentsByPostUUID"] = true
+}
+
+func (m *MockHandler) GetReplies(http.ResponseWriter, *http.Request) {
+	m.called["GetReplies"] = true
+}
+
+func (m *MockHandler) GetThread(http.ResponseWriter, *http.Request) {
+	m.called["GetThread"] = true
+}
+
+func TestRoute(t *testing.T) {
+	routes := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/comment", "GetCommentsByPostUUID"},
+		{http.MethodPost, "/comment", "CreateComment"},
+		{http.MethodGet, "/comment/a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f/replies", "GetReplies"},
+		{http.MethodGet, "/comment/a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f/thread", "GetThread"},
+	}
+
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterCommentRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	for _, v := range routes {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.handler], "%v not called", v.handler)
+	}
+}
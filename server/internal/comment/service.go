@@ -1,9 +1,285 @@
 package comment
 
+import (
+	"errors"
+	"log"
+
+	"github.com/dsypasit/social-clone/server/internal/filter"
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBlocked        = errors.New("user is blocked")
+	ErrParentMismatch = errors.New("parent comment belongs to another post")
+)
+
+type ICommentRepository interface {
+	CreateComment(CommentCreated) (int64, error)
+	GetPostAuthorUUID(postUUID, viewerUUID string) (string, error)
+	GetCommentsByPostUUID(postUUID, viewerUUID string) ([]CommentResponse, error)
+	GetComment(commentUUID, viewerUUID string) (CommentRef, error)
+	GetReplies(parentUUID, viewerUUID string, limit, offset int) ([]CommentResponse, error)
+	GetThread(commentUUID, viewerUUID string, depth, limit int) ([]CommentResponse, error)
+}
+
+type IBlockServiceForComment interface {
+	IsBlocked(userUUID, otherUUID string) (bool, error)
+}
+
+type IUserServiceForComment interface {
+	GetUserByUsername(string) (user.User, error)
+}
+
+type INotificationServiceForComment interface {
+	Notify(notification.Event) error
+}
+
+type IContentFilterForComment interface {
+	Check(filter.Content) filter.Result
+	Flag(targetType, targetUUID string, res filter.Result) error
+}
+
 type CommentService struct {
-	commentRepo *CommentRepository
+	commentRepo         ICommentRepository
+	blockService        IBlockServiceForComment
+	userService         IUserServiceForComment
+	notificationService INotificationServiceForComment
+	contentFilter       IContentFilterForComment
+	contentRule         util.TextRule
+}
+
+func NewCommentService(commentRepo ICommentRepository, blockService IBlockServiceForComment,
+	userService IUserServiceForComment, notificationService INotificationServiceForComment,
+	contentFilter IContentFilterForComment, contentRule util.TextRule,
+) *CommentService {
+	return &CommentService{commentRepo, blockService, userService, notificationService, contentFilter, contentRule}
+}
+
+func (s *CommentService) CreateComment(c CommentCreated) (int64, error) {
+	content, err := s.contentRule.Sanitize(c.Content)
+	if err != nil {
+		return 0, err
+	}
+	c.Content = content
+
+	var parent CommentRef
+	if c.ParentUUID != "" {
+		parent, err = s.commentRepo.GetComment(c.ParentUUID, c.UserUUID)
+		if err != nil {
+			return 0, err
+		}
+		if c.PostUUID != "" && c.PostUUID != parent.PostUUID {
+			return 0, ErrParentMismatch
+		}
+		c.PostUUID = parent.PostUUID
+	}
+
+	authorUUID, err := s.commentRepo.GetPostAuthorUUID(c.PostUUID, c.UserUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.checkBlocked(c.UserUUID, authorUUID, parent.AuthorUUID); err != nil {
+		return 0, err
+	}
+	screened := s.contentFilter.Check(filter.Content{TargetType: filter.TargetComment, UserUUID: c.UserUUID, Text: c.Content})
+	if screened.Verdict == filter.Reject {
+		return 0, screened.Reason
+	}
+
+	c.UUID = uuid.NewString()
+	c.Mentions, err = s.resolveMentions(c.Content)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.commentRepo.CreateComment(c)
+	if err != nil {
+		return 0, err
+	}
+	// the comment stands either way, a failed flag is only logged
+	if err := s.contentFilter.Flag(filter.TargetComment, c.UUID, screened); err != nil {
+		log.Println("failed to flag comment:", err)
+	}
+	s.notifyAuthor(c, authorUUID)
+	if parent.AuthorUUID != "" && parent.AuthorUUID != authorUUID {
+		s.notifyParentAuthor(c, parent.AuthorUUID)
+	}
+	s.notifyMentioned(c)
+	return id, nil
+}
+
+// checkBlocked fails when the user and any of the others have blocked each
+// other. Empty uuids are skipped.
+func (s *CommentService) checkBlocked(userUUID string, others ...string) error {
+	for _, other := range others {
+		if other == "" {
+			continue
+		}
+		blocked, err := s.blockService.IsBlocked(userUUID, other)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrBlocked
+		}
+	}
+	return nil
+}
+
+func (s *CommentService) notifyParentAuthor(c CommentCreated, parentAuthorUUID string) {
+	err := s.notificationService.Notify(notification.Event{
+		Type:          notification.TypeComment,
+		ActorUUID:     c.UserUUID,
+		RecipientUUID: parentAuthorUUID,
+		TargetType:    notification.TargetComment,
+		TargetUUID:    c.ParentUUID,
+	})
+	if err != nil {
+		log.Println("failed to notify reply:", err)
+	}
+}
+
+func (s *CommentService) notifyAuthor(c CommentCreated, authorUUID string) {
+	err := s.notificationService.Notify(notification.Event{
+		Type:          notification.TypeComment,
+		ActorUUID:     c.UserUUID,
+		RecipientUUID: authorUUID,
+		TargetType:    notification.TargetPost,
+		TargetUUID:    c.PostUUID,
+	})
+	if err != nil {
+		log.Println("failed to notify comment:", err)
+	}
+}
+
+func (s *CommentService) resolveMentions(content string) ([]Mention, error) {
+	var mentions []Mention
+	resolved := map[string]string{}
+	for _, t := range util.ExtractMentions(content) {
+		userUUID, ok := resolved[t.Username]
+		if !ok {
+			u, err := s.userService.GetUserByUsername(t.Username)
+			if err == user.ErrUserNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			userUUID = u.UUID
+			resolved[t.Username] = userUUID
+		}
+		mentions = append(mentions, Mention{userUUID, t.Offset, t.Length})
+	}
+	return mentions, nil
+}
+
+// notifyMentioned sends one notification per mentioned user who can read the
+// post. The comment is already stored, so failures are only logged.
+func (s *CommentService) notifyMentioned(c CommentCreated) {
+	notified := map[string]bool{}
+	for _, m := range c.Mentions {
+		if notified[m.UserUUID] {
+			continue
+		}
+		notified[m.UserUUID] = true
+		_, err := s.commentRepo.GetPostAuthorUUID(c.PostUUID, m.UserUUID)
+		if err == ErrPostNotFound {
+			continue
+		}
+		if err != nil {
+			log.Println("failed to check mention visibility:", err)
+			continue
+		}
+		err = s.notificationService.Notify(notification.Event{
+			Type:          notification.TypeMention,
+			ActorUUID:     c.UserUUID,
+			RecipientUUID: m.UserUUID,
+			TargetType:    notification.TargetComment,
+			TargetUUID:    c.UUID,
+		})
+		if err != nil {
+			log.Println("failed to notify mention:", err)
+		}
+	}
 }
 
-func NewcommentService(commRepo *CommentRepository) *CommentService {
-	return &CommentService{commRepo}
+func (s *CommentService) GetCommentsByPostUUID(postUUID, viewerUUID string) ([]CommentResponse, error) {
+	authorUUID, err := s.commentRepo.GetPostAuthorUUID(postUUID, viewerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// a blocked viewer cannot see the post, so answer as if it does not exist
+	blocked, err := s.blockService.IsBlocked(viewerUUID, authorUUID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrPostNotFound
+	}
+
+	return s.commentRepo.GetCommentsByPostUUID(postUUID, viewerUUID)
+}
+
+// visibleComment looks up a comment the viewer may read, a blocked viewer
+// gets ErrCommentNotFound as if it did not exist.
+func (s *CommentService) visibleComment(commentUUID, viewerUUID string) (CommentRef, error) {
+	ref, err := s.commentRepo.GetComment(commentUUID, viewerUUID)
+	if err != nil {
+		return CommentRef{}, err
+	}
+
+	err = s.checkBlocked(viewerUUID, ref.PostAuthorUUID, ref.AuthorUUID)
+	if err == ErrBlocked {
+		return CommentRef{}, ErrCommentNotFound
+	}
+	return ref, err
+}
+
+func (s *CommentService) GetReplies(commentUUID, viewerUUID string, page util.Page) ([]CommentResponse, error) {
+	if _, err := s.visibleComment(commentUUID, viewerUUID); err != nil {
+		return nil, err
+	}
+	return s.commentRepo.GetReplies(commentUUID, viewerUUID, page.Limit, page.Offset)
+}
+
+func (s *CommentService) GetThread(commentUUID, viewerUUID string, depth int) (CommentResponse, error) {
+	if _, err := s.visibleComment(commentUUID, viewerUUID); err != nil {
+		return CommentResponse{}, err
+	}
+
+	depth = min(max(depth, 1), MaxThreadDepth)
+	comments, err := s.commentRepo.GetThread(commentUUID, viewerUUID, depth, ThreadReplyLimit)
+	if err != nil {
+		return CommentResponse{}, err
+	}
+	return buildThread(comments)
+}
+
+// buildThread nests the flat, level ordered comments of GetThread under
+// their parents.
+func buildThread(comments []CommentResponse) (CommentResponse, error) {
+	if len(comments) == 0 {
+		return CommentResponse{}, ErrCommentNotFound
+	}
+
+	replies := map[string][]CommentResponse{}
+	for _, c := range comments[1:] {
+		if c.ParentUUID != nil {
+			replies[*c.ParentUUID] = append(replies[*c.ParentUUID], c)
+		}
+	}
+
+	var attach func(c CommentResponse) CommentResponse
+	attach = func(c CommentResponse) CommentResponse {
+		for _, r := range replies[c.UUID] {
+			c.Replies = append(c.Replies, attach(r))
+		}
+		return c
+	}
+	return attach(comments[0]), nil
 }
@@ -0,0 +1,306 @@
+package comment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/filter"
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	authorErr error
+	repoErr   error
+	created   CommentCreated
+	refs      map[string]CommentRef
+	thread    []CommentResponse
+	depth     int
+	// hiddenFrom lists the viewers the post is not visible to.
+	hiddenFrom map[string]bool
+}
+
+func (m *MockRepo) CreateComment(c CommentCreated) (int64, error) {
+	m.created = c
+	return 1, m.repoErr
+}
+
+func (m *MockRepo) GetPostAuthorUUID(_, viewerUUID string) (string, error) {
+	if m.hiddenFrom[viewerUUID] {
+		return "", ErrPostNotFound
+	}
+	return "f6630558-b800-48ff-9a09-5863d6055154", m.authorErr
+}
+
+func (m *MockRepo) GetCommentsByPostUUID(string, string) ([]CommentResponse, error) {
+	return []CommentResponse{}, m.repoErr
+}
+
+func (m *MockRepo) GetComment(commentUUID, _ string) (CommentRef, error) {
+	ref, ok := m.refs[commentUUID]
+	if !ok {
+		return CommentRef{}, ErrCommentNotFound
+	}
+	return ref, nil
+}
+
+func (m *MockRepo) GetReplies(string, string, int, int) ([]CommentResponse, error) {
+	return []CommentResponse{}, m.repoErr
+}
+
+func (m *MockRepo) GetThread(_, _ string, depth, _ int) ([]CommentResponse, error) {
+	m.depth = depth
+	return m.thread, m.repoErr
+}
+
+type MockBlockSrv struct {
+	blocked bool
+	// blockedUsers blocks only these users when set
+	blockedUsers map[string]bool
+}
+
+func (m *MockBlockSrv) IsBlocked(_, other string) (bool, error) {
+	if m.blockedUsers != nil {
+		return m.blockedUsers[other], nil
+	}
+	return m.blocked, nil
+}
+
+var testContentRule = util.TextRule{MaxLength: 300, Multiline: true}
+
+type MockUserSrv struct{}
+
+func (m *MockUserSrv) GetUserByUsername(username string) (user.User, error) {
+	if username == "ong" {
+		return user.User{UUID: "f6630558-b800-48ff-9a09-5863d6055154", Username: "ong"}, nil
+	}
+	return user.User{}, user.ErrUserNotFound
+}
+
+type MockFilter struct {
+	result  filter.Result
+	flagged []string
+}
+
+func (m *MockFilter) Check(filter.Content) filter.Result {
+	return m.result
+}
+
+func (m *MockFilter) Flag(targetType, targetUUID string, res filter.Result) error {
+	if res.Verdict == filter.Flag {
+		m.flagged = append(m.flagged, targetUUID)
+	}
+	return nil
+}
+
+type MockNotificationSrv struct {
+	events []notification.Event
+}
+
+func (m *MockNotificationSrv) Notify(e notification.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func TestServiceCreateComment(t *testing.T) {
+	testTable := []struct {
+		title     string
+		content   string
+		authorErr error
+		blocked   bool
+		wantErr   error
+	}{
+		{"should create comment", "nice", nil, false, nil},
+		{"should post not found", "nice", ErrPostNotFound, false, ErrPostNotFound},
+		{"should not comment when blocked", "nice", nil, true, ErrBlocked},
+		{"should reject blank comment", " \u200b\u2060 ", nil, false, util.ErrTextEmpty},
+		{"should reject too long comment", strings.Repeat("a", 301), nil, false, util.ErrTextTooLong},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewCommentService(&MockRepo{authorErr: v.authorErr}, &MockBlockSrv{blocked: v.blocked}, &MockUserSrv{}, &MockNotificationSrv{}, &MockFilter{}, testContentRule)
+			_, err := s.CreateComment(CommentCreated{Content: v.content, UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceGetComments(t *testing.T) {
+	testTable := []struct {
+		title   string
+		blocked bool
+		wantErr error
+	}{
+		{"should return comments", false, nil},
+		{"should hide post from blocked viewer", true, ErrPostNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewCommentService(&MockRepo{}, &MockBlockSrv{blocked: v.blocked}, &MockUserSrv{}, &MockNotificationSrv{}, &MockFilter{}, testContentRule)
+			_, err := s.GetCommentsByPostUUID("f6630558-b800-48ff-9a09-5863d6055154", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceCreateComment_Mentions(t *testing.T) {
+	mRepo := MockRepo{}
+	mNotification := MockNotificationSrv{}
+	s := NewCommentService(&mRepo, &MockBlockSrv{}, &MockUserSrv{}, &mNotification, &MockFilter{}, testContentRule)
+	_, err := s.CreateComment(CommentCreated{Content: "@ong @ong @ghost", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+
+	wantMentions := []Mention{{"f6630558-b800-48ff-9a09-5863d6055154", 0, 4}, {"f6630558-b800-48ff-9a09-5863d6055154", 5, 4}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, wantMentions, mRepo.created.Mentions, "Want %v but got %v", wantMentions, mRepo.created.Mentions)
+	assert.Lenf(t, mNotification.events, 2, "want post author and one notification per mentioned user")
+	assert.Equal(t, notification.TargetComment, mNotification.events[1].TargetType)
+	assert.Equal(t, mRepo.created.UUID, mNotification.events[1].TargetUUID)
+}
+
+func TestServiceCreateComment_MentionCannotView(t *testing.T) {
+	mRepo := MockRepo{hiddenFrom: map[string]bool{"f6630558-b800-48ff-9a09-5863d6055154": true}}
+	mNotification := MockNotificationSrv{}
+	s := NewCommentService(&mRepo, &MockBlockSrv{}, &MockUserSrv{}, &mNotification, &MockFilter{}, testContentRule)
+	_, err := s.CreateComment(CommentCreated{Content: "@ong on a followers-only post", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Lenf(t, mRepo.created.Mentions, 1, "the mention is still stored")
+	for _, e := range mNotification.events {
+		assert.NotEqual(t, notification.TypeMention, e.Type, "a mention who cannot view the post is not notified")
+	}
+}
+
+func TestServiceCreateComment_NotifyAuthor(t *testing.T) {
+	mNotification := MockNotificationSrv{}
+	s := NewCommentService(&MockRepo{}, &MockBlockSrv{}, &MockUserSrv{}, &mNotification, &MockFilter{}, testContentRule)
+	_, err := s.CreateComment(CommentCreated{
+		Content: "nice", PostUUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+	})
+
+	want := notification.Event{
+		Type:          notification.TypeComment,
+		ActorUUID:     "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		RecipientUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+		TargetType:    notification.TargetPost,
+		TargetUUID:    "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+	}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []notification.Event{want}, mNotification.events)
+}
+
+// parentRef is a comment by bob on a post by ong.
+var parentRef = CommentRef{
+	UUID:           "a1c3e0d2-6f5b-4b8e-9a2d-3c4b5a6d7e8f",
+	AuthorUUID:     "7a053eee-a70d-442c-81ba-c36d72d3f87b",
+	PostUUID:       "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+	PostAuthorUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+}
+
+func TestServiceCreateComment_Reply(t *testing.T) {
+	testTable := []struct {
+		title        string
+		c            CommentCreated
+		blockedUsers map[string]bool
+		wantPost     string
+		wantEvents   int
+		wantErr      error
+	}{
+		{"should reply without post uuid", CommentCreated{ParentUUID: parentRef.UUID}, nil, parentRef.PostUUID, 2, nil},
+		{"should reply with matching post uuid", CommentCreated{ParentUUID: parentRef.UUID, PostUUID: parentRef.PostUUID}, nil, parentRef.PostUUID, 2, nil},
+		{"should reject parent from another post", CommentCreated{ParentUUID: parentRef.UUID, PostUUID: "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"}, nil, "", 0, ErrParentMismatch},
+		{"should parent not found", CommentCreated{ParentUUID: "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"}, nil, "", 0, ErrCommentNotFound},
+		{"should not reply when parent author blocked", CommentCreated{ParentUUID: parentRef.UUID}, map[string]bool{parentRef.AuthorUUID: true}, "", 0, ErrBlocked},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{refs: map[string]CommentRef{parentRef.UUID: parentRef}}
+			mNotification := MockNotificationSrv{}
+			s := NewCommentService(&mRepo, &MockBlockSrv{blockedUsers: v.blockedUsers}, &MockUserSrv{}, &mNotification, &MockFilter{}, testContentRule)
+			v.c.Content, v.c.UserUUID = "agree", "e936e164-52fa-4fd5-b0e0-597c2f270245"
+			_, err := s.CreateComment(v.c)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantPost, mRepo.created.PostUUID)
+			assert.Lenf(t, mNotification.events, v.wantEvents, "want post author and parent author notified")
+			if v.wantEvents == 2 {
+				assert.Equal(t, notification.TargetComment, mNotification.events[1].TargetType)
+				assert.Equal(t, parentRef.UUID, mNotification.events[1].TargetUUID)
+			}
+		})
+	}
+}
+
+func TestServiceGetThread(t *testing.T) {
+	root, reply, nested := parentRef.UUID, "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"
+	mRepo := MockRepo{
+		refs: map[string]CommentRef{root: parentRef},
+		thread: []CommentResponse{
+			{UUID: root, NumReply: 2},
+			{UUID: reply, ParentUUID: &root, NumReply: 1},
+			{UUID: "e8a1f1d4-2f0e-4a53-8c43-3a2b1c0d9e8f", ParentUUID: &root},
+			{UUID: nested, ParentUUID: &reply},
+		},
+	}
+	s := NewCommentService(&mRepo, &MockBlockSrv{}, &MockUserSrv{}, &MockNotificationSrv{}, &MockFilter{}, testContentRule)
+	thread, err := s.GetThread(root, "e936e164-52fa-4fd5-b0e0-597c2f270245", MaxThreadDepth+3)
+
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, MaxThreadDepth, mRepo.depth)
+	assert.Len(t, thread.Replies, 2)
+	assert.Equal(t, reply, thread.Replies[0].UUID)
+	assert.Len(t, thread.Replies[0].Replies, 1)
+	assert.Equal(t, nested, thread.Replies[0].Replies[0].UUID)
+}
+
+func TestServiceGetReplies(t *testing.T) {
+	testTable := []struct {
+		title        string
+		commentUUID  string
+		blockedUsers map[string]bool
+		wantErr      error
+	}{
+		{"should return replies", parentRef.UUID, nil, nil},
+		{"should comment not found", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", nil, ErrCommentNotFound},
+		{"should hide comment from blocked viewer", parentRef.UUID, map[string]bool{parentRef.AuthorUUID: true}, ErrCommentNotFound},
+		{"should hide comment when post author blocked", parentRef.UUID, map[string]bool{parentRef.PostAuthorUUID: true}, ErrCommentNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{refs: map[string]CommentRef{parentRef.UUID: parentRef}}
+			s := NewCommentService(&mRepo, &MockBlockSrv{blockedUsers: v.blockedUsers}, &MockUserSrv{}, &MockNotificationSrv{}, &MockFilter{}, testContentRule)
+			_, err := s.GetReplies(v.commentUUID, "e936e164-52fa-4fd5-b0e0-597c2f270245", util.Page{Limit: 20})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceCreateComment_ContentFilter(t *testing.T) {
+	testTable := []struct {
+		title       string
+		result      filter.Result
+		wantErr     error
+		wantFlagged int
+	}{
+		{"should create allowed comment", filter.Result{}, nil, 0},
+		{"should create and flag flagged comment", filter.Result{Verdict: filter.Flag, Reason: filter.ErrBlockedWord}, nil, 1},
+		{"should not create rejected comment", filter.Result{Verdict: filter.Reject, Reason: filter.ErrDuplicate}, filter.ErrDuplicate, 0},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{}
+			mFilter := MockFilter{result: v.result}
+			s := NewCommentService(&mRepo, &MockBlockSrv{}, &MockUserSrv{}, &MockNotificationSrv{}, &mFilter, testContentRule)
+			_, err := s.CreateComment(CommentCreated{Content: "nice", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Len(t, mFilter.flagged, v.wantFlagged)
+			assert.Equal(t, v.wantErr == nil, mRepo.created.UUID != "")
+		})
+	}
+}
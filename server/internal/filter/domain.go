@@ -0,0 +1,43 @@
+package filter
+
+import "errors"
+
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+)
+
+// Verdict is what a filter decides about content, ordered by severity.
+type Verdict int
+
+const (
+	Allow Verdict = iota
+	Flag
+	Reject
+)
+
+// Reasons a filter gives for a flag or a reject. Rejects are returned to the
+// author as errors, flags go to moderators as the report detail.
+var (
+	ErrBlockedWord = errors.New("content contains a blocked word")
+	ErrLinkSpam    = errors.New("content contains too many links")
+	ErrDuplicate   = errors.New("content duplicates a recent one")
+	ErrTooFrequent = errors.New("posting too frequently")
+)
+
+// Content is the text a user is about to store. Edit marks changes to
+// published content, which are not new posts to rate limit or deduplicate.
+// UUID is the stored row being rewritten, such as a draft, and is left out
+// when comparing against recent content.
+type Content struct {
+	TargetType string
+	UserUUID   string
+	Text       string
+	Edit       bool
+	UUID       string
+}
+
+type Result struct {
+	Verdict Verdict
+	Reason  error
+}
@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Blocklist matches whole words and phrases, ignoring case and punctuation,
+// so "spam" matches "SPAM!" but not "spammer".
+type Blocklist struct {
+	phrases []string
+	verdict Verdict
+}
+
+func NewBlocklist(words []string, verdict Verdict) *Blocklist {
+	b := &Blocklist{verdict: verdict}
+	for _, w := range words {
+		if w = normalizeWords(w); strings.TrimSpace(w) != "" {
+			b.phrases = append(b.phrases, w)
+		}
+	}
+	return b
+}
+
+func (b *Blocklist) Check(c Content) (Result, error) {
+	text := normalizeWords(c.Text)
+	for _, p := range b.phrases {
+		if strings.Contains(text, p) {
+			return Result{b.verdict, ErrBlockedWord}, nil
+		}
+	}
+	return Result{}, nil
+}
+
+// normalizeWords lowercases text and joins its words with single spaces,
+// padded with one on each side for whole word matching.
+func normalizeWords(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c) && !unicode.IsMark(c)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
+
+var linkRe = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkSpam flags content with more than flagAt links and rejects content with
+// more than rejectAt. Zero turns either off.
+type LinkSpam struct {
+	flagAt   int
+	rejectAt int
+}
+
+func NewLinkSpam(flagAt, rejectAt int) *LinkSpam {
+	return &LinkSpam{flagAt, rejectAt}
+}
+
+func (l *LinkSpam) Check(c Content) (Result, error) {
+	n := len(linkRe.FindAllStringIndex(c.Text, -1))
+	switch {
+	case l.rejectAt > 0 && n > l.rejectAt:
+		return Result{Reject, ErrLinkSpam}, nil
+	case l.flagAt > 0 && n > l.flagAt:
+		return Result{Flag, ErrLinkSpam}, nil
+	}
+	return Result{}, nil
+}
+
+type IFilterRepository interface {
+	HasRecentContent(targetType, userUUID, skipUUID, text string, window time.Duration) (bool, error)
+	CountRecent(targetType, userUUID, skipUUID string, window time.Duration) (int, error)
+}
+
+// Duplicate rejects new content that repeats what the same user stored
+// within the window, ignoring case and spacing.
+type Duplicate struct {
+	repo   IFilterRepository
+	window time.Duration
+}
+
+func NewDuplicate(repo IFilterRepository, window time.Duration) *Duplicate {
+	return &Duplicate{repo, window}
+}
+
+func (d *Duplicate) Check(c Content) (Result, error) {
+	if c.Edit || d.window <= 0 {
+		return Result{}, nil
+	}
+	dup, err := d.repo.HasRecentContent(c.TargetType, c.UserUUID, c.UUID, c.Text, d.window)
+	if err != nil || !dup {
+		return Result{}, err
+	}
+	return Result{Reject, ErrDuplicate}, nil
+}
+
+// Rate rejects new content past a per minute limit for its target type.
+// Deleted content still counts, deleting does not buy more posts.
+type Rate struct {
+	repo   IFilterRepository
+	limits map[string]int
+}
+
+func NewRate(repo IFilterRepository, limits map[string]int) *Rate {
+	return &Rate{repo, limits}
+}
+
+func (r *Rate) Check(c Content) (Result, error) {
+	limit := r.limits[c.TargetType]
+	if c.Edit || limit <= 0 {
+		return Result{}, nil
+	}
+	n, err := r.repo.CountRecent(c.TargetType, c.UserUUID, c.UUID, time.Minute)
+	if err != nil || n < limit {
+		return Result{}, err
+	}
+	return Result{Reject, ErrTooFrequent}, nil
+}
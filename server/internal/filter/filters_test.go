@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ongUUID = "e936e164-52fa-4fd5-b0e0-597c2f270245"
+
+type MockRepo struct {
+	dup       bool
+	count     int
+	repoErr   error
+	gotWindow time.Duration
+}
+
+func (m *MockRepo) HasRecentContent(targetType, userUUID, skipUUID, text string, window time.Duration) (bool, error) {
+	m.gotWindow = window
+	return m.dup, m.repoErr
+}
+
+func (m *MockRepo) CountRecent(targetType, userUUID, skipUUID string, window time.Duration) (int, error) {
+	m.gotWindow = window
+	return m.count, m.repoErr
+}
+
+func TestBlocklist(t *testing.T) {
+	b := NewBlocklist([]string{"Spam", "buy now", " "}, Reject)
+	testTable := []struct {
+		title string
+		text  string
+		want  Result
+	}{
+		{"should match word ignoring case", "this is SPAM!", Result{Reject, ErrBlockedWord}},
+		{"should match phrase across spacing", "Buy\n  now, cheap", Result{Reject, ErrBlockedWord}},
+		{"should not match inside word", "no spammers here", Result{}},
+		{"should not match split phrase", "buy it now", Result{}},
+		{"should allow clean text", "hello world", Result{}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			got, err := b.Check(Content{TargetType: TargetPost, UserUUID: ongUUID, Text: v.text})
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, got)
+		})
+	}
+}
+
+func TestLinkSpam(t *testing.T) {
+	l := NewLinkSpam(1, 3)
+	testTable := []struct {
+		title string
+		text  string
+		want  Result
+	}{
+		{"should allow one link", "see https://a.com", Result{}},
+		{"should flag past flag limit", "https://a.com and www.b.com", Result{Flag, ErrLinkSpam}},
+		{"should reject past reject limit", "http://a.com http://b.com http://c.com http://d.com", Result{Reject, ErrLinkSpam}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			got, err := l.Check(Content{TargetType: TargetPost, UserUUID: ongUUID, Text: v.text})
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, got)
+		})
+	}
+}
+
+func TestDuplicate(t *testing.T) {
+	testTable := []struct {
+		title   string
+		content Content
+		repo    MockRepo
+		want    Result
+		wantErr error
+	}{
+		{"should reject duplicate", Content{TargetPost, ongUUID, "hi", false, ""}, MockRepo{dup: true}, Result{Reject, ErrDuplicate}, nil},
+		{"should allow new content", Content{TargetPost, ongUUID, "hi", false, ""}, MockRepo{}, Result{}, nil},
+		{"should skip edits", Content{TargetPost, ongUUID, "hi", true, ""}, MockRepo{dup: true}, Result{}, nil},
+		{"should return repo error", Content{TargetPost, ongUUID, "hi", false, ""}, MockRepo{repoErr: errors.New("db down")}, Result{}, errors.New("db down")},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			d := NewDuplicate(&v.repo, 10*time.Minute)
+			got, err := d.Check(v.content)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, got)
+		})
+	}
+
+	mRepo := MockRepo{}
+	NewDuplicate(&mRepo, 10*time.Minute).Check(Content{TargetType: TargetPost, UserUUID: ongUUID, Text: "hi"})
+	assert.Equal(t, 10*time.Minute, mRepo.gotWindow)
+}
+
+func TestRate(t *testing.T) {
+	limits := map[string]int{TargetPost: 3}
+	testTable := []struct {
+		title   string
+		content Content
+		count   int
+		want    Result
+	}{
+		{"should allow under limit", Content{TargetPost, ongUUID, "hi", false, ""}, 2, Result{}},
+		{"should reject at limit", Content{TargetPost, ongUUID, "hi", false, ""}, 3, Result{Reject, ErrTooFrequent}},
+		{"should skip edits", Content{TargetPost, ongUUID, "hi", true, ""}, 3, Result{}},
+		{"should skip target without limit", Content{TargetComment, ongUUID, "hi", false, ""}, 30, Result{}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			r := NewRate(&MockRepo{count: v.count}, limits)
+			got, err := r.Check(v.content)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, got)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// recentQuery scopes a target table to one user within a window of $2
+// seconds, leaving out the row $3 when set. The cutoff is taken in SQL since
+// the timestamps are in the database zone. Comments are never edited, their
+// updated_at is when they were written.
+var recentQuery = map[string]string{
+	TargetPost:    "FROM post WHERE app_user_id = (SELECT id FROM app_user WHERE uuid = $1) AND created_at >= CURRENT_TIMESTAMP - make_interval(secs => $2) AND uuid IS DISTINCT FROM $3",
+	TargetComment: "FROM comment WHERE app_user_id = (SELECT id FROM app_user WHERE uuid = $1) AND updated_at >= CURRENT_TIMESTAMP - make_interval(secs => $2) AND uuid IS DISTINCT FROM $3",
+}
+
+type FilterRepository struct {
+	db *sql.DB
+}
+
+func NewFilterRepository(db *sql.DB) *FilterRepository {
+	return &FilterRepository{db}
+}
+
+func (r *FilterRepository) HasRecentContent(targetType, userUUID, skipUUID, text string, window time.Duration) (bool, error) {
+	from, ok := recentQuery[targetType]
+	if !ok {
+		return false, fmt.Errorf("unknown target type %q", targetType)
+	}
+	query := `SELECT EXISTS (SELECT 1 ` + from + ` AND deleted_at IS NULL
+    AND lower(regexp_replace(content, '\s+', ' ', 'g')) = lower(regexp_replace($4, '\s+', ' ', 'g')))`
+
+	var exist bool
+	err := r.db.QueryRow(query, userUUID, window.Seconds(), nullUUID(skipUUID), text).Scan(&exist)
+	return exist, err
+}
+
+func (r *FilterRepository) CountRecent(targetType, userUUID, skipUUID string, window time.Duration) (int, error) {
+	from, ok := recentQuery[targetType]
+	if !ok {
+		return 0, fmt.Errorf("unknown target type %q", targetType)
+	}
+	var count int
+	err := r.db.QueryRow("SELECT count(*) "+from, userUUID, window.Seconds(), nullUUID(skipUUID)).Scan(&count)
+	return count, err
+}
+
+// nullUUID passes an unset uuid as NULL, which no row matches.
+func nullUUID(uuid string) any {
+	if uuid == "" {
+		return nil
+	}
+	return uuid
+}
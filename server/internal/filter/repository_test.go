@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasRecentContent(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM comment (.+) AND updated_at >= CURRENT_TIMESTAMP - make_interval\\(secs => \\$2\\) AND uuid IS DISTINCT FROM \\$3 AND deleted_at IS NULL").
+		WithArgs(ongUUID, float64(600), nil, "hi").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	repo := NewFilterRepository(db)
+	dup, err := repo.HasRecentContent(TargetComment, ongUUID, "", "hi", 10*time.Minute)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, dup)
+
+	_, err = repo.HasRecentContent("group", ongUUID, "", "hi", 10*time.Minute)
+	assert.NotNil(t, err, "unknown target type should error")
+}
+
+func TestCountRecent(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM post (.+) AND created_at >= CURRENT_TIMESTAMP - make_interval\\(secs => \\$2\\) AND uuid IS DISTINCT FROM \\$3").
+		WithArgs(ongUUID, float64(60), "f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	repo := NewFilterRepository(db)
+	n, err := repo.CountRecent(TargetPost, ongUUID, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", time.Minute)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, 4, n)
+
+	_, err = repo.CountRecent("group", ongUUID, "", time.Minute)
+	assert.NotNil(t, err, "unknown target type should error")
+}
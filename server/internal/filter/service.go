@@ -0,0 +1,50 @@
+package filter
+
+import "log"
+
+type Filter interface {
+	Check(Content) (Result, error)
+}
+
+type IFlaggerForFilter interface {
+	Flag(targetType, targetUUID, reason string) error
+}
+
+// FilterService runs content through its filters before the post and
+// comment services store it, and files what it flags for moderators.
+type FilterService struct {
+	filters []Filter
+	flagger IFlaggerForFilter
+}
+
+func NewFilterService(flagger IFlaggerForFilter, filters ...Filter) *FilterService {
+	return &FilterService{filters, flagger}
+}
+
+// Check returns the first reject, else the first flag. A filter that fails
+// to check is logged and skipped, an outage should not stop all posting.
+func (s *FilterService) Check(c Content) Result {
+	var res Result
+	for _, f := range s.filters {
+		r, err := f.Check(c)
+		if err != nil {
+			log.Println("content filter failed:", err)
+			continue
+		}
+		if r.Verdict == Reject {
+			return r
+		}
+		if r.Verdict == Flag && res.Verdict == Allow {
+			res = r
+		}
+	}
+	return res
+}
+
+// Flag queues stored content for moderation when its result was a flag.
+func (s *FilterService) Flag(targetType, targetUUID string, res Result) error {
+	if res.Verdict != Flag {
+		return nil
+	}
+	return s.flagger.Flag(targetType, targetUUID, res.Reason.Error())
+}
@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockFilter struct {
+	result Result
+	err    error
+	called bool
+}
+
+func (m *MockFilter) Check(Content) (Result, error) {
+	m.called = true
+	return m.result, m.err
+}
+
+type MockFlagger struct {
+	targetUUID string
+	reason     string
+}
+
+func (m *MockFlagger) Flag(targetType, targetUUID, reason string) error {
+	m.targetUUID, m.reason = targetUUID, reason
+	return nil
+}
+
+func TestServiceCheck(t *testing.T) {
+	flag := Result{Flag, ErrLinkSpam}
+	reject := Result{Reject, ErrDuplicate}
+	testTable := []struct {
+		title   string
+		filters []*MockFilter
+		want    Result
+	}{
+		{"should allow without filters", nil, Result{}},
+		{"should allow when all allow", []*MockFilter{{}, {}}, Result{}},
+		{"should flag", []*MockFilter{{}, {result: flag}}, flag},
+		{"should reject over flag", []*MockFilter{{result: flag}, {result: reject}}, reject},
+		{"should skip failed filter", []*MockFilter{{result: reject, err: errors.New("db down")}, {result: flag}}, flag},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			var filters []Filter
+			for _, f := range v.filters {
+				filters = append(filters, f)
+			}
+			s := NewFilterService(&MockFlagger{}, filters...)
+			got := s.Check(Content{TargetType: TargetPost, UserUUID: ongUUID, Text: "hi"})
+			assert.Equal(t, v.want, got)
+		})
+	}
+}
+
+func TestServiceCheck_StopsAtReject(t *testing.T) {
+	after := MockFilter{}
+	s := NewFilterService(&MockFlagger{}, &MockFilter{result: Result{Reject, ErrBlockedWord}}, &after)
+	s.Check(Content{TargetType: TargetPost, UserUUID: ongUUID, Text: "hi"})
+	assert.False(t, after.called, "filters after a reject should not run")
+}
+
+func TestServiceFlag(t *testing.T) {
+	mFlagger := MockFlagger{}
+	s := NewFilterService(&mFlagger)
+
+	err := s.Flag(TargetPost, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", Result{})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Empty(t, mFlagger.targetUUID, "allowed content should not be flagged")
+
+	err = s.Flag(TargetPost, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", Result{Flag, ErrLinkSpam})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", mFlagger.targetUUID)
+	assert.Equal(t, ErrLinkSpam.Error(), mFlagger.reason)
+}
@@ -0,0 +1,9 @@
+package follow
+
+import "time"
+
+type FollowResponse struct {
+	UserUUID  string    `json:"user_uuid"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
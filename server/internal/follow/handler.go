@@ -0,0 +1,121 @@
+package follow
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IFollowService interface {
+	Follow(followerUUID, followedUUID string) (int64, error)
+	Unfollow(followerUUID, followedUUID string) error
+	GetFollowers(userUUID string, limit, offset int) ([]FollowResponse, error)
+	GetFollowing(userUUID string, limit, offset int) ([]FollowResponse, error)
+}
+
+type FollowHandler struct {
+	followService IFollowService
+}
+
+func NewFollowHandler(followService IFollowService) *FollowHandler {
+	return &FollowHandler{followService}
+}
+
+func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	followedUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(followedUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if _, err := h.followService.Follow(userUUID, followedUUID); err != nil {
+		switch err {
+		case ErrSelfFollow:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrUserNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrBlocked:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		case ErrAlreadyFollowed:
+			util.SendJson(w, util.BuildErrResponse("duplicate follow")(err), http.StatusConflict)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("followed successful!"), http.StatusCreated)
+}
+
+func (h *FollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	followedUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(followedUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.followService.Unfollow(userUUID, followedUUID); err != nil {
+		if err == ErrNotFollowed {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("unfollowed successful!"), http.StatusOK)
+}
+
+func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.followService.GetFollowers)
+}
+
+func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.followService.GetFollowing)
+}
+
+func (h *FollowHandler) list(w http.ResponseWriter, r *http.Request,
+	get func(userUUID string, limit, offset int) ([]FollowResponse, error),
+) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID := r.URL.Query().Get("useruuid")
+	if userUUID == "" {
+		userUUID, _ = r.Context().Value("userUUID").(string)
+	}
+	if !util.IsValidUUID(userUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	follows, err := get(userUUID, page.Limit, page.Offset)
+	if err != nil {
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, follows, http.StatusOK)
+}
@@ -0,0 +1,97 @@
+package follow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr error
+}
+
+func (m *mService) Follow(string, string) (int64, error) {
+	return 1, m.isErr
+}
+
+func (m *mService) Unfollow(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) GetFollowers(string, int, int) ([]FollowResponse, error) {
+	return []FollowResponse{}, m.isErr
+}
+
+func (m *mService) GetFollowing(string, int, int) ([]FollowResponse, error) {
+	return []FollowResponse{}, m.isErr
+}
+
+func TestHandlerFollow(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{"should follow user", "f6630558-b800-48ff-9a09-5863d6055154", nil, http.StatusCreated, util.BuildResponse("followed successful!")},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause yourself", "f6630558-b800-48ff-9a09-5863d6055154", ErrSelfFollow, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should not found", "f6630558-b800-48ff-9a09-5863d6055154", ErrUserNotFound, http.StatusNotFound, util.BuildErrResponse("not found")(nil)},
+		{"should forbidden cause blocked", "f6630558-b800-48ff-9a09-5863d6055154", ErrBlocked, http.StatusForbidden, util.BuildErrResponse("forbidden")(nil)},
+		{"should conflict", "f6630558-b800-48ff-9a09-5863d6055154", ErrAlreadyFollowed, http.StatusConflict, util.BuildErrResponse("duplicate follow")(nil)},
+		{"should service error", "f6630558-b800-48ff-9a09-5863d6055154", errors.New("service err"), http.StatusInternalServerError, util.BuildErrResponse("service error")(nil)},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewFollowHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.Follow(rec, req)
+
+			var res map[string]string
+			json.NewDecoder(rec.Body).Decode(&res)
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantBody["message"], res["message"], "Want %v but got %v", v.wantBody, res)
+		})
+	}
+}
+
+func TestHandlerGetFollowers(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return followers of caller", "", nil, http.StatusOK},
+		{"should return followers of user", "?useruuid=f6630558-b800-48ff-9a09-5863d6055154", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "?useruuid=abc", nil, http.StatusBadRequest},
+		{"should bad request cause invalid page", "?limit=-1", nil, http.StatusBadRequest},
+		{"should service failed", "", errors.New("service err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewFollowHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, "/"+v.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.GetFollowers(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package follow
+
+import (
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrAlreadyFollowed = errors.New("already followed")
+	ErrNotFollowed     = errors.New("not followed")
+)
+
+type FollowRepository struct {
+	db *sql.DB
+}
+
+func NewFollowRepository(db *sql.DB) *FollowRepository {
+	return &FollowRepository{db}
+}
+
+func (r *FollowRepository) Follow(followerUUID, followedUUID string) (int64, error) {
+	query := `INSERT INTO follows (follower_id, followed_id)
+    VALUES (
+        (SELECT id FROM app_user WHERE uuid = $1),
+        (SELECT id FROM app_user WHERE uuid = $2)
+    )
+    ON CONFLICT (follower_id, followed_id) DO NOTHING
+    RETURNING id`
+
+	var id int64
+	err := r.db.QueryRow(query, followerUUID, followedUUID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, ErrAlreadyFollowed
+	}
+	return id, err
+}
+
+func (r *FollowRepository) Unfollow(followerUUID, followedUUID string) error {
+	result, err := r.db.Exec(`DELETE FROM follows
+    WHERE follower_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND followed_id = (SELECT id FROM app_user WHERE uuid = $2)`, followerUUID, followedUUID)
+	if err != nil {
+		return err
+	}
+	numAffect, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numAffect == 0 {
+		return ErrNotFollowed
+	}
+	return nil
+}
+
+func (r *FollowRepository) GetFollowers(userUUID string, limit, offset int) ([]FollowResponse, error) {
+	return r.getFollows(`
+  SELECT u.uuid, u.username, f.created_at
+  FROM follows AS f
+  JOIN app_user AS u ON u.id = f.follower_id
+  WHERE f.followed_id = (SELECT id FROM app_user WHERE uuid = $1)
+  ORDER BY f.created_at DESC
+  LIMIT $2 OFFSET $3`, userUUID, limit, offset)
+}
+
+func (r *FollowRepository) GetFollowing(userUUID string, limit, offset int) ([]FollowResponse, error) {
+	return r.getFollows(`
+  SELECT u.uuid, u.username, f.created_at
+  FROM follows AS f
+  JOIN app_user AS u ON u.id = f.followed_id
+  WHERE f.follower_id = (SELECT id FROM app_user WHERE uuid = $1)
+  ORDER BY f.created_at DESC
+  LIMIT $2 OFFSET $3`, userUUID, limit, offset)
+}
+
+func (r *FollowRepository) getFollows(query string, args ...interface{}) ([]FollowResponse, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := []FollowResponse{}
+	for rows.Next() {
+		var f FollowResponse
+		if err := rows.Scan(&f.UserUUID, &f.Username, &f.CreatedAt); err != nil {
+			return follows, err
+		}
+		follows = append(follows, f)
+	}
+	return follows, rows.Err()
+}
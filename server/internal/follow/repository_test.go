@@ -0,0 +1,81 @@
+package follow
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollow(t *testing.T) {
+	testTable := []struct {
+		title   string
+		rows    *sqlmock.Rows
+		rowErr  error
+		wantID  int64
+		wantErr error
+	}{
+		{"should follow user", sqlmock.NewRows([]string{"id"}).AddRow(1), nil, 1, nil},
+		{"should already followed", nil, sql.ErrNoRows, 0, ErrAlreadyFollowed},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			exp := mock.ExpectQuery("INSERT INTO follows").
+				WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+			if v.rows != nil {
+				exp.WillReturnRows(v.rows)
+			} else {
+				exp.WillReturnError(v.rowErr)
+			}
+
+			repo := NewFollowRepository(db)
+			id, err := repo.Follow("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.wantID, id, "Want %v but got %v", v.wantID, id)
+		})
+	}
+}
+
+func TestUnfollow(t *testing.T) {
+	testTable := []struct {
+		title   string
+		affect  int64
+		wantErr error
+	}{
+		{"should unfollow", 1, nil},
+		{"should not followed", 0, ErrNotFollowed},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectExec("DELETE FROM follows").WillReturnResult(sqlmock.NewResult(0, v.affect))
+
+			repo := NewFollowRepository(db)
+			err := repo.Unfollow("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestGetFollowers(t *testing.T) {
+	createdAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("JOIN app_user AS u ON u.id = f.follower_id").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "username", "created_at"}).
+			AddRow("f6630558-b800-48ff-9a09-5863d6055154", "ong", createdAt))
+
+	repo := NewFollowRepository(db)
+	followers, err := repo.GetFollowers("e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0)
+	want := []FollowResponse{{"f6630558-b800-48ff-9a09-5863d6055154", "ong", createdAt}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, followers, "Want %v but got %v", want, followers)
+}
@@ -0,0 +1,24 @@
+package follow
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IFollowHandler interface {
+	Follow(http.ResponseWriter, *http.Request)
+	Unfollow(http.ResponseWriter, *http.Request)
+	GetFollowers(http.ResponseWriter, *http.Request)
+	GetFollowing(http.ResponseWriter, *http.Request)
+}
+
+func RegisterFollowRouter(router *mux.Router, followHandler IFollowHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/follow").Subrouter()
+	srouter.Use(authMiddleware)
+
+	srouter.HandleFunc("/followers", followHandler.GetFollowers).Methods(http.MethodGet)
+	srouter.HandleFunc("/following", followHandler.GetFollowing).Methods(http.MethodGet)
+	srouter.HandleFunc("/{uuid}", followHandler.Follow).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}", followHandler.Unfollow).Methods(http.MethodDelete)
+}
@@ -0,0 +1,53 @@
+package follow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) Follow(http.ResponseWriter, *http.Request) {
+	m.called["Follow"] = true
+}
+
+func (m *MockHandler) Unfollow(http.ResponseWriter, *http.Request) {
+	m.called["Unfollow"] = true
+}
+
+func (m *MockHandler) GetFollowers(http.ResponseWriter, *http.Request) {
+	m.called["GetFollowers"] = true
+}
+
+func (m *MockHandler) GetFollowing(http.ResponseWriter, *http.Request) {
+	m.called["GetFollowing"] = true
+}
+
+func TestRoute(t *testing.T) {
+	uuid := "f6630558-b800-48ff-9a09-5863d6055154"
+	routes := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/follow/followers", "GetFollowers"},
+		{http.MethodGet, "/follow/following", "GetFollowing"},
+		{http.MethodPost, "/follow/" + uuid, "Follow"},
+		{http.MethodDelete, "/follow/" + uuid, "Unfollow"},
+	}
+
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterFollowRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	for _, v := range routes {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.handler], "%v not called", v.handler)
+	}
+}
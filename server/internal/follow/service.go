@@ -0,0 +1,98 @@
+package follow
+
+import (
+	"errors"
+	"log"
+
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/user"
+)
+
+var (
+	ErrSelfFollow   = errors.New("cannot follow yourself")
+	ErrUserNotFound = errors.New("user not found")
+	ErrBlocked      = errors.New("user is blocked")
+)
+
+type IFollowRepository interface {
+	Follow(followerUUID, followedUUID string) (int64, error)
+	Unfollow(followerUUID, followedUUID string) error
+	GetFollowers(userUUID string, limit, offset int) ([]FollowResponse, error)
+	GetFollowing(userUUID string, limit, offset int) ([]FollowResponse, error)
+}
+
+type IUserServiceForFollow interface {
+	GetUserByUUID(string) (user.User, error)
+}
+
+type IBlockServiceForFollow interface {
+	IsBlocked(userUUID, otherUUID string) (bool, error)
+}
+
+type INotificationServiceForFollow interface {
+	Notify(notification.Event) error
+}
+
+type FollowService struct {
+	followRepo          IFollowRepository
+	userService         IUserServiceForFollow
+	blockService        IBlockServiceForFollow
+	notificationService INotificationServiceForFollow
+}
+
+func NewFollowService(followRepo IFollowRepository, userService IUserServiceForFollow,
+	blockService IBlockServiceForFollow, notificationService INotificationServiceForFollow,
+) *FollowService {
+	return &FollowService{followRepo, userService, blockService, notificationService}
+}
+
+func (s *FollowService) Follow(followerUUID, followedUUID string) (int64, error) {
+	if followerUUID == followedUUID {
+		return 0, ErrSelfFollow
+	}
+
+	_, err := s.userService.GetUserByUUID(followedUUID)
+	if err != nil {
+		if err == user.ErrUserNotFound {
+			return 0, ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	blocked, err := s.blockService.IsBlocked(followerUUID, followedUUID)
+	if err != nil {
+		return 0, err
+	}
+	if blocked {
+		return 0, ErrBlocked
+	}
+
+	id, err := s.followRepo.Follow(followerUUID, followedUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.notificationService.Notify(notification.Event{
+		Type:          notification.TypeFollow,
+		ActorUUID:     followerUUID,
+		RecipientUUID: followedUUID,
+		TargetType:    notification.TargetUser,
+		TargetUUID:    followedUUID,
+	})
+	if err != nil {
+		log.Println("failed to notify follow:", err)
+	}
+	return id, nil
+}
+
+func (s *FollowService) Unfollow(followerUUID, followedUUID string) error {
+	return s.followRepo.Unfollow(followerUUID, followedUUID)
+}
+
+func (s *FollowService) GetFollowers(userUUID string, limit, offset int) ([]FollowResponse, error) {
+	return s.followRepo.GetFollowers(userUUID, limit, offset)
+}
+
+func (s *FollowService) GetFollowing(userUUID string, limit, offset int) ([]FollowResponse, error) {
+	return s.followRepo.GetFollowing(userUUID, limit, offset)
+}
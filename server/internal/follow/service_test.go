@@ -0,0 +1,88 @@
+package follow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	repoErr error
+}
+
+func (m *MockRepo) Follow(string, string) (int64, error) {
+	return 1, m.repoErr
+}
+
+func (m *MockRepo) Unfollow(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) GetFollowers(string, int, int) ([]FollowResponse, error) {
+	return []FollowResponse{}, m.repoErr
+}
+
+func (m *MockRepo) GetFollowing(string, int, int) ([]FollowResponse, error) {
+	return []FollowResponse{}, m.repoErr
+}
+
+type MockUserSrv struct {
+	err error
+}
+
+func (m *MockUserSrv) GetUserByUUID(s string) (user.User, error) {
+	return user.User{UUID: s}, m.err
+}
+
+type MockBlockSrv struct {
+	blocked bool
+	err     error
+}
+
+func (m *MockBlockSrv) IsBlocked(string, string) (bool, error) {
+	return m.blocked, m.err
+}
+
+type MockNotificationSrv struct {
+	events []notification.Event
+}
+
+func (m *MockNotificationSrv) Notify(e notification.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func TestServiceFollow(t *testing.T) {
+	testTable := []struct {
+		title    string
+		follower string
+		followed string
+		userErr  error
+		blockSrv MockBlockSrv
+		wantErr  error
+	}{
+		{"should follow user", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", nil, MockBlockSrv{}, nil},
+		{"should not follow yourself", "e936e164-52fa-4fd5-b0e0-597c2f270245", "e936e164-52fa-4fd5-b0e0-597c2f270245", nil, MockBlockSrv{}, ErrSelfFollow},
+		{"should user not found", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", user.ErrUserNotFound, MockBlockSrv{}, ErrUserNotFound},
+		{"should not follow blocked user", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", nil, MockBlockSrv{blocked: true}, ErrBlocked},
+		{"should block service error", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154", nil, MockBlockSrv{err: errors.New("db err")}, errors.New("db err")},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mNotification := MockNotificationSrv{}
+			s := NewFollowService(&MockRepo{}, &MockUserSrv{v.userErr}, &v.blockSrv, &mNotification)
+			_, err := s.Follow(v.follower, v.followed)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			if v.wantErr == nil {
+				assert.Lenf(t, mNotification.events, 1, "want follow notification")
+				assert.Equal(t, notification.TypeFollow, mNotification.events[0].Type)
+			} else {
+				assert.Empty(t, mNotification.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package health
+
+import "errors"
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+const (
+	CheckDatabase  = "database"
+	CheckMigration = "migration"
+	CheckWorkers   = "workers"
+)
+
+var (
+	ErrCheckTimeout  = errors.New("check timed out")
+	ErrNoMigration   = errors.New("no migration applied")
+	ErrWorkerStopped = errors.New("worker stopped")
+)
+
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type Report struct {
+	Status   string        `json:"status"`
+	Draining bool          `json:"draining"`
+	Checks   []CheckResult `json:"checks,omitempty"`
+}
+
+type WorkerStatus struct {
+	Name    string
+	Running bool
+	Err     error
+}
@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+)
+
+type IHealthService interface {
+	Liveness() Report
+	Readiness(ctx context.Context) Report
+}
+
+type HealthHandler struct {
+	healthService IHealthService
+}
+
+func NewHealthHandler(healthService IHealthService) *HealthHandler {
+	return &HealthHandler{healthService}
+}
+
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	util.SendJson(w, h.healthService.Liveness(), http.StatusOK)
+}
+
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.healthService.Readiness(r.Context())
+	if report.Status != StatusOK {
+		util.SendJson(w, report, http.StatusServiceUnavailable)
+		return
+	}
+	util.SendJson(w, report, http.StatusOK)
+}
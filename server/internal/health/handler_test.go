@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	report Report
+}
+
+func (m *mService) Liveness() Report {
+	return Report{Status: StatusOK}
+}
+
+func (m *mService) Readiness(context.Context) Report {
+	return m.report
+}
+
+func TestLivez(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHealthHandler(&mService{}).Livez(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got Report
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, StatusOK, got.Status)
+}
+
+func TestReadyz(t *testing.T) {
+	testTable := []struct {
+		title      string
+		report     Report
+		wantStatus int
+	}{
+		{"should be ok", Report{Status: StatusOK, Checks: []CheckResult{{Name: CheckDatabase, Status: StatusOK}}}, http.StatusOK},
+		{"should be unavailable cause check failed", Report{Status: StatusFail, Checks: []CheckResult{{Name: CheckDatabase, Status: StatusFail}}}, http.StatusServiceUnavailable},
+		{"should be unavailable cause draining", Report{Status: StatusDraining, Draining: true}, http.StatusServiceUnavailable},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHealthHandler(&mService{v.report}).Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, v.wantStatus, rec.Code)
+			var got Report
+			assert.Nil(t, json.NewDecoder(rec.Body).Decode(&got))
+			assert.Equal(t, v.report, got)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+)
+
+type HealthRepository struct {
+	db *sql.DB
+}
+
+func NewHealthRepository(db *sql.DB) *HealthRepository {
+	return &HealthRepository{db}
+}
+
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// MigrationVersion is the latest version dbmate recorded as applied.
+func (r *HealthRepository) MigrationVersion(ctx context.Context) (string, error) {
+	var version sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT max(version) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return "", err
+	}
+	if !version.Valid {
+		return "", ErrNoMigration
+	}
+	return version.String, nil
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPing(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	defer db.Close()
+	mock.ExpectPing()
+
+	err := NewHealthRepository(db).Ping(context.Background())
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrationVersion(t *testing.T) {
+	testTable := []struct {
+		title   string
+		version any
+		want    string
+		wantErr error
+	}{
+		{"should return latest version", "20261020040000", "20261020040000", nil},
+		{"should fail when none applied", nil, "", ErrNoMigration},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("SELECT max\\(version\\) FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(v.version))
+
+			got, err := NewHealthRepository(db).MigrationVersion(context.Background())
+			assert.Equal(t, v.wantErr, err)
+			assert.Equal(t, v.want, got)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
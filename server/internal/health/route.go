@@ -0,0 +1,17 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IHealthHandler interface {
+	Livez(http.ResponseWriter, *http.Request)
+	Readyz(http.ResponseWriter, *http.Request)
+}
+
+func RegisterHealthRouter(router *mux.Router, healthHandler IHealthHandler) {
+	router.HandleFunc("/livez", healthHandler.Livez).Methods(http.MethodGet)
+	router.HandleFunc("/readyz", healthHandler.Readyz).Methods(http.MethodGet)
+}
@@ -0,0 +1,35 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	livezCalled  bool
+	readyzCalled bool
+}
+
+func (m *MockHandler) Livez(http.ResponseWriter, *http.Request) {
+	m.livezCalled = true
+}
+
+func (m *MockHandler) Readyz(http.ResponseWriter, *http.Request) {
+	m.readyzCalled = true
+}
+
+func TestRoute(t *testing.T) {
+	router := mux.NewRouter()
+	healthHandler := MockHandler{}
+	RegisterHealthRouter(router, &healthHandler)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.True(t, healthHandler.livezCalled, "livez handler not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.True(t, healthHandler.readyzCalled, "readyz handler not called")
+}
@@ -0,0 +1,126 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type IHealthRepository interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (string, error)
+}
+
+type IWorkerStatus interface {
+	Status() []WorkerStatus
+}
+
+type check struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+type HealthService struct {
+	checks   []check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewHealthService checks the database, the applied migration and the
+// background workers, each given timeout to answer.
+func NewHealthService(repo IHealthRepository, workers IWorkerStatus, timeout time.Duration) *HealthService {
+	return &HealthService{
+		checks: []check{
+			{CheckDatabase, func(ctx context.Context) (string, error) { return "", repo.Ping(ctx) }},
+			{CheckMigration, repo.MigrationVersion},
+			{CheckWorkers, func(context.Context) (string, error) { return checkWorkers(workers.Status()) }},
+		},
+		timeout: timeout,
+	}
+}
+
+// SetDraining marks the instance as shutting down, readiness fails from
+// then on so load balancers stop sending new requests.
+func (s *HealthService) SetDraining() {
+	s.draining.Store(true)
+}
+
+// Liveness only says the process serves requests, it checks no dependency
+// so an outage there does not get the instance restarted.
+func (s *HealthService) Liveness() Report {
+	return Report{Status: StatusOK, Draining: s.draining.Load()}
+}
+
+func (s *HealthService) Readiness(ctx context.Context) Report {
+	results := make([]CheckResult, len(s.checks))
+	var wg sync.WaitGroup
+	for i, c := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Draining: s.draining.Load(), Checks: results}
+	for _, r := range results {
+		if r.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	if report.Draining {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run gives up on a check after the timeout even when it ignores ctx.
+func (s *HealthService) run(ctx context.Context, c check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	type outcome struct {
+		detail string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		detail, err := c.run(ctx)
+		done <- outcome{detail, err}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+	case <-ctx.Done():
+		o.err = ErrCheckTimeout
+	}
+
+	result := CheckResult{Name: c.name, Status: StatusOK, Detail: o.detail, DurationMs: time.Since(start).Milliseconds()}
+	if o.err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			o.err = ErrCheckTimeout
+		}
+		result.Status, result.Error = StatusFail, o.err.Error()
+	}
+	return result
+}
+
+func checkWorkers(status []WorkerStatus) (string, error) {
+	var running []string
+	for _, w := range status {
+		if !w.Running {
+			if w.Err != nil {
+				return "", fmt.Errorf("%w: %s: %v", ErrWorkerStopped, w.Name, w.Err)
+			}
+			return "", fmt.Errorf("%w: %s", ErrWorkerStopped, w.Name)
+		}
+		running = append(running, w.Name)
+	}
+	return strings.Join(running, ", "), nil
+}
@@ -0,0 +1,115 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepository struct {
+	pingErr    error
+	version    string
+	versionErr error
+	block      bool
+}
+
+func (m *MockRepository) Ping(ctx context.Context) error {
+	if m.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return m.pingErr
+}
+
+func (m *MockRepository) MigrationVersion(context.Context) (string, error) {
+	if m.block {
+		// ignores ctx, the service must still give up
+		time.Sleep(200 * time.Millisecond)
+	}
+	return m.version, m.versionErr
+}
+
+type MockWorkers []WorkerStatus
+
+func (m MockWorkers) Status() []WorkerStatus {
+	return m
+}
+
+func statuses(r Report) map[string]string {
+	got := map[string]string{}
+	for _, c := range r.Checks {
+		got[c.Name] = c.Status
+	}
+	return got
+}
+
+func TestReadiness(t *testing.T) {
+	running := MockWorkers{{Name: "realtime", Running: true}, {Name: "scheduler", Running: true}}
+	testTable := []struct {
+		title      string
+		repo       MockRepository
+		workers    MockWorkers
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			"should be ok", MockRepository{version: "20261020040000"}, running, StatusOK,
+			map[string]string{CheckDatabase: StatusOK, CheckMigration: StatusOK, CheckWorkers: StatusOK},
+		},
+		{
+			"should fail cause database down", MockRepository{pingErr: errors.New("down"), versionErr: errors.New("down")}, running, StatusFail,
+			map[string]string{CheckDatabase: StatusFail, CheckMigration: StatusFail, CheckWorkers: StatusOK},
+		},
+		{
+			"should fail cause worker stopped", MockRepository{version: "20261020040000"}, MockWorkers{{Name: "realtime", Err: errors.New("closed")}}, StatusFail,
+			map[string]string{CheckDatabase: StatusOK, CheckMigration: StatusOK, CheckWorkers: StatusFail},
+		},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewHealthService(&v.repo, v.workers, time.Second)
+			got := s.Readiness(context.Background())
+			assert.Equal(t, v.wantStatus, got.Status)
+			assert.Equal(t, v.wantChecks, statuses(got))
+		})
+	}
+}
+
+func TestReadiness_Details(t *testing.T) {
+	s := NewHealthService(&MockRepository{version: "20261020040000"}, MockWorkers{{Name: "realtime", Running: true}, {Name: "scheduler", Running: true}}, time.Second)
+	got := s.Readiness(context.Background())
+
+	assert.Equal(t, "20261020040000", got.Checks[1].Detail)
+	assert.Equal(t, "realtime, scheduler", got.Checks[2].Detail)
+
+	s = NewHealthService(&MockRepository{}, MockWorkers{{Name: "realtime", Err: errors.New("closed")}}, time.Second)
+	got = s.Readiness(context.Background())
+	assert.Equal(t, "worker stopped: realtime: closed", got.Checks[2].Error)
+}
+
+func TestReadiness_Timeout(t *testing.T) {
+	s := NewHealthService(&MockRepository{block: true}, MockWorkers{}, 20*time.Millisecond)
+
+	start := time.Now()
+	got := s.Readiness(context.Background())
+
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, StatusFail, got.Status)
+	for _, c := range got.Checks[:2] {
+		assert.Equal(t, ErrCheckTimeout.Error(), c.Error)
+	}
+}
+
+func TestDraining(t *testing.T) {
+	s := NewHealthService(&MockRepository{version: "20261020040000"}, MockWorkers{}, time.Second)
+	s.SetDraining()
+
+	assert.Equal(t, Report{Status: StatusOK, Draining: true}, s.Liveness())
+	got := s.Readiness(context.Background())
+	assert.Equal(t, StatusDraining, got.Status)
+	assert.True(t, got.Draining)
+}
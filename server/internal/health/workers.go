@@ -0,0 +1,52 @@
+package health
+
+import (
+	"sort"
+	"sync"
+)
+
+// Workers runs the background workers and tracks which of them are still
+// running, a worker whose run returned is reported stopped.
+type Workers struct {
+	mu     sync.Mutex
+	status map[string]WorkerStatus
+	wg     sync.WaitGroup
+}
+
+func NewWorkers() *Workers {
+	return &Workers{status: map[string]WorkerStatus{}}
+}
+
+func (w *Workers) Go(name string, run func() error) {
+	w.set(WorkerStatus{Name: name, Running: true})
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		err := run()
+		w.set(WorkerStatus{Name: name, Err: err})
+	}()
+}
+
+// Wait blocks until every worker returned.
+func (w *Workers) Wait() {
+	w.wg.Wait()
+}
+
+// Status lists the workers by name.
+func (w *Workers) Status() []WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := make([]WorkerStatus, 0, len(w.status))
+	for _, s := range w.status {
+		status = append(status, s)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Name < status[j].Name })
+	return status
+}
+
+func (w *Workers) set(s WorkerStatus) {
+	w.mu.Lock()
+	w.status[s.Name] = s
+	w.mu.Unlock()
+}
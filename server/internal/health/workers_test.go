@@ -0,0 +1,30 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkers(t *testing.T) {
+	workers := NewWorkers()
+	release := make(chan struct{})
+	failed := errors.New("listen failed")
+
+	workers.Go("scheduler", func() error {
+		<-release
+		return nil
+	})
+	workers.Go("realtime", func() error { return failed })
+
+	assert.Eventually(t, func() bool {
+		return workers.Status()[0] == WorkerStatus{Name: "realtime", Err: failed}
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, WorkerStatus{Name: "scheduler", Running: true}, workers.Status()[1])
+
+	close(release)
+	workers.Wait()
+	assert.Equal(t, []WorkerStatus{{Name: "realtime", Err: failed}, {Name: "scheduler"}}, workers.Status())
+}
@@ -0,0 +1,26 @@
+package linkpreview
+
+import "time"
+
+// Preview is the card shown under a post for the first link in its content.
+type Preview struct {
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	SiteName    string    `json:"site_name,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// oEmbed holds the fields of an oEmbed response a preview can use.
+type oEmbed struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+type job struct {
+	postUUID string
+	url      string
+}
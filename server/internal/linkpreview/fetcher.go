@@ -0,0 +1,293 @@
+package linkpreview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	maxRedirects         = 3
+	maxTitleLength       = 200
+	maxDescriptionLength = 500
+)
+
+var (
+	ErrBlockedAddress = errors.New("address not allowed")
+	ErrInvalidURL     = errors.New("invalid url")
+	ErrNotHTML        = errors.New("not an html page")
+	ErrNoMetadata     = errors.New("no preview metadata")
+	ErrTooLarge       = errors.New("response too large")
+)
+
+// blockedNets are ranges that are not reachable on the public internet but
+// that net.IP has no predicate for.
+var blockedNets = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// IsPublicIP reports whether ip is a public unicast address, anything a
+// server should not be tricked into calling from inside the network is not.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fetcher reads Open Graph and oEmbed metadata. The address check runs when
+// the connection is dialed, after DNS resolution and on every redirect, so a
+// hostname cannot resolve its way into the private network.
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+	allowIP  func(net.IP) bool
+}
+
+func NewFetcher(timeout time.Duration, maxBytes int64) *Fetcher {
+	f := &Fetcher{maxBytes: maxBytes, allowIP: IsPublicIP}
+	dialer := &net.Dialer{Timeout: timeout, Control: f.control}
+	f.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return http.ErrUseLastResponse
+			}
+			return checkScheme(req.URL)
+		},
+	}
+	return f
+}
+
+func (f *Fetcher) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !f.allowIP(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+func checkScheme(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// Fetch builds a preview from the page at rawURL. Open Graph tags win, the
+// page's oEmbed endpoint fills what they leave out and <title> is the last
+// resort.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Preview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Preview{}, ErrInvalidURL
+	}
+	if err := checkScheme(u); err != nil {
+		return Preview{}, err
+	}
+
+	body, resp, err := f.get(ctx, u.String(), "text/html")
+	if err != nil {
+		return Preview{}, err
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Preview{}, ErrNotHTML
+	}
+
+	page := resp.Request.URL
+	meta := parseMeta(body)
+	p := Preview{
+		URL:         rawURL,
+		Title:       firstOf(meta["og:title"], meta["twitter:title"]),
+		Description: firstOf(meta["og:description"], meta["twitter:description"], meta["description"]),
+		ImageURL:    resolve(page, firstOf(meta["og:image"], meta["twitter:image"])),
+		SiteName:    meta["og:site_name"],
+	}
+
+	if p.Title == "" || p.ImageURL == "" {
+		if href := resolve(page, oEmbedLink(body)); href != "" {
+			if o, err := f.oEmbed(ctx, href); err == nil {
+				p.Title = firstOf(p.Title, o.Title)
+				p.ImageURL = firstOf(p.ImageURL, resolve(page, o.ThumbnailURL))
+				p.SiteName = firstOf(p.SiteName, o.ProviderName, o.AuthorName)
+			}
+		}
+	}
+	p.Title = firstOf(p.Title, pageTitle(body))
+	if p.Title == "" {
+		return Preview{}, ErrNoMetadata
+	}
+	p.Title, p.Description = truncate(p.Title, maxTitleLength), truncate(p.Description, maxDescriptionLength)
+	p.FetchedAt = time.Now()
+	return p, nil
+}
+
+func (f *Fetcher) oEmbed(ctx context.Context, href string) (oEmbed, error) {
+	var o oEmbed
+	body, _, err := f.get(ctx, href, "application/json")
+	if err != nil {
+		return o, err
+	}
+	if int64(len(body)) > f.maxBytes {
+		return o, ErrTooLarge
+	}
+	return o, json.Unmarshal(body, &o)
+}
+
+// get reads at most maxBytes+1 of the body so callers can tell a page was
+// cut off. HTML metadata sits in the head, so a cut page is still usable.
+func (f *Fetcher) get(ctx context.Context, rawURL, accept string) ([]byte, *http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, ErrInvalidURL
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "social-clone-linkpreview/1.0")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, nil, ErrBlockedAddress
+		}
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	return body, resp, err
+}
+
+var (
+	metaTagRe  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	linkTagRe  = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	attrRe     = regexp.MustCompile(`(?is)([a-z:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	titleTagRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+func attrs(tag string) map[string]string {
+	a := map[string]string{}
+	for _, m := range attrRe.FindAllStringSubmatch(tag, -1) {
+		a[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3])
+	}
+	return a
+}
+
+// parseMeta maps the property or name of each meta tag to its content, the
+// first occurrence wins.
+func parseMeta(body []byte) map[string]string {
+	meta := map[string]string{}
+	for _, tag := range metaTagRe.FindAllString(string(body), -1) {
+		a := attrs(tag)
+		key := strings.ToLower(firstOf(a["property"], a["name"]))
+		if _, ok := meta[key]; key != "" && !ok {
+			meta[key] = strings.TrimSpace(a["content"])
+		}
+	}
+	return meta
+}
+
+func oEmbedLink(body []byte) string {
+	for _, tag := range linkTagRe.FindAllString(string(body), -1) {
+		a := attrs(tag)
+		if strings.EqualFold(a["type"], "application/json+oembed") {
+			return a["href"]
+		}
+	}
+	return ""
+}
+
+func pageTitle(body []byte) string {
+	m := titleTagRe.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(html.UnescapeString(string(m[1])))
+}
+
+// resolve makes ref absolute against the page and drops anything that is
+// not http or https.
+func resolve(page *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := page.Parse(ref)
+	if err != nil || checkScheme(u) != nil {
+		return ""
+	}
+	return u.String()
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+var urlRe = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// FirstURL returns the first http or https link in content without trailing
+// punctuation, or "" when there is none.
+func FirstURL(content string) string {
+	return strings.TrimRight(urlRe.FindString(content), ".,;:!?)]}")
+}
@@ -0,0 +1,176 @@
+package linkpreview
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestFetcher lets the fetcher reach httptest servers on loopback, which
+// the default address check rightly refuses.
+func newTestFetcher(timeout time.Duration, maxBytes int64) *Fetcher {
+	f := NewFetcher(timeout, maxBytes)
+	f.allowIP = func(net.IP) bool { return true }
+	return f
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testTable := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.ip, func(t *testing.T) {
+			assert.Equal(t, v.want, IsPublicIP(net.ParseIP(v.ip)))
+		})
+	}
+}
+
+func TestFetch_OpenGraph(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head><title>Fallback</title>
+		<meta property="og:title" content="Go &amp; You">
+		<meta content="A tour of Go" property="og:description">
+		<meta property="og:image" content="/cover.png">
+		<meta property="og:site_name" content="Example">
+		</head><body></body></html>`)
+	}))
+	defer srv.Close()
+
+	p, err := newTestFetcher(time.Second, 1<<20).Fetch(context.Background(), srv.URL+"/post")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, srv.URL+"/post", p.URL)
+	assert.Equal(t, "Go & You", p.Title)
+	assert.Equal(t, "A tour of Go", p.Description)
+	assert.Equal(t, srv.URL+"/cover.png", p.ImageURL)
+	assert.Equal(t, "Example", p.SiteName)
+}
+
+func TestFetch_OEmbedFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/video", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<head><link rel="alternate" type="application/json+oembed" href="/oembed?id=1"></head>`)
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"title":"Cat video","provider_name":"Tube","thumbnail_url":"https://img.example/cat.jpg"}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p, err := newTestFetcher(time.Second, 1<<20).Fetch(context.Background(), srv.URL+"/video")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, "Cat video", p.Title)
+	assert.Equal(t, "https://img.example/cat.jpg", p.ImageURL)
+	assert.Equal(t, "Tube", p.SiteName)
+}
+
+func TestFetch_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/bare", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<p>nothing to see</p>`)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "ftp://example.com/", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newTestFetcher(100*time.Millisecond, 1<<20)
+	testTable := []struct {
+		title   string
+		url     string
+		wantErr error
+	}{
+		{"should reject non html", srv.URL + "/json", ErrNotHTML},
+		{"should reject page without metadata", srv.URL + "/bare", ErrNoMetadata},
+		{"should reject unsupported scheme", "file:///etc/passwd", ErrInvalidURL},
+		{"should reject redirect to unsupported scheme", srv.URL + "/redirect", ErrInvalidURL},
+	}
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			_, err := f.Fetch(context.Background(), v.url)
+			assert.ErrorIs(t, err, v.wantErr)
+		})
+	}
+
+	_, err := f.Fetch(context.Background(), srv.URL+"/missing")
+	assert.NotNil(t, err, "should fail on error status")
+	_, err = f.Fetch(context.Background(), srv.URL+"/slow")
+	assert.NotNil(t, err, "should time out")
+}
+
+func TestFetch_SizeLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<title>Head first</title>`+strings.Repeat("x", 4096)+`<meta property="og:title" content="Too late">`)
+	}))
+	defer srv.Close()
+
+	p, err := newTestFetcher(time.Second, 1024).Fetch(context.Background(), srv.URL)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, "Head first", p.Title, "tags past the size limit are never read")
+}
+
+func TestFetch_BlocksPrivateAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer srv.Close()
+
+	_, err := NewFetcher(time.Second, 1<<20).Fetch(context.Background(), srv.URL)
+	assert.ErrorIs(t, err, ErrBlockedAddress)
+}
+
+func TestFirstURL(t *testing.T) {
+	testTable := []struct {
+		content string
+		want    string
+	}{
+		{"look at https://example.com/a?b=1.", "https://example.com/a?b=1"},
+		{"(see http://example.com)", "http://example.com"},
+		{"two https://a.example and https://b.example", "https://a.example"},
+		{"no links, just example.com", ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.content, func(t *testing.T) {
+			assert.Equal(t, v.want, FirstURL(v.content))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package linkpreview
+
+import "database/sql"
+
+type LinkPreviewRepository struct {
+	db *sql.DB
+}
+
+func NewLinkPreviewRepository(db *sql.DB) *LinkPreviewRepository {
+	return &LinkPreviewRepository{db}
+}
+
+// SavePreview replaces the preview of a post, a post that was deleted in the
+// meantime simply gets none.
+func (r *LinkPreviewRepository) SavePreview(postUUID string, p Preview) error {
+	query := `INSERT INTO link_preview (post_id, url, title, description, image_url, site_name, fetched_at)
+    SELECT id, $2, $3, $4, $5, $6, $7 FROM post WHERE uuid = $1
+    ON CONFLICT (post_id) DO UPDATE SET url = EXCLUDED.url, title = EXCLUDED.title,
+      description = EXCLUDED.description, image_url = EXCLUDED.image_url,
+      site_name = EXCLUDED.site_name, fetched_at = EXCLUDED.fetched_at`
+
+	_, err := r.db.Exec(query, postUUID, p.URL, p.Title, p.Description, p.ImageURL, p.SiteName, p.FetchedAt.UTC())
+	return err
+}
+
+func (r *LinkPreviewRepository) DeletePreview(postUUID string) error {
+	_, err := r.db.Exec(`DELETE FROM link_preview
+    WHERE post_id = (SELECT id FROM post WHERE uuid = $1)`, postUUID)
+	return err
+}
@@ -0,0 +1,34 @@
+package linkpreview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavePreview(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	p := Preview{URL: "https://example.com", Title: "Example", FetchedAt: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)}
+	mock.ExpectExec("INSERT INTO link_preview (.+) ON CONFLICT").
+		WithArgs("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", p.URL, p.Title, "", "", "", p.FetchedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := NewLinkPreviewRepository(db).SavePreview("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", p)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePreview(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectExec("DELETE FROM link_preview").
+		WithArgs("f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := NewLinkPreviewRepository(db).DeletePreview("f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
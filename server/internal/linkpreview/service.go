@@ -0,0 +1,90 @@
+package linkpreview
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type ILinkPreviewRepository interface {
+	SavePreview(postUUID string, p Preview) error
+	DeletePreview(postUUID string) error
+}
+
+type IFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (Preview, error)
+}
+
+// LinkPreviewService fetches previews off the request path. Jobs wait in a
+// bounded queue and are dropped when it is full, a post without a preview
+// is still a valid post.
+type LinkPreviewService struct {
+	repo    ILinkPreviewRepository
+	fetcher IFetcher
+	jobs    chan job
+	workers int
+	timeout time.Duration
+}
+
+func NewLinkPreviewService(repo ILinkPreviewRepository, fetcher IFetcher, workers, queueSize int, timeout time.Duration) *LinkPreviewService {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &LinkPreviewService{repo, fetcher, make(chan job, queueSize), workers, timeout}
+}
+
+// Enqueue schedules a preview for the first link in content. Content without
+// a link clears the preview, so an edit that removes the link drops the card.
+func (s *LinkPreviewService) Enqueue(postUUID, content string) {
+	select {
+	case s.jobs <- job{postUUID: postUUID, url: FirstURL(content)}:
+	default:
+		log.Println("link preview queue full, dropped post:", postUUID)
+	}
+}
+
+// Run works the queue until ctx is done.
+func (s *LinkPreviewService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-s.jobs:
+					s.process(ctx, j)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// process stores the preview, or clears a stale one when the link is gone or
+// could not be previewed.
+func (s *LinkPreviewService) process(ctx context.Context, j job) {
+	if j.url == "" {
+		if err := s.repo.DeletePreview(j.postUUID); err != nil {
+			log.Println("failed to delete link preview:", err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	p, err := s.fetcher.Fetch(ctx, j.url)
+	if err != nil {
+		log.Println("failed to fetch link preview:", err)
+		if err := s.repo.DeletePreview(j.postUUID); err != nil {
+			log.Println("failed to delete link preview:", err)
+		}
+		return
+	}
+	if err := s.repo.SavePreview(j.postUUID, p); err != nil {
+		log.Println("failed to save link preview:", err)
+	}
+}
@@ -0,0 +1,99 @@
+package linkpreview
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mRepo struct {
+	mu      sync.Mutex
+	saved   map[string]Preview
+	deleted []string
+}
+
+func (m *mRepo) SavePreview(postUUID string, p Preview) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saved == nil {
+		m.saved = map[string]Preview{}
+	}
+	m.saved[postUUID] = p
+	return nil
+}
+
+func (m *mRepo) DeletePreview(postUUID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, postUUID)
+	return nil
+}
+
+type mFetcher struct {
+	err error
+}
+
+func (m *mFetcher) Fetch(_ context.Context, rawURL string) (Preview, error) {
+	return Preview{URL: rawURL, Title: "title"}, m.err
+}
+
+func TestServiceProcess(t *testing.T) {
+	testTable := []struct {
+		title       string
+		job         job
+		fetchErr    error
+		wantSaved   bool
+		wantDeleted bool
+	}{
+		{"should save preview", job{"f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "https://example.com"}, nil, true, false},
+		{"should clear preview without link", job{"f307d2db-d2ea-4ec9-8d31-27b7443d7c72", ""}, nil, false, true},
+		{"should clear preview on fetch error", job{"f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "https://example.com"}, ErrBlockedAddress, false, true},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := mRepo{}
+			s := NewLinkPreviewService(&repo, &mFetcher{v.fetchErr}, 1, 1, time.Second)
+			s.process(context.Background(), v.job)
+			_, saved := repo.saved[v.job.postUUID]
+			assert.Equal(t, v.wantSaved, saved)
+			assert.Equal(t, v.wantDeleted, len(repo.deleted) == 1)
+		})
+	}
+}
+
+func TestServiceEnqueue_DropsWhenFull(t *testing.T) {
+	s := NewLinkPreviewService(&mRepo{}, &mFetcher{}, 1, 1, time.Second)
+	s.Enqueue("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "https://a.example")
+	s.Enqueue("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "https://b.example")
+	assert.Len(t, s.jobs, 1)
+	assert.Equal(t, job{"f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "https://a.example"}, <-s.jobs)
+}
+
+func TestServiceRun(t *testing.T) {
+	repo := mRepo{}
+	s := NewLinkPreviewService(&repo, &mFetcher{}, 2, 4, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	s.Enqueue("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "see https://example.com")
+	assert.Eventually(t, func() bool {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		return repo.saved["f307d2db-d2ea-4ec9-8d31-27b7443d7c72"].URL == "https://example.com"
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("workers did not stop")
+	}
+}
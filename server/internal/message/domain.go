@@ -0,0 +1,109 @@
+package message
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	MaxMembers          = 10
+	MaxTitleLength      = 100
+	MaxMessageLength    = 2000
+	DefaultMessageLimit = 50
+	MaxMessageLimit     = 100
+)
+
+type ConversationCreated struct {
+	UUID        string   `json:"-"`
+	CreatorUUID string   `json:"-"`
+	MemberUUIDs []string `json:"member_uuids"`
+	Title       string   `json:"title"`
+	IsGroup     bool     `json:"-"`
+}
+
+// Conversation is the minimal view the service needs to authorize and fan
+// out a message.
+type Conversation struct {
+	UUID        string
+	IsGroup     bool
+	MemberUUIDs []string
+}
+
+func (c Conversation) HasMember(userUUID string) bool {
+	for _, m := range c.MemberUUIDs {
+		if m == userUUID {
+			return true
+		}
+	}
+	return false
+}
+
+// Member carries the read receipt of one participant.
+type Member struct {
+	UUID                string     `json:"uuid"`
+	Username            string     `json:"username"`
+	LastReadMessageUUID *string    `json:"last_read_message_uuid"`
+	LastReadAt          *time.Time `json:"last_read_at"`
+}
+
+type Members []Member
+
+func (m *Members) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok || len(b) == 0 {
+		*m = Members{}
+		return nil
+	}
+	return json.Unmarshal(b, m)
+}
+
+type ConversationResponse struct {
+	UUID        string           `json:"uuid"`
+	IsGroup     bool             `json:"is_group"`
+	Title       string           `json:"title"`
+	Members     Members          `json:"members"`
+	LastMessage *MessageResponse `json:"last_message"`
+	UnreadCount int64            `json:"unread_count"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+type CreatedResponse struct {
+	UUID string `json:"uuid"`
+}
+
+type MessageCreated struct {
+	UUID             string `json:"-"`
+	ConversationUUID string `json:"-"`
+	SenderUUID       string `json:"-"`
+	Content          string `json:"content"`
+}
+
+type MessageResponse struct {
+	UUID             string    `json:"uuid"`
+	ConversationUUID string    `json:"conversation_uuid"`
+	SenderUUID       string    `json:"sender_uuid"`
+	SenderUsername   string    `json:"sender_username"`
+	Content          string    `json:"content"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// MessageFilter pages by message cursor. Before returns older messages newest
+// first, After returns newer messages oldest first so a polling client can
+// append them in order.
+type MessageFilter struct {
+	ConversationUUID string
+	ViewerUUID       string
+	Before           string
+	After            string
+	Limit            int
+}
+
+type ReadReceipt struct {
+	ConversationUUID string `json:"conversation_uuid"`
+	UserUUID         string `json:"user_uuid"`
+	MessageUUID      string `json:"message_uuid"`
+}
+
+type MessageRead struct {
+	MessageUUID string `json:"message_uuid"`
+}
@@ -0,0 +1,207 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IMessageService interface {
+	CreateConversation(ConversationCreated) (string, bool, error)
+	GetConversations(userUUID string, limit, offset int) ([]ConversationResponse, error)
+	SendMessage(MessageCreated) (string, error)
+	GetMessages(MessageFilter) ([]MessageResponse, error)
+	MarkRead(conversationUUID, userUUID, messageUUID string) error
+}
+
+type MessageHandler struct {
+	messageService IMessageService
+}
+
+func NewMessageHandler(messageService IMessageService) *MessageHandler {
+	return &MessageHandler{messageService}
+}
+
+func sendServiceErr(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInvalidMembers, ErrTooManyMembers, ErrInvalidTitle, ErrEmptyMessage, ErrMessageTooLong,
+		ErrConflictCursor, ErrInvalidPageSize:
+		util.SendJson(w, util.BuildErrResponse("invalid request")(err), http.StatusBadRequest)
+	case ErrUserNotFound, ErrConversationNotFound, ErrMessageNotFound:
+		util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+	case ErrBlocked:
+		util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+	default:
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+	}
+}
+
+func (h *MessageHandler) CreateConversation(w http.ResponseWriter, r *http.Request) {
+	var c ConversationCreated
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+	c.CreatorUUID = userUUID
+
+	for _, m := range c.MemberUUIDs {
+		if !util.IsValidUUID(m) {
+			util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	uuid, created, err := h.messageService.CreateConversation(c)
+	if err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	util.SendJson(w, CreatedResponse{uuid}, status)
+}
+
+func (h *MessageHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	conversations, err := h.messageService.GetConversations(userUUID, page.Limit, page.Offset)
+	if err != nil {
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, conversations, http.StatusOK)
+}
+
+func (h *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	conversationUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(conversationUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	var m MessageCreated
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+	m.ConversationUUID, m.SenderUUID = conversationUUID, userUUID
+
+	uuid, err := h.messageService.SendMessage(m)
+	if err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, CreatedResponse{uuid}, http.StatusCreated)
+}
+
+func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	conversationUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(conversationUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	before, after := q.Get("before"), q.Get("after")
+	if (before != "" && !util.IsValidUUID(before)) || (after != "" && !util.IsValidUUID(after)) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	messages, err := h.messageService.GetMessages(MessageFilter{
+		ConversationUUID: conversationUUID,
+		ViewerUUID:       userUUID,
+		Before:           before,
+		After:            after,
+		Limit:            page.Limit,
+	})
+	if err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, messages, http.StatusOK)
+}
+
+func (h *MessageHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	conversationUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(conversationUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	// the body is optional, without it the whole conversation is read
+	var mr MessageRead
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&mr); err != nil {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+	}
+	if mr.MessageUUID != "" && !util.IsValidUUID(mr.MessageUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.messageService.MarkRead(conversationUUID, userUUID, mr.MessageUUID); err != nil {
+		sendServiceErr(w, err)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("marked conversation as read successful!"), http.StatusOK)
+}
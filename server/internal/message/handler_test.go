@@ -0,0 +1,183 @@
+package message
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr     error
+	created   bool
+	gotFilter MessageFilter
+	gotRead   string
+}
+
+func (m *mService) CreateConversation(ConversationCreated) (string, bool, error) {
+	return convUUID, m.created, m.isErr
+}
+
+func (m *mService) GetConversations(string, int, int) ([]ConversationResponse, error) {
+	return []ConversationResponse{}, m.isErr
+}
+
+func (m *mService) SendMessage(MessageCreated) (string, error) {
+	return "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", m.isErr
+}
+
+func (m *mService) GetMessages(f MessageFilter) ([]MessageResponse, error) {
+	m.gotFilter = f
+	return []MessageResponse{}, m.isErr
+}
+
+func (m *mService) MarkRead(_, _, messageUUID string) error {
+	m.gotRead = messageUUID
+	return m.isErr
+}
+
+func withUser(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), "userUUID", ongUUID))
+}
+
+func TestHandlerCreateConversation(t *testing.T) {
+	body, _ := json.Marshal(ConversationCreated{MemberUUIDs: []string{bobUUID}})
+	testTable := []struct {
+		title      string
+		body       []byte
+		created    bool
+		serviceErr error
+		wantStatus int
+	}{
+		{"should create conversation", body, true, nil, http.StatusCreated},
+		{"should return existing conversation", body, false, nil, http.StatusOK},
+		{"should bad request cause invalid body", []byte("abc"), false, nil, http.StatusBadRequest},
+		{"should bad request cause invalid member uuid", []byte(`{"member_uuids":["abc"]}`), false, nil, http.StatusBadRequest},
+		{"should not found", body, false, ErrUserNotFound, http.StatusNotFound},
+		{"should forbidden", body, false, ErrBlocked, http.StatusForbidden},
+		{"should service error", body, false, errors.New("db err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewMessageHandler(&mService{isErr: v.serviceErr, created: v.created})
+			req := withUser(httptest.NewRequest(http.MethodPost, "/conversations", bytes.NewReader(v.body)))
+			rec := httptest.NewRecorder()
+			h.CreateConversation(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetConversations(t *testing.T) {
+	testTable := []struct {
+		title      string
+		url        string
+		wantStatus int
+	}{
+		{"should return conversations", "/conversations", http.StatusOK},
+		{"should bad request cause invalid page", "/conversations?offset=-1", http.StatusBadRequest},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewMessageHandler(&mService{})
+			rec := httptest.NewRecorder()
+			h.GetConversations(rec, withUser(httptest.NewRequest(http.MethodGet, v.url, nil)))
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerSendMessage(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should send message", convUUID, `{"content":"hi"}`, nil, http.StatusCreated},
+		{"should bad request cause invalid uuid", "abc", `{"content":"hi"}`, nil, http.StatusBadRequest},
+		{"should bad request cause empty message", convUUID, `{"content":""}`, ErrEmptyMessage, http.StatusBadRequest},
+		{"should not found", convUUID, `{"content":"hi"}`, ErrConversationNotFound, http.StatusNotFound},
+		{"should forbidden", convUUID, `{"content":"hi"}`, ErrBlocked, http.StatusForbidden},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewMessageHandler(&mService{isErr: v.serviceErr})
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(v.body)))
+			req = withUser(mux.SetURLVars(req, map[string]string{"uuid": v.uuid}))
+			rec := httptest.NewRecorder()
+			h.SendMessage(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetMessages(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		wantStatus int
+		wantAfter  string
+	}{
+		{"should return messages", "?limit=10", http.StatusOK, ""},
+		{"should poll newer messages", "?after=d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", http.StatusOK, "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"},
+		{"should bad request cause invalid cursor", "?before=abc", http.StatusBadRequest, ""},
+		{"should bad request cause invalid limit", "?limit=abc", http.StatusBadRequest, ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			ms := mService{}
+			h := NewMessageHandler(&ms)
+			req := httptest.NewRequest(http.MethodGet, "/"+v.query, nil)
+			req = withUser(mux.SetURLVars(req, map[string]string{"uuid": convUUID}))
+			rec := httptest.NewRecorder()
+			h.GetMessages(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equal(t, v.wantAfter, ms.gotFilter.After)
+		})
+	}
+}
+
+func TestHandlerMarkRead(t *testing.T) {
+	testTable := []struct {
+		title      string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantRead   string
+	}{
+		{"should mark whole conversation", "", nil, http.StatusOK, ""},
+		{"should mark up to message", `{"message_uuid":"d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"}`, nil, http.StatusOK, "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"},
+		{"should bad request cause invalid message uuid", `{"message_uuid":"abc"}`, nil, http.StatusBadRequest, ""},
+		{"should not found", "", ErrMessageNotFound, http.StatusNotFound, ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			ms := mService{isErr: v.serviceErr}
+			h := NewMessageHandler(&ms)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(v.body)))
+			req = withUser(mux.SetURLVars(req, map[string]string{"uuid": convUUID}))
+			rec := httptest.NewRecorder()
+			h.MarkRead(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equal(t, v.wantRead, ms.gotRead)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package message
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrMessageNotFound      = errors.New("message not found")
+)
+
+type MessageRepository struct {
+	db *sql.DB
+}
+
+func NewMessageRepository(db *sql.DB) *MessageRepository {
+	return &MessageRepository{db}
+}
+
+// FindDirectConversation returns the one-to-one conversation between the two
+// users, or an empty uuid when there is none.
+func (r *MessageRepository) FindDirectConversation(userUUID, otherUUID string) (string, error) {
+	query := `
+  SELECT c.uuid FROM conversation AS c
+  WHERE c.is_group = false
+  AND EXISTS (SELECT 1 FROM conversation_member AS cm JOIN app_user AS u ON u.id = cm.user_id
+    WHERE cm.conversation_id = c.id AND u.uuid = $1)
+  AND EXISTS (SELECT 1 FROM conversation_member AS cm JOIN app_user AS u ON u.id = cm.user_id
+    WHERE cm.conversation_id = c.id AND u.uuid = $2)
+  LIMIT 1`
+
+	var uuid string
+	err := r.db.QueryRow(query, userUUID, otherUUID).Scan(&uuid)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return uuid, err
+}
+
+func (r *MessageRepository) CreateConversation(c ConversationCreated) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(`INSERT INTO conversation (uuid, is_group, title, created_by)
+    VALUES ($1, $2, NULLIF($3, ''), (SELECT id FROM app_user WHERE uuid = $4)) RETURNING id`,
+		c.UUID, c.IsGroup, c.Title, c.CreatorUUID).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	members := append([]string{c.CreatorUUID}, c.MemberUUIDs...)
+	_, err = tx.Exec(`INSERT INTO conversation_member (conversation_id, user_id)
+    SELECT $1, id FROM app_user WHERE uuid = ANY($2::uuid[])`, id, pq.Array(members))
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *MessageRepository) GetConversation(conversationUUID string) (Conversation, error) {
+	query := `
+  SELECT c.uuid, c.is_group, array_agg(u.uuid ORDER BY cm.joined_at)
+  FROM conversation AS c
+  JOIN conversation_member AS cm ON cm.conversation_id = c.id
+  JOIN app_user AS u ON u.id = cm.user_id
+  WHERE c.uuid = $1
+  GROUP BY c.id`
+
+	var c Conversation
+	var members pq.StringArray
+	err := r.db.QueryRow(query, conversationUUID).Scan(&c.UUID, &c.IsGroup, &members)
+	if err == sql.ErrNoRows {
+		return c, ErrConversationNotFound
+	}
+	c.MemberUUIDs = members
+	return c, err
+}
+
+// GetConversations lists the user's conversations with the latest message
+// first. Unread counts ignore the user's own messages.
+func (r *MessageRepository) GetConversations(userUUID string, limit, offset int) ([]ConversationResponse, error) {
+	query := `
+  SELECT c.uuid, c.is_group, c.title, c.updated_at,
+    (SELECT json_agg(json_build_object(
+        'uuid', u.uuid, 'username', u.username,
+        'last_read_message_uuid', rm.uuid, 'last_read_at', m.last_read_at
+      ) ORDER BY m.joined_at)
+      FROM conversation_member AS m
+      JOIN app_user AS u ON u.id = m.user_id
+      LEFT JOIN message AS rm ON rm.id = m.last_read_message_id
+      WHERE m.conversation_id = c.id),
+    lm.uuid, lm.sender_uuid, lm.sender_username, lm.content, lm.created_at,
+    (SELECT count(*) FROM message AS um
+      WHERE um.conversation_id = c.id AND um.sender_id <> cm.user_id
+      AND um.id > COALESCE(cm.last_read_message_id, 0))
+  FROM conversation_member AS cm
+  JOIN conversation AS c ON c.id = cm.conversation_id
+  LEFT JOIN LATERAL (
+    SELECT msg.uuid, su.uuid AS sender_uuid, su.username AS sender_username, msg.content, msg.created_at
+    FROM message AS msg
+    JOIN app_user AS su ON su.id = msg.sender_id
+    WHERE msg.conversation_id = c.id
+    ORDER BY msg.id DESC
+    LIMIT 1
+  ) AS lm ON true
+  WHERE cm.user_id = (SELECT id FROM app_user WHERE uuid = $1)
+  ORDER BY c.updated_at DESC, c.id DESC
+  LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, userUUID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []ConversationResponse{}
+	for rows.Next() {
+		var c ConversationResponse
+		var title sql.NullString
+		var lastUUID, senderUUID, senderName, content sql.NullString
+		var createdAt sql.NullTime
+		err := rows.Scan(&c.UUID, &c.IsGroup, &title, &c.UpdatedAt, &c.Members,
+			&lastUUID, &senderUUID, &senderName, &content, &createdAt, &c.UnreadCount)
+		if err != nil {
+			return conversations, err
+		}
+		c.Title = title.String
+		if lastUUID.Valid {
+			c.LastMessage = &MessageResponse{lastUUID.String, c.UUID, senderUUID.String, senderName.String, content.String, createdAt.Time}
+		}
+		conversations = append(conversations, c)
+	}
+	return conversations, rows.Err()
+}
+
+func (r *MessageRepository) CreateMessage(m MessageCreated) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(`INSERT INTO message (uuid, conversation_id, sender_id, content)
+    VALUES ($1, (SELECT id FROM conversation WHERE uuid = $2), (SELECT id FROM app_user WHERE uuid = $3), $4)
+    RETURNING id`, m.UUID, m.ConversationUUID, m.SenderUUID, m.Content).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`UPDATE conversation SET updated_at = current_timestamp WHERE uuid = $1`, m.ConversationUUID)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// blockFilter hides, in group conversations, messages from senders the viewer
+// ($2) has blocked or been blocked by.
+const blockFilter = `
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks AS b
+    WHERE (b.blocker_id = m.sender_id AND b.blocked_id = (SELECT id FROM app_user WHERE uuid = $2))
+    OR (b.blocked_id = m.sender_id AND b.blocker_id = (SELECT id FROM app_user WHERE uuid = $2))
+  )`
+
+func (r *MessageRepository) GetMessages(f MessageFilter) ([]MessageResponse, error) {
+	cursor, order := `AND ($3::uuid IS NULL OR m.id < (SELECT id FROM message WHERE uuid = $3))`, "DESC"
+	var cursorUUID any
+	if f.After != "" {
+		cursor, order = `AND m.id > (SELECT id FROM message WHERE uuid = $3)`, "ASC"
+		cursorUUID = f.After
+	} else if f.Before != "" {
+		cursorUUID = f.Before
+	}
+
+	query := `
+  SELECT m.uuid, c.uuid, u.uuid, u.username, m.content, m.created_at
+  FROM message AS m
+  JOIN conversation AS c ON c.id = m.conversation_id
+  JOIN app_user AS u ON u.id = m.sender_id
+  WHERE c.uuid = $1 ` + cursor + blockFilter + `
+  ORDER BY m.id ` + order + `
+  LIMIT $4`
+
+	rows, err := r.db.Query(query, f.ConversationUUID, f.ViewerUUID, cursorUUID, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []MessageResponse{}
+	for rows.Next() {
+		var m MessageResponse
+		err := rows.Scan(&m.UUID, &m.ConversationUUID, &m.SenderUUID, &m.SenderUsername, &m.Content, &m.CreatedAt)
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// MarkRead moves the user's read pointer to messageUUID, or to the latest
+// message when it is empty. The pointer never moves backwards, so it returns
+// the newly read message or an empty uuid when nothing changed.
+func (r *MessageRepository) MarkRead(conversationUUID, userUUID, messageUUID string) (string, error) {
+	var id int64
+	var uuid string
+	var err error
+	if messageUUID == "" {
+		err = r.db.QueryRow(`SELECT m.id, m.uuid FROM message AS m
+      WHERE m.conversation_id = (SELECT id FROM conversation WHERE uuid = $1)
+      ORDER BY m.id DESC LIMIT 1`, conversationUUID).Scan(&id, &uuid)
+	} else {
+		err = r.db.QueryRow(`SELECT m.id, m.uuid FROM message AS m
+      WHERE m.uuid = $2 AND m.conversation_id = (SELECT id FROM conversation WHERE uuid = $1)`,
+			conversationUUID, messageUUID).Scan(&id, &uuid)
+	}
+	if err == sql.ErrNoRows {
+		return "", ErrMessageNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	result, err := r.db.Exec(`UPDATE conversation_member SET last_read_message_id = $3, last_read_at = current_timestamp
+    WHERE conversation_id = (SELECT id FROM conversation WHERE uuid = $1)
+    AND user_id = (SELECT id FROM app_user WHERE uuid = $2)
+    AND (last_read_message_id IS NULL OR last_read_message_id < $3)`, conversationUUID, userUUID, id)
+	if err != nil {
+		return "", err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return "", err
+	}
+	return uuid, nil
+}
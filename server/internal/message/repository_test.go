@@ -0,0 +1,197 @@
+package message
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDirectConversation(t *testing.T) {
+	testTable := []struct {
+		title    string
+		queryErr error
+		want     string
+	}{
+		{"should return existing conversation", nil, "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"},
+		{"should return empty when none", sql.ErrNoRows, ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			q := mock.ExpectQuery("SELECT c.uuid FROM conversation").
+				WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+			if v.queryErr != nil {
+				q.WillReturnError(v.queryErr)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"uuid"}).AddRow(v.want))
+			}
+
+			repo := NewMessageRepository(db)
+			got, err := repo.FindDirectConversation("e936e164-52fa-4fd5-b0e0-597c2f270245", "f6630558-b800-48ff-9a09-5863d6055154")
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, got)
+		})
+	}
+}
+
+func TestCreateConversation(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	c := ConversationCreated{
+		UUID:        "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11",
+		CreatorUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		MemberUUIDs: []string{"f6630558-b800-48ff-9a09-5863d6055154"},
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO conversation").
+		WithArgs(c.UUID, false, "", c.CreatorUUID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("INSERT INTO conversation_member").
+		WithArgs(1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewMessageRepository(db)
+	id, err := repo.CreateConversation(c)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, int64(1), id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetConversation(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("SELECT (.+) FROM conversation AS c").
+		WithArgs("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11").
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "is_group", "members"}).
+			AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", false, "{e936e164-52fa-4fd5-b0e0-597c2f270245,f6630558-b800-48ff-9a09-5863d6055154}"))
+
+	repo := NewMessageRepository(db)
+	c, err := repo.GetConversation("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, c.HasMember("f6630558-b800-48ff-9a09-5863d6055154"))
+	assert.False(t, c.HasMember("ea151663-aad6-45b2-808b-e3f160956612"))
+}
+
+func TestGetConversations(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	now := time.Now()
+	members := `[{"uuid":"e936e164-52fa-4fd5-b0e0-597c2f270245","username":"ong","last_read_message_uuid":null,"last_read_at":null}]`
+	mock.ExpectQuery("SELECT (.+) FROM conversation_member AS cm").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "is_group", "title", "updated_at", "members",
+			"last_uuid", "sender_uuid", "sender_username", "content", "created_at", "unread"}).
+			AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", false, nil, now, []byte(members),
+				"d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "e936e164-52fa-4fd5-b0e0-597c2f270245", "ong", "hi", now, 2).
+			AddRow("5f2d7a55-0a3c-4e0e-8f0f-4b6c0f3e2a10", true, "team", now, []byte(members),
+				nil, nil, nil, nil, nil, 0))
+
+	repo := NewMessageRepository(db)
+	got, err := repo.GetConversations("e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "hi", got[0].LastMessage.Content)
+	assert.Equal(t, int64(2), got[0].UnreadCount)
+	assert.Len(t, got[0].Members, 1)
+	assert.Nil(t, got[1].LastMessage)
+	assert.Equal(t, "team", got[1].Title)
+}
+
+func TestCreateMessage(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	m := MessageCreated{
+		UUID:             "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21",
+		ConversationUUID: "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11",
+		SenderUUID:       "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		Content:          "hi",
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO message").
+		WithArgs(m.UUID, m.ConversationUUID, m.SenderUUID, m.Content).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("UPDATE conversation SET updated_at").
+		WithArgs(m.ConversationUUID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewMessageRepository(db)
+	id, err := repo.CreateMessage(m)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, int64(7), id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessages(t *testing.T) {
+	testTable := []struct {
+		title      string
+		filter     MessageFilter
+		wantCursor any
+		wantOrder  string
+	}{
+		{"should return latest", MessageFilter{Limit: 20}, nil, "DESC"},
+		{"should return older", MessageFilter{Before: "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", Limit: 20}, "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "DESC"},
+		{"should return newer", MessageFilter{After: "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", Limit: 20}, "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "ASC"},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			v.filter.ConversationUUID = "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"
+			v.filter.ViewerUUID = "e936e164-52fa-4fd5-b0e0-597c2f270245"
+			mock.ExpectQuery("SELECT (.+) FROM message AS m (.+) ORDER BY m.id "+v.wantOrder).
+				WithArgs(v.filter.ConversationUUID, v.filter.ViewerUUID, v.wantCursor, 20).
+				WillReturnRows(sqlmock.NewRows([]string{"uuid", "conversation_uuid", "sender_uuid", "sender_username", "content", "created_at"}).
+					AddRow("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", v.filter.ConversationUUID, v.filter.ViewerUUID, "ong", "hi", time.Now()))
+
+			repo := NewMessageRepository(db)
+			got, err := repo.GetMessages(v.filter)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Len(t, got, 1)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	testTable := []struct {
+		title    string
+		lookup   error
+		affected int64
+		want     string
+		wantErr  error
+	}{
+		{"should move read pointer", nil, 1, "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", nil},
+		{"should not move read pointer backwards", nil, 0, "", nil},
+		{"should message not found", sql.ErrNoRows, 0, "", ErrMessageNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			q := mock.ExpectQuery("SELECT m.id, m.uuid FROM message").
+				WithArgs("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21")
+			if v.lookup != nil {
+				q.WillReturnError(v.lookup)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"id", "uuid"}).AddRow(7, "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"))
+				mock.ExpectExec("UPDATE conversation_member").
+					WithArgs("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "e936e164-52fa-4fd5-b0e0-597c2f270245", 7).
+					WillReturnResult(sqlmock.NewResult(0, v.affected))
+			}
+
+			repo := NewMessageRepository(db)
+			got, err := repo.MarkRead("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "e936e164-52fa-4fd5-b0e0-597c2f270245", "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, got)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package message
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IMessageHandler interface {
+	CreateConversation(http.ResponseWriter, *http.Request)
+	GetConversations(http.ResponseWriter, *http.Request)
+	SendMessage(http.ResponseWriter, *http.Request)
+	GetMessages(http.ResponseWriter, *http.Request)
+	MarkRead(http.ResponseWriter, *http.Request)
+}
+
+func RegisterMessageRouter(router *mux.Router, messageHandler IMessageHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/conversations").Subrouter()
+	srouter.Use(authMiddleware)
+
+	srouter.HandleFunc("", messageHandler.GetConversations).Methods(http.MethodGet)
+	srouter.HandleFunc("", messageHandler.CreateConversation).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}/messages", messageHandler.GetMessages).Methods(http.MethodGet)
+	srouter.HandleFunc("/{uuid}/messages", messageHandler.SendMessage).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}/read", messageHandler.MarkRead).Methods(http.MethodPost)
+}
@@ -0,0 +1,57 @@
+package message
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) CreateConversation(http.ResponseWriter, *http.Request) {
+	m.called["CreateConversation"] = true
+}
+
+func (m *MockHandler) GetConversations(http.ResponseWriter, *http.Request) {
+	m.called["GetConversations"] = true
+}
+
+func (m *MockHandler) SendMessage(http.ResponseWriter, *http.Request) {
+	m.called["SendMessage"] = true
+}
+
+func (m *MockHandler) GetMessages(http.ResponseWriter, *http.Request) {
+	m.called["GetMessages"] = true
+}
+
+func (m *MockHandler) MarkRead(http.ResponseWriter, *http.Request) {
+	m.called["MarkRead"] = true
+}
+
+func TestRoute(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{called: map[string]bool{}}
+	RegisterMessageRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	testTable := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodPost, "/conversations", "CreateConversation"},
+		{http.MethodGet, "/conversations", "GetConversations"},
+		{http.MethodPost, "/conversations/" + convUUID + "/messages", "SendMessage"},
+		{http.MethodGet, "/conversations/" + convUUID + "/messages", "GetMessages"},
+		{http.MethodPost, "/conversations/" + convUUID + "/read", "MarkRead"},
+	}
+
+	for _, v := range testTable {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.want], "%s not called", v.want)
+	}
+}
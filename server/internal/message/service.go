@@ -0,0 +1,224 @@
+package message
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMembers  = errors.New("invalid conversation members")
+	ErrTooManyMembers  = errors.New("too many conversation members")
+	ErrInvalidTitle    = errors.New("invalid conversation title")
+	ErrEmptyMessage    = errors.New("empty message")
+	ErrMessageTooLong  = errors.New("message too long")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrBlocked         = errors.New("user is blocked")
+	ErrConflictCursor  = errors.New("before and after cannot be combined")
+	ErrInvalidPageSize = errors.New("invalid page size")
+)
+
+type IMessageRepository interface {
+	FindDirectConversation(userUUID, otherUUID string) (string, error)
+	CreateConversation(ConversationCreated) (int64, error)
+	GetConversation(conversationUUID string) (Conversation, error)
+	GetConversations(userUUID string, limit, offset int) ([]ConversationResponse, error)
+	CreateMessage(MessageCreated) (int64, error)
+	GetMessages(MessageFilter) ([]MessageResponse, error)
+	MarkRead(conversationUUID, userUUID, messageUUID string) (string, error)
+}
+
+type IUserServiceForMessage interface {
+	GetUserByUUID(string) (user.User, error)
+}
+
+type IBlockServiceForMessage interface {
+	IsBlocked(userUUID, otherUUID string) (bool, error)
+}
+
+type IPublisherForMessage interface {
+	Publish(realtime.Event) error
+}
+
+type MessageService struct {
+	messageRepo  IMessageRepository
+	userService  IUserServiceForMessage
+	blockService IBlockServiceForMessage
+	publisher    IPublisherForMessage
+}
+
+func NewMessageService(messageRepo IMessageRepository, userService IUserServiceForMessage,
+	blockService IBlockServiceForMessage, publisher IPublisherForMessage,
+) *MessageService {
+	return &MessageService{messageRepo, userService, blockService, publisher}
+}
+
+// CreateConversation returns the conversation uuid and whether it was newly
+// created. Starting a one-to-one conversation that already exists returns
+// the existing one.
+func (s *MessageService) CreateConversation(c ConversationCreated) (string, bool, error) {
+	members := []string{}
+	seen := map[string]bool{c.CreatorUUID: true}
+	for _, m := range c.MemberUUIDs {
+		if !seen[m] {
+			seen[m] = true
+			members = append(members, m)
+		}
+	}
+	if len(members) == 0 {
+		return "", false, ErrInvalidMembers
+	}
+	if len(members)+1 > MaxMembers {
+		return "", false, ErrTooManyMembers
+	}
+	c.Title = strings.TrimSpace(c.Title)
+	if utf8.RuneCountInString(c.Title) > MaxTitleLength {
+		return "", false, ErrInvalidTitle
+	}
+	c.MemberUUIDs = members
+	c.IsGroup = len(members) > 1 || c.Title != ""
+
+	for _, m := range members {
+		if _, err := s.userService.GetUserByUUID(m); err != nil {
+			if err == user.ErrUserNotFound {
+				return "", false, ErrUserNotFound
+			}
+			return "", false, err
+		}
+		blocked, err := s.blockService.IsBlocked(c.CreatorUUID, m)
+		if err != nil {
+			return "", false, err
+		}
+		if blocked {
+			return "", false, ErrBlocked
+		}
+	}
+
+	if !c.IsGroup {
+		existing, err := s.messageRepo.FindDirectConversation(c.CreatorUUID, members[0])
+		if err != nil {
+			return "", false, err
+		}
+		if existing != "" {
+			return existing, false, nil
+		}
+	}
+
+	c.UUID = uuid.NewString()
+	if _, err := s.messageRepo.CreateConversation(c); err != nil {
+		return "", false, err
+	}
+	return c.UUID, true, nil
+}
+
+func (s *MessageService) GetConversations(userUUID string, limit, offset int) ([]ConversationResponse, error) {
+	return s.messageRepo.GetConversations(userUUID, limit, offset)
+}
+
+// getConversation answers non-members as if the conversation does not exist.
+func (s *MessageService) getConversation(conversationUUID, userUUID string) (Conversation, error) {
+	c, err := s.messageRepo.GetConversation(conversationUUID)
+	if err != nil {
+		return c, err
+	}
+	if !c.HasMember(userUUID) {
+		return c, ErrConversationNotFound
+	}
+	return c, nil
+}
+
+func (s *MessageService) SendMessage(m MessageCreated) (string, error) {
+	m.Content = strings.TrimSpace(m.Content)
+	if m.Content == "" {
+		return "", ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(m.Content) > MaxMessageLength {
+		return "", ErrMessageTooLong
+	}
+
+	c, err := s.getConversation(m.ConversationUUID, m.SenderUUID)
+	if err != nil {
+		return "", err
+	}
+
+	// a block closes a one-to-one conversation, in groups the blocked
+	// sender's messages are filtered out when reading instead
+	if !c.IsGroup {
+		for _, other := range c.MemberUUIDs {
+			if other == m.SenderUUID {
+				continue
+			}
+			blocked, err := s.blockService.IsBlocked(m.SenderUUID, other)
+			if err != nil {
+				return "", err
+			}
+			if blocked {
+				return "", ErrBlocked
+			}
+		}
+	}
+
+	m.UUID = uuid.NewString()
+	if _, err := s.messageRepo.CreateMessage(m); err != nil {
+		return "", err
+	}
+	s.publish(c, realtime.TypeMessage, map[string]string{
+		"conversation_uuid": c.UUID,
+		"message_uuid":      m.UUID,
+		"sender_uuid":       m.SenderUUID,
+	})
+	return m.UUID, nil
+}
+
+func (s *MessageService) GetMessages(f MessageFilter) ([]MessageResponse, error) {
+	if f.Before != "" && f.After != "" {
+		return nil, ErrConflictCursor
+	}
+	if f.Limit < 0 {
+		return nil, ErrInvalidPageSize
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultMessageLimit
+	}
+	f.Limit = min(f.Limit, MaxMessageLimit)
+
+	if _, err := s.getConversation(f.ConversationUUID, f.ViewerUUID); err != nil {
+		return nil, err
+	}
+	return s.messageRepo.GetMessages(f)
+}
+
+// MarkRead records a read receipt and pushes it to the other members.
+func (s *MessageService) MarkRead(conversationUUID, userUUID, messageUUID string) error {
+	c, err := s.getConversation(conversationUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	readUUID, err := s.messageRepo.MarkRead(conversationUUID, userUUID, messageUUID)
+	if err != nil || readUUID == "" {
+		return err
+	}
+	s.publish(c, realtime.TypeMessageRead, ReadReceipt{conversationUUID, userUUID, readUUID})
+	return nil
+}
+
+// publish pushes to every member, including the actor's other sessions. The
+// message is already stored and clients fall back to polling, so failures
+// are only logged.
+func (s *MessageService) publish(c Conversation, typ string, data any) {
+	for _, member := range c.MemberUUIDs {
+		e, err := realtime.NewEvent(typ, member, data)
+		if err == nil {
+			err = s.publisher.Publish(e)
+		}
+		if err != nil {
+			log.Println("failed to publish message event:", err)
+		}
+	}
+}
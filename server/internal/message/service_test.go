@@ -0,0 +1,220 @@
+package message
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ongUUID  = "e936e164-52fa-4fd5-b0e0-597c2f270245"
+	bobUUID  = "f6630558-b800-48ff-9a09-5863d6055154"
+	annUUID  = "ea151663-aad6-45b2-808b-e3f160956612"
+	convUUID = "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"
+)
+
+type MockRepo struct {
+	existing     string
+	conversation Conversation
+	repoErr      error
+	created      *ConversationCreated
+	sent         *MessageCreated
+	gotFilter    MessageFilter
+	readUUID     string
+}
+
+func (m *MockRepo) FindDirectConversation(string, string) (string, error) {
+	return m.existing, m.repoErr
+}
+
+func (m *MockRepo) CreateConversation(c ConversationCreated) (int64, error) {
+	m.created = &c
+	return 1, m.repoErr
+}
+
+func (m *MockRepo) GetConversation(string) (Conversation, error) {
+	if m.conversation.UUID == "" {
+		return Conversation{}, ErrConversationNotFound
+	}
+	return m.conversation, nil
+}
+
+func (m *MockRepo) GetConversations(string, int, int) ([]ConversationResponse, error) {
+	return []ConversationResponse{}, m.repoErr
+}
+
+func (m *MockRepo) CreateMessage(msg MessageCreated) (int64, error) {
+	m.sent = &msg
+	return 1, m.repoErr
+}
+
+func (m *MockRepo) GetMessages(f MessageFilter) ([]MessageResponse, error) {
+	m.gotFilter = f
+	return []MessageResponse{}, m.repoErr
+}
+
+func (m *MockRepo) MarkRead(string, string, string) (string, error) {
+	return m.readUUID, m.repoErr
+}
+
+type MockUserSrv struct{}
+
+func (m *MockUserSrv) GetUserByUUID(s string) (user.User, error) {
+	if s == ongUUID || s == bobUUID || s == annUUID {
+		return user.User{UUID: s}, nil
+	}
+	return user.User{}, user.ErrUserNotFound
+}
+
+type MockBlockSrv struct {
+	blocked map[string]bool
+}
+
+func (m *MockBlockSrv) IsBlocked(_, other string) (bool, error) {
+	return m.blocked[other], nil
+}
+
+type MockPublisher struct {
+	events []realtime.Event
+}
+
+func (m *MockPublisher) Publish(e realtime.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func TestServiceCreateConversation(t *testing.T) {
+	testTable := []struct {
+		title       string
+		input       ConversationCreated
+		existing    string
+		blocked     map[string]bool
+		wantCreated bool
+		wantGroup   bool
+		wantErr     error
+	}{
+		{"should create direct conversation", ConversationCreated{MemberUUIDs: []string{bobUUID, bobUUID}}, "", nil, true, false, nil},
+		{"should reuse direct conversation", ConversationCreated{MemberUUIDs: []string{bobUUID}}, convUUID, nil, false, false, nil},
+		{"should create group conversation", ConversationCreated{MemberUUIDs: []string{bobUUID, annUUID}}, "", nil, true, true, nil},
+		{"should reject only yourself", ConversationCreated{MemberUUIDs: []string{ongUUID}}, "", nil, false, false, ErrInvalidMembers},
+		{"should reject unknown member", ConversationCreated{MemberUUIDs: []string{"d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"}}, "", nil, false, false, ErrUserNotFound},
+		{"should reject blocked member", ConversationCreated{MemberUUIDs: []string{bobUUID, annUUID}}, "", map[string]bool{annUUID: true}, false, false, ErrBlocked},
+		{"should reject long title", ConversationCreated{MemberUUIDs: []string{bobUUID}, Title: strings.Repeat("a", MaxTitleLength+1)}, "", nil, false, false, ErrInvalidTitle},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := &MockRepo{existing: v.existing}
+			s := NewMessageService(repo, &MockUserSrv{}, &MockBlockSrv{v.blocked}, &MockPublisher{})
+			v.input.CreatorUUID = ongUUID
+			uuid, created, err := s.CreateConversation(v.input)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantCreated, created)
+			if v.wantErr != nil {
+				return
+			}
+			assert.NotEmpty(t, uuid)
+			if created {
+				assert.Equal(t, v.wantGroup, repo.created.IsGroup)
+				assert.NotContains(t, repo.created.MemberUUIDs, ongUUID)
+			}
+		})
+	}
+}
+
+func TestServiceCreateConversation_TooManyMembers(t *testing.T) {
+	members := make([]string, MaxMembers)
+	for i := range members {
+		members[i] = fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+	}
+	s := NewMessageService(&MockRepo{}, &MockUserSrv{}, &MockBlockSrv{}, &MockPublisher{})
+	_, _, err := s.CreateConversation(ConversationCreated{CreatorUUID: ongUUID, MemberUUIDs: members})
+	assert.Equal(t, ErrTooManyMembers, err)
+}
+
+func TestServiceSendMessage(t *testing.T) {
+	direct := Conversation{convUUID, false, []string{ongUUID, bobUUID}}
+	group := Conversation{convUUID, true, []string{ongUUID, bobUUID, annUUID}}
+	testTable := []struct {
+		title        string
+		conversation Conversation
+		sender       string
+		content      string
+		blocked      map[string]bool
+		wantEvents   int
+		wantErr      error
+	}{
+		{"should send direct message", direct, ongUUID, " hi ", nil, 2, nil},
+		{"should send group message despite block", group, ongUUID, "hi", map[string]bool{annUUID: true}, 3, nil},
+		{"should reject blocked direct message", direct, ongUUID, "hi", map[string]bool{bobUUID: true}, 0, ErrBlocked},
+		{"should hide conversation from non member", direct, annUUID, "hi", nil, 0, ErrConversationNotFound},
+		{"should reject empty message", direct, ongUUID, "   ", nil, 0, ErrEmptyMessage},
+		{"should reject long message", direct, ongUUID, strings.Repeat("a", MaxMessageLength+1), nil, 0, ErrMessageTooLong},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := &MockRepo{conversation: v.conversation}
+			publisher := &MockPublisher{}
+			s := NewMessageService(repo, &MockUserSrv{}, &MockBlockSrv{v.blocked}, publisher)
+			_, err := s.SendMessage(MessageCreated{ConversationUUID: convUUID, SenderUUID: v.sender, Content: v.content})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Lenf(t, publisher.events, v.wantEvents, "want one push per member")
+			if v.wantErr == nil {
+				assert.Equal(t, strings.TrimSpace(v.content), repo.sent.Content)
+				assert.Equal(t, realtime.TypeMessage, publisher.events[0].Type)
+			}
+		})
+	}
+}
+
+func TestServiceGetMessages(t *testing.T) {
+	testTable := []struct {
+		title     string
+		filter    MessageFilter
+		wantLimit int
+		wantErr   error
+	}{
+		{"should default limit", MessageFilter{ViewerUUID: ongUUID}, DefaultMessageLimit, nil},
+		{"should cap limit", MessageFilter{ViewerUUID: ongUUID, Limit: 1000}, MaxMessageLimit, nil},
+		{"should reject both cursors", MessageFilter{ViewerUUID: ongUUID, Before: convUUID, After: convUUID}, 0, ErrConflictCursor},
+		{"should hide conversation from non member", MessageFilter{ViewerUUID: annUUID}, 0, ErrConversationNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := &MockRepo{conversation: Conversation{convUUID, false, []string{ongUUID, bobUUID}}}
+			s := NewMessageService(repo, &MockUserSrv{}, &MockBlockSrv{}, &MockPublisher{})
+			v.filter.ConversationUUID = convUUID
+			_, err := s.GetMessages(v.filter)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantLimit, repo.gotFilter.Limit)
+		})
+	}
+}
+
+func TestServiceMarkRead(t *testing.T) {
+	testTable := []struct {
+		title      string
+		readUUID   string
+		wantEvents int
+	}{
+		{"should push read receipt", "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", 2},
+		{"should not push when already read", "", 0},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := &MockRepo{conversation: Conversation{convUUID, false, []string{ongUUID, bobUUID}}, readUUID: v.readUUID}
+			publisher := &MockPublisher{}
+			s := NewMessageService(repo, &MockUserSrv{}, &MockBlockSrv{}, publisher)
+			err := s.MarkRead(convUUID, ongUUID, "")
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Len(t, publisher.events, v.wantEvents)
+		})
+	}
+}
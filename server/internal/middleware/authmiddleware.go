@@ -3,38 +3,47 @@ package middleware
 import (
 	"context"
 	"net/http"
-	"strings"
 
 	"github.com/dsypasit/social-clone/server/internal/auth"
 	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/dsypasit/social-clone/server/internal/user"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/mux"
 )
 
-func AuthMiddleware(jwtService auth.JwtServiceInterface) mux.MiddlewareFunc {
+type IUserServiceForAuth interface {
+	GetUserByUUID(string) (user.User, error)
+}
+
+func AuthMiddleware(jwtService auth.JwtServiceInterface, userService IUserServiceForAuth) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
-		return Middleware(jwtService, h)
+		return Middleware(jwtService, userService, h)
 	}
 }
 
-func Middleware(jwtService auth.JwtServiceInterface, next http.Handler) http.Handler {
+func Middleware(jwtService auth.JwtServiceInterface, userService IUserServiceForAuth, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authValue := r.Header.Get("Authorization")
-		if authValue == "" {
+		token, fromCookie, err := auth.TokenFromRequest(r)
+		if err != nil {
+			message := "Missing authorization token"
+			if err == auth.ErrInvalidAuthFormat {
+				message = "Invalid authorization format"
+			}
 			util.SendJson(w, map[string]string{
-				"message": "Missing authorization token",
+				"message": message,
 			}, http.StatusUnauthorized)
 			return
 		}
 
-		parts := strings.SplitN(authValue, "Bearer ", 2)
-		if len(parts) != 2 {
-			util.SendJson(w, map[string]string{
-				"message": "Invalid authorization format",
-			}, http.StatusUnauthorized)
-			return
+		// the browser attaches the cookie on its own, a header token cannot be forged cross site
+		if fromCookie {
+			if err := auth.CheckCSRF(r); err != nil {
+				util.SendJson(w, map[string]string{
+					"message": "invalid csrf token",
+				}, http.StatusForbidden)
+				return
+			}
 		}
-		token := parts[1]
 
 		claim, err := jwtService.VerifyToken(token)
 		if err != nil {
@@ -51,8 +60,46 @@ func Middleware(jwtService auth.JwtServiceInterface, next http.Handler) http.Han
 			return
 		}
 
-		// insert uuid value to context
+		usr, err := userService.GetUserByUUID(claim.UserUUID)
+		if err != nil {
+			if err == user.ErrUserNotFound {
+				util.SendJson(w, map[string]string{
+					"message": "Invalid authorization token",
+				}, http.StatusUnauthorized)
+				return
+			}
+
+			util.SendJson(w, map[string]string{
+				"message": "failed to get user",
+			}, http.StatusInternalServerError)
+			return
+		}
+
+		// suspended accounts keep a valid token until it expires, so check on every request
+		if usr.IsSuspended() {
+			util.SendJson(w, map[string]string{
+				"message": "account suspended",
+			}, http.StatusForbidden)
+			return
+		}
+
+		// insert uuid and role value to context
 		ctx := context.WithValue(r.Context(), "userUUID", claim.UserUUID)
+		ctx = context.WithValue(ctx, "userRole", usr.Role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func RequireRole(role string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if userRole, _ := r.Context().Value("userRole").(string); userRole != role {
+				util.SendJson(w, map[string]string{
+					"message": "permission denied",
+				}, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/dsypasit/social-clone/server/internal/auth"
+	"github.com/dsypasit/social-clone/server/internal/user"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,7 +21,7 @@ func TestAuthMiddleware_MissingAuthorization(t *testing.T) {
 
 	// Create a middleware instance with a mock JwtService
 	mockService := auth.NewJwtService("secret")
-	middleware := AuthMiddleware(mockService)
+	middleware := AuthMiddleware(mockService, &MockUserService{})
 
 	// Create a request without authorization header
 	req, err := http.NewRequest(http.MethodGet, "/", nil)
@@ -46,7 +48,7 @@ func TestAuthMiddleware_InvalidFormat(t *testing.T) {
 
 	// Create a middleware instance with a mock JwtService
 	mockService := auth.NewJwtService("secret")
-	middleware := AuthMiddleware(mockService)
+	middleware := AuthMiddleware(mockService, &MockUserService{})
 
 	// Create a request without authorization header
 	req, err := http.NewRequest(http.MethodGet, "/", nil)
@@ -81,6 +83,15 @@ func (m *MockJwtService) VerifyToken(token string) (*auth.AuthJWTClaim, error) {
 	return &m.claimToken, m.err
 }
 
+type MockUserService struct {
+	u   user.User
+	err error
+}
+
+func (m *MockUserService) GetUserByUUID(string) (user.User, error) {
+	return m.u, m.err
+}
+
 func TestAuthMiddleware_ValidClaims(t *testing.T) {
 	claimToken := auth.AuthJWTClaim{UserUUID: "94d67127-78e8-419f-adb8-782d26e4805d", RegisteredClaims: jwt.RegisteredClaims{}}
 	// Create a middleware instance with a mock JwtService
@@ -88,7 +99,7 @@ func TestAuthMiddleware_ValidClaims(t *testing.T) {
 		claimToken: claimToken,
 	}
 
-	middleware := AuthMiddleware(&mockService)
+	middleware := AuthMiddleware(&mockService, &MockUserService{user.User{Role: user.RoleUser}, nil})
 
 	// Create a request without authorization header
 	req, err := http.NewRequest(http.MethodGet, "/", nil)
@@ -103,8 +114,10 @@ func TestAuthMiddleware_ValidClaims(t *testing.T) {
 
 		ctx := r.Context()
 		actual, _ := ctx.Value("userUUID").(string)
+		actualRole, _ := ctx.Value("userRole").(string)
 
 		assert.Equal(t, expected, actual, fmt.Sprintf("Expected user uuid: %v, got: %v", expected, actual))
+		assert.Equal(t, user.RoleUser, actualRole, fmt.Sprintf("Expected user role: %v, got: %v", user.RoleUser, actualRole))
 
 		w.WriteHeader(http.StatusOK)
 	})
@@ -121,7 +134,7 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 
 	mService := MockJwtService{claimToken: claimToken, err: jwt.ErrTokenExpired}
 
-	middleware := AuthMiddleware(&mService)
+	middleware := AuthMiddleware(&mService, &MockUserService{})
 	req, err := http.NewRequest(http.MethodGet, "/", nil)
 	assert.Nilf(t, err, "Unexpected error : %v", err)
 	req.Header.Add("Authorization", "Bearer valid token")
@@ -140,3 +153,125 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	assert.Equalf(t, http.StatusUnauthorized, rec.Code, "expected unauthorized status but got %v", rec.Code)
 	assert.Equalf(t, expected, response, "want %v but got %v", expected, response)
 }
+
+func TestAuthMiddleware_UserStatus(t *testing.T) {
+	suspendedAt := time.Now()
+	testTable := []struct {
+		title      string
+		user       user.User
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{
+			"should forbidden cause account suspended", user.User{Role: user.RoleUser, SuspendedAt: &suspendedAt}, nil,
+			http.StatusForbidden, map[string]string{"message": "account suspended"},
+		},
+		{
+			"should unauthorized cause user not found", user.User{}, user.ErrUserNotFound,
+			http.StatusUnauthorized, map[string]string{"message": "Invalid authorization token"},
+		},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			claimToken := auth.AuthJWTClaim{UserUUID: "94d67127-78e8-419f-adb8-782d26e4805d"}
+			mService := MockJwtService{claimToken: claimToken}
+			middleware := AuthMiddleware(&mService, &MockUserService{v.user, v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Add("Authorization", "Bearer valid token")
+			rec := httptest.NewRecorder()
+
+			middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rec, req)
+
+			var response map[string]string
+			json.NewDecoder(rec.Body).Decode(&response)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantBody, response, "want %v but got %v", v.wantBody, response)
+		})
+	}
+}
+
+func TestAuthMiddleware_SessionCookie(t *testing.T) {
+	testTable := []struct {
+		title      string
+		method     string
+		csrfCookie string
+		csrfHeader string
+		wantStatus int
+	}{
+		{"should accept cookie on safe method", http.MethodGet, "", "", http.StatusOK},
+		{"should accept cookie with matching csrf token", http.MethodPost, "csrf", "csrf", http.StatusOK},
+		{"should forbidden cause csrf token missing", http.MethodPost, "csrf", "", http.StatusForbidden},
+		{"should forbidden cause csrf token mismatch", http.MethodDelete, "csrf", "other", http.StatusForbidden},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			claimToken := auth.AuthJWTClaim{UserUUID: "94d67127-78e8-419f-adb8-782d26e4805d"}
+			mService := MockJwtService{claimToken: claimToken}
+			middleware := AuthMiddleware(&mService, &MockUserService{user.User{Role: user.RoleUser}, nil})
+
+			req, _ := http.NewRequest(v.method, "/", nil)
+			req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: "valid token"})
+			if v.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: auth.CSRFCookie, Value: v.csrfCookie})
+			}
+			if v.csrfHeader != "" {
+				req.Header.Set(auth.CSRFHeader, v.csrfHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, claimToken.UserUUID, r.Context().Value("userUUID"))
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_HeaderSkipsCSRF(t *testing.T) {
+	mService := MockJwtService{claimToken: auth.AuthJWTClaim{UserUUID: "94d67127-78e8-419f-adb8-782d26e4805d"}}
+	middleware := AuthMiddleware(&mService, &MockUserService{user.User{Role: user.RoleUser}, nil})
+
+	req, _ := http.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Add("Authorization", "Bearer valid token")
+	rec := httptest.NewRecorder()
+	middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, req)
+
+	assert.Equalf(t, http.StatusOK, rec.Code, "want %v but got %v", http.StatusOK, rec.Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	testTable := []struct {
+		title      string
+		role       string
+		wantStatus int
+	}{
+		{"should pass admin", user.RoleAdmin, http.StatusOK},
+		{"should forbidden user", user.RoleUser, http.StatusForbidden},
+		{"should forbidden without role", "", http.StatusForbidden},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "userRole", v.role))
+			rec := httptest.NewRecorder()
+
+			RequireRole(user.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
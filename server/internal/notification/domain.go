@@ -0,0 +1,63 @@
+package notification
+
+import "time"
+
+const (
+	TypeFollow  = "follow"
+	TypeLike    = "like"
+	TypeComment = "comment"
+	TypeMention = "mention"
+)
+
+const (
+	TargetUser    = "user"
+	TargetPost    = "post"
+	TargetComment = "comment"
+)
+
+// MaxGroupActors is how many recent actors a group lists by name.
+const MaxGroupActors = 3
+
+type Event struct {
+	Type          string `json:"type"`
+	ActorUUID     string `json:"actor_uuid"`
+	RecipientUUID string `json:"-"`
+	TargetType    string `json:"target_type"`
+	TargetUUID    string `json:"target_uuid"`
+}
+
+type Actor struct {
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+}
+
+// NotificationGroup folds every notification of the same type about the same
+// target into one entry. UUID is the newest notification in the group and is
+// what the mark-as-read endpoint expects.
+type NotificationGroup struct {
+	UUID       string    `json:"uuid"`
+	Type       string    `json:"type"`
+	TargetType string    `json:"target_type"`
+	TargetUUID string    `json:"target_uuid"`
+	Actors     []Actor   `json:"actors"`
+	ActorCount int       `json:"actor_count"`
+	Unread     bool      `json:"unread"`
+	Message    string    `json:"message"`
+	LatestAt   time.Time `json:"latest_at"`
+}
+
+type NotificationList struct {
+	Notifications []NotificationGroup `json:"notifications"`
+	UnreadCount   int64               `json:"unread_count"`
+}
+
+type NotificationFilter struct {
+	RecipientUUID string
+	UnreadOnly    bool
+	Limit         int
+	Offset        int
+}
+
+type UnreadCount struct {
+	UnreadCount int64 `json:"unread_count"`
+}
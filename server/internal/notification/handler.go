@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type INotificationService interface {
+	GetNotifications(NotificationFilter) (NotificationList, error)
+	CountUnread(recipientUUID string) (int64, error)
+	MarkRead(recipientUUID, notificationUUID string) error
+	MarkAllRead(recipientUUID string) (int64, error)
+}
+
+type NotificationHandler struct {
+	notificationService INotificationService
+}
+
+func NewNotificationHandler(notificationService INotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService}
+}
+
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	list, err := h.notificationService.GetNotifications(NotificationFilter{
+		RecipientUUID: userUUID,
+		UnreadOnly:    r.URL.Query().Get("unread") == "true",
+		Limit:         page.Limit,
+		Offset:        page.Offset,
+	})
+	if err != nil {
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, list, http.StatusOK)
+}
+
+func (h *NotificationHandler) CountUnread(w http.ResponseWriter, r *http.Request) {
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	count, err := h.notificationService.CountUnread(userUUID)
+	if err != nil {
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, UnreadCount{count}, http.StatusOK)
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	notificationUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(notificationUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userUUID, notificationUUID); err != nil {
+		if err == ErrNotificationNotFound {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("marked notification as read successful!"), http.StatusOK)
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if _, err := h.notificationService.MarkAllRead(userUUID); err != nil {
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("marked all notifications as read successful!"), http.StatusOK)
+}
@@ -0,0 +1,119 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr     error
+	gotFilter NotificationFilter
+}
+
+func (m *mService) GetNotifications(f NotificationFilter) (NotificationList, error) {
+	m.gotFilter = f
+	return NotificationList{[]NotificationGroup{}, 1}, m.isErr
+}
+
+func (m *mService) CountUnread(string) (int64, error) {
+	return 4, m.isErr
+}
+
+func (m *mService) MarkRead(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) MarkAllRead(string) (int64, error) {
+	return 0, m.isErr
+}
+
+func TestHandlerGetNotifications(t *testing.T) {
+	testTable := []struct {
+		title      string
+		url        string
+		userUUID   string
+		serviceErr error
+		wantStatus int
+		wantUnread bool
+	}{
+		{"should return notifications", "/?unread=true&limit=10", "e936e164-52fa-4fd5-b0e0-597c2f270245", nil, http.StatusOK, true},
+		{"should bad request cause invalid page", "/?limit=abc", "e936e164-52fa-4fd5-b0e0-597c2f270245", nil, http.StatusBadRequest, false},
+		{"should unauthorized", "/", "", nil, http.StatusUnauthorized, false},
+		{"should service failed", "/", "e936e164-52fa-4fd5-b0e0-597c2f270245", errors.New("db err"), http.StatusInternalServerError, false},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			ms := mService{isErr: v.serviceErr}
+			h := NewNotificationHandler(&ms)
+
+			req, _ := http.NewRequest(http.MethodGet, v.url, nil)
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", v.userUUID))
+			rec := httptest.NewRecorder()
+			h.GetNotifications(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equal(t, v.wantUnread, ms.gotFilter.UnreadOnly)
+		})
+	}
+}
+
+func TestHandlerCountUnread(t *testing.T) {
+	h := NewNotificationHandler(&mService{})
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+	rec := httptest.NewRecorder()
+	h.CountUnread(rec, req)
+
+	var res UnreadCount
+	json.NewDecoder(rec.Body).Decode(&res)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(4), res.UnreadCount)
+}
+
+func TestHandlerMarkRead(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should mark as read", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest},
+		{"should not found", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", ErrNotificationNotFound, http.StatusNotFound},
+		{"should service error", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", errors.New("db err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewNotificationHandler(&mService{isErr: v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.MarkRead(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerMarkAllRead(t *testing.T) {
+	h := NewNotificationHandler(&mService{})
+
+	req, _ := http.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+	rec := httptest.NewRecorder()
+	h.MarkAllRead(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
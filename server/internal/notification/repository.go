@@ -0,0 +1,139 @@
+package notification
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db}
+}
+
+// CreateNotification silently drops the event when either side has blocked the
+// other, the recipient has muted the actor, or the same unread event already
+// exists. It reports whether a row was stored.
+func (r *NotificationRepository) CreateNotification(uuid string, e Event) (bool, error) {
+	query := `INSERT INTO notification (uuid, recipient_id, actor_id, type, target_type, target_uuid)
+  SELECT $1, rcp.id, act.id, $4, $5, $6
+  FROM app_user AS rcp, app_user AS act
+  WHERE rcp.uuid = $2 AND act.uuid = $3
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks AS b
+    WHERE (b.blocker_id = rcp.id AND b.blocked_id = act.id)
+    OR (b.blocker_id = act.id AND b.blocked_id = rcp.id)
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM mutes AS m WHERE m.muter_id = rcp.id AND m.muted_id = act.id
+  )
+  ON CONFLICT (recipient_id, actor_id, type, target_uuid) WHERE read_at IS NULL DO NOTHING`
+
+	result, err := r.db.Exec(query, uuid, e.RecipientUUID, e.ActorUUID, e.Type, e.TargetType, e.TargetUUID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// GetNotifications groups by type and target, keeping read and unread
+// notifications in separate groups so new activity on an old post shows up
+// as a fresh entry.
+func (r *NotificationRepository) GetNotifications(f NotificationFilter) ([]NotificationGroup, error) {
+	query := `
+  SELECT (array_agg(n.uuid ORDER BY n.id DESC))[1], n.type, n.target_type, n.target_uuid,
+    (array_agg(a.uuid ORDER BY n.id DESC))[1:10], (array_agg(a.username ORDER BY n.id DESC))[1:10],
+    count(DISTINCT n.actor_id), n.read_at IS NULL AS unread, max(n.created_at)
+  FROM notification AS n
+  JOIN app_user AS a ON a.id = n.actor_id
+  WHERE n.recipient_id = (SELECT id FROM app_user WHERE uuid = $1)
+  AND ($2 = false OR n.read_at IS NULL)
+  GROUP BY n.type, n.target_type, n.target_uuid, n.read_at IS NULL
+  ORDER BY max(n.id) DESC
+  LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, f.RecipientUUID, f.UnreadOnly, f.Limit, f.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []NotificationGroup{}
+	for rows.Next() {
+		var g NotificationGroup
+		var actorUUIDs, actorNames pq.StringArray
+		err := rows.Scan(&g.UUID, &g.Type, &g.TargetType, &g.TargetUUID,
+			&actorUUIDs, &actorNames, &g.ActorCount, &g.Unread, &g.LatestAt)
+		if err != nil {
+			return groups, err
+		}
+		g.Actors = recentActors(actorUUIDs, actorNames)
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// recentActors dedups the newest-first actor lists down to MaxGroupActors.
+func recentActors(uuids, names []string) []Actor {
+	actors := []Actor{}
+	seen := map[string]bool{}
+	for i := range uuids {
+		if seen[uuids[i]] || i >= len(names) {
+			continue
+		}
+		seen[uuids[i]] = true
+		actors = append(actors, Actor{uuids[i], names[i]})
+		if len(actors) == MaxGroupActors {
+			break
+		}
+	}
+	return actors
+}
+
+func (r *NotificationRepository) CountUnread(recipientUUID string) (int64, error) {
+	query := `
+  SELECT count(DISTINCT (type, target_type, target_uuid))
+  FROM notification
+  WHERE recipient_id = (SELECT id FROM app_user WHERE uuid = $1) AND read_at IS NULL`
+
+	var count int64
+	err := r.db.QueryRow(query, recipientUUID).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks the whole unread group that notificationUUID belongs to.
+// Marking an already read group is not an error.
+func (r *NotificationRepository) MarkRead(recipientUUID, notificationUUID string) error {
+	var typ, targetType, targetUUID string
+	err := r.db.QueryRow(`SELECT type, target_type, target_uuid FROM notification
+    WHERE uuid = $1 AND recipient_id = (SELECT id FROM app_user WHERE uuid = $2)`,
+		notificationUUID, recipientUUID).Scan(&typ, &targetType, &targetUUID)
+	if err == sql.ErrNoRows {
+		return ErrNotificationNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`UPDATE notification SET read_at = current_timestamp
+    WHERE recipient_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND type = $2 AND target_type = $3 AND target_uuid = $4 AND read_at IS NULL`,
+		recipientUUID, typ, targetType, targetUUID)
+	return err
+}
+
+func (r *NotificationRepository) MarkAllRead(recipientUUID string) (int64, error) {
+	result, err := r.db.Exec(`UPDATE notification SET read_at = current_timestamp
+    WHERE recipient_id = (SELECT id FROM app_user WHERE uuid = $1) AND read_at IS NULL`, recipientUUID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
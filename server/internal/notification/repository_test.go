@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNotification(t *testing.T) {
+	e := Event{
+		Type:          TypeMention,
+		ActorUUID:     "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		RecipientUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+		TargetType:    TargetPost,
+		TargetUUID:    "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectExec("INSERT INTO notification").
+		WithArgs("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", e.RecipientUUID, e.ActorUUID, e.Type, e.TargetType, e.TargetUUID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := NewNotificationRepository(db)
+	created, err := repo.CreateNotification("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", e)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, created)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNotifications(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"uuid", "type", "target_type", "target_uuid", "actor_uuids", "actor_names", "actor_count", "unread", "latest_at"}).
+		AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", TypeLike, TargetPost, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+			"{a,b,a,c,d}", "{ann,bob,ann,cat,dan}", 4, true, now)
+	mock.ExpectQuery("SELECT (.+) FROM notification").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", true, 20, 0).
+		WillReturnRows(rows)
+
+	repo := NewNotificationRepository(db)
+	groups, err := repo.GetNotifications(NotificationFilter{"e936e164-52fa-4fd5-b0e0-597c2f270245", true, 20, 0})
+
+	wantActors := []Actor{{"a", "ann"}, {"b", "bob"}, {"c", "cat"}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, wantActors, groups[0].Actors)
+	assert.Equal(t, 4, groups[0].ActorCount)
+	assert.True(t, groups[0].Unread)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkRead(t *testing.T) {
+	testTable := []struct {
+		title    string
+		lookup   error
+		wantErr  error
+		wantExec bool
+	}{
+		{"should mark group as read", nil, nil, true},
+		{"should not found", sql.ErrNoRows, ErrNotificationNotFound, false},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			q := mock.ExpectQuery("SELECT type, target_type, target_uuid FROM notification").
+				WithArgs("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			if v.lookup != nil {
+				q.WillReturnError(v.lookup)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"type", "target_type", "target_uuid"}).
+					AddRow(TypeLike, TargetPost, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72"))
+			}
+			if v.wantExec {
+				mock.ExpectExec("UPDATE notification SET read_at").
+					WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", TypeLike, TargetPost, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			}
+
+			repo := NewNotificationRepository(db)
+			err := repo.MarkRead("e936e164-52fa-4fd5-b0e0-597c2f270245", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkAllRead(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectExec("UPDATE notification SET read_at").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewNotificationRepository(db)
+	n, err := repo.MarkAllRead("e936e164-52fa-4fd5-b0e0-597c2f270245")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, int64(2), n)
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type INotificationHandler interface {
+	GetNotifications(http.ResponseWriter, *http.Request)
+	CountUnread(http.ResponseWriter, *http.Request)
+	MarkRead(http.ResponseWriter, *http.Request)
+	MarkAllRead(http.ResponseWriter, *http.Request)
+}
+
+func RegisterNotificationRouter(router *mux.Router, notificationHandler INotificationHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/notifications").Subrouter()
+	srouter.Use(authMiddleware)
+
+	srouter.HandleFunc("", notificationHandler.GetNotifications).Methods(http.MethodGet)
+	srouter.HandleFunc("/unread-count", notificationHandler.CountUnread).Methods(http.MethodGet)
+	srouter.HandleFunc("/read-all", notificationHandler.MarkAllRead).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}/read", notificationHandler.MarkRead).Methods(http.MethodPost)
+}
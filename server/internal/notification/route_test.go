@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	getNotificationsCalled bool
+	countUnreadCalled      bool
+	markReadCalled         bool
+	markAllReadCalled      bool
+}
+
+func (m *MockHandler) GetNotifications(http.ResponseWriter, *http.Request) {
+	m.getNotificationsCalled = true
+}
+
+func (m *MockHandler) CountUnread(http.ResponseWriter, *http.Request) {
+	m.countUnreadCalled = true
+}
+
+func (m *MockHandler) MarkRead(http.ResponseWriter, *http.Request) {
+	m.markReadCalled = true
+}
+
+func (m *MockHandler) MarkAllRead(http.ResponseWriter, *http.Request) {
+	m.markAllReadCalled = true
+}
+
+func TestRoute(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{}
+	RegisterNotificationRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/notifications", nil))
+	assert.True(t, mHandler.getNotificationsCalled, "get notifications not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/notifications/unread-count", nil))
+	assert.True(t, mHandler.countUnreadCalled, "count unread not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/notifications/0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11/read", nil))
+	assert.True(t, mHandler.markReadCalled, "mark read not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/notifications/read-all", nil))
+	assert.True(t, mHandler.markAllReadCalled, "mark all read not called")
+}
@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidEvent = errors.New("invalid notification event")
+
+type INotificationRepository interface {
+	CreateNotification(uuid string, e Event) (bool, error)
+	GetNotifications(NotificationFilter) ([]NotificationGroup, error)
+	CountUnread(recipientUUID string) (int64, error)
+	MarkRead(recipientUUID, notificationUUID string) error
+	MarkAllRead(recipientUUID string) (int64, error)
+}
+
+type IPublisherForNotification interface {
+	Publish(realtime.Event) error
+}
+
+type NotificationService struct {
+	notificationRepo INotificationRepository
+	publisher        IPublisherForNotification
+}
+
+func NewNotificationService(notificationRepo INotificationRepository, publisher IPublisherForNotification) *NotificationService {
+	return &NotificationService{notificationRepo, publisher}
+}
+
+// Notify records e for its recipient. Acting on your own content never
+// notifies you.
+func (s *NotificationService) Notify(e Event) error {
+	switch e.Type {
+	case TypeFollow, TypeLike, TypeComment, TypeMention:
+	default:
+		return ErrInvalidEvent
+	}
+	if e.RecipientUUID == "" || e.ActorUUID == "" || e.TargetUUID == "" {
+		return ErrInvalidEvent
+	}
+	if e.RecipientUUID == e.ActorUUID {
+		return nil
+	}
+	created, err := s.notificationRepo.CreateNotification(uuid.NewString(), e)
+	if err != nil || !created {
+		return err
+	}
+
+	// the notification is stored and will show up on the next fetch, so a
+	// failed push is only logged
+	re, err := realtime.NewEvent(realtime.TypeNotification, e.RecipientUUID, e)
+	if err == nil {
+		err = s.publisher.Publish(re)
+	}
+	if err != nil {
+		log.Println("failed to publish notification:", err)
+	}
+	return nil
+}
+
+func (s *NotificationService) GetNotifications(f NotificationFilter) (NotificationList, error) {
+	groups, err := s.notificationRepo.GetNotifications(f)
+	if err != nil {
+		return NotificationList{}, err
+	}
+	count, err := s.notificationRepo.CountUnread(f.RecipientUUID)
+	if err != nil {
+		return NotificationList{}, err
+	}
+
+	for i := range groups {
+		groups[i].Message = buildMessage(groups[i])
+	}
+	return NotificationList{groups, count}, nil
+}
+
+func (s *NotificationService) CountUnread(recipientUUID string) (int64, error) {
+	return s.notificationRepo.CountUnread(recipientUUID)
+}
+
+func (s *NotificationService) MarkRead(recipientUUID, notificationUUID string) error {
+	return s.notificationRepo.MarkRead(recipientUUID, notificationUUID)
+}
+
+func (s *NotificationService) MarkAllRead(recipientUUID string) (int64, error) {
+	return s.notificationRepo.MarkAllRead(recipientUUID)
+}
+
+// buildMessage renders e.g. "ong and 4 others liked your post".
+func buildMessage(g NotificationGroup) string {
+	if len(g.Actors) == 0 {
+		return ""
+	}
+
+	subject := g.Actors[0].Username
+	switch others := g.ActorCount - 1; {
+	case others == 1:
+		subject += " and 1 other"
+	case others > 1:
+		subject += fmt.Sprintf(" and %d others", others)
+	}
+
+	target := strings.ReplaceAll(g.TargetType, "_", " ")
+	switch g.Type {
+	case TypeFollow:
+		return subject + " followed you"
+	case TypeLike:
+		return fmt.Sprintf("%s liked your %s", subject, target)
+	case TypeComment:
+		return fmt.Sprintf("%s commented on your %s", subject, target)
+	case TypeMention:
+		return fmt.Sprintf("%s mentioned you in a %s", subject, target)
+	}
+	return ""
+}
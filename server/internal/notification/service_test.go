@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	created []Event
+	groups  []NotificationGroup
+	unread  int64
+	repoErr error
+	gotRead string
+	readAll bool
+}
+
+func (m *MockRepo) CreateNotification(_ string, e Event) (bool, error) {
+	m.created = append(m.created, e)
+	return true, nil
+}
+
+func (m *MockRepo) GetNotifications(NotificationFilter) ([]NotificationGroup, error) {
+	return m.groups, m.repoErr
+}
+
+func (m *MockRepo) CountUnread(string) (int64, error) {
+	return m.unread, m.repoErr
+}
+
+func (m *MockRepo) MarkRead(_, notificationUUID string) error {
+	m.gotRead = notificationUUID
+	return m.repoErr
+}
+
+func (m *MockRepo) MarkAllRead(string) (int64, error) {
+	m.readAll = true
+	return 2, m.repoErr
+}
+
+type MockPublisher struct {
+	events []realtime.Event
+}
+
+func (m *MockPublisher) Publish(e realtime.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func TestServiceNotify(t *testing.T) {
+	valid := Event{
+		Type:          TypeMention,
+		ActorUUID:     "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		RecipientUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+		TargetType:    TargetPost,
+		TargetUUID:    "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+	}
+	self := valid
+	self.RecipientUUID = valid.ActorUUID
+	badType := valid
+	badType.Type = "poke"
+
+	testTable := []struct {
+		title       string
+		event       Event
+		wantErr     error
+		wantCreated int
+	}{
+		{"should create notification", valid, nil, 1},
+		{"should skip self notification", self, nil, 0},
+		{"should reject unknown type", badType, ErrInvalidEvent, 0},
+		{"should reject missing recipient", Event{Type: TypeMention}, ErrInvalidEvent, 0},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := &MockRepo{}
+			publisher := &MockPublisher{}
+			s := NewNotificationService(repo, publisher)
+			err := s.Notify(v.event)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Lenf(t, repo.created, v.wantCreated, "Want %v notifications but got %v", v.wantCreated, len(repo.created))
+			assert.Lenf(t, publisher.events, v.wantCreated, "want one push per stored notification")
+			for _, e := range publisher.events {
+				assert.Equal(t, v.event.RecipientUUID, e.RecipientUUID)
+			}
+		})
+	}
+}
+
+func TestServiceGetNotifications(t *testing.T) {
+	ong := Actor{"f6630558-b800-48ff-9a09-5863d6055154", "ong"}
+	bob := Actor{"ea151663-aad6-45b2-808b-e3f160956612", "bob"}
+	repo := &MockRepo{
+		groups: []NotificationGroup{
+			{Type: TypeLike, TargetType: TargetPost, Actors: []Actor{ong, bob}, ActorCount: 5},
+			{Type: TypeComment, TargetType: TargetPost, Actors: []Actor{bob, ong}, ActorCount: 2},
+			{Type: TypeFollow, TargetType: TargetUser, Actors: []Actor{ong}, ActorCount: 1},
+			{Type: TypeMention, TargetType: TargetComment, Actors: []Actor{bob}, ActorCount: 1},
+		},
+		unread: 3,
+	}
+	s := NewNotificationService(repo, &MockPublisher{})
+	list, err := s.GetNotifications(NotificationFilter{RecipientUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+
+	want := []string{
+		"ong and 4 others liked your post",
+		"bob and 1 other commented on your post",
+		"ong followed you",
+		"bob mentioned you in a comment",
+	}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, int64(3), list.UnreadCount)
+	for i, g := range list.Notifications {
+		assert.Equal(t, want[i], g.Message)
+	}
+}
+
+func TestServiceMarkRead(t *testing.T) {
+	repo := &MockRepo{repoErr: ErrNotificationNotFound}
+	s := NewNotificationService(repo, &MockPublisher{})
+	err := s.MarkRead("e936e164-52fa-4fd5-b0e0-597c2f270245", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11")
+	assert.Equal(t, ErrNotificationNotFound, err)
+	assert.Equal(t, "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", repo.gotRead)
+}
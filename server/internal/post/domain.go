@@ -1,6 +1,25 @@
 package post
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dsypasit/social-clone/server/internal/linkpreview"
+)
+
+const (
+	VisibilityPublic    = 1
+	VisibilityFollowers = 2
+	VisibilityPrivate   = 3
+)
+
+const (
+	MinPollOptions      = 2
+	MaxPollOptions      = 4
+	MaxPollOptionLength = 25
+	MaxPollDuration     = 7 * 24 * time.Hour
+)
 
 type Post struct {
 	ID               int    `json:"id"`
@@ -21,17 +40,246 @@ type PostResponse struct {
 	UserUUID         *string   `json:"user_uuid"`
 	VisibilityTypeId int       `json:"visibility_type_id"`
 	UpdateAt         time.Time `json:"update_at"`
+	Mentions         Mentions  `json:"mentions,omitempty"`
+	IsRepost         bool      `json:"is_repost"`
+	IsQuote          bool      `json:"is_quote"`
+	NumRepost        int64     `json:"num_repost"`
+	NumQuote         int64     `json:"num_quote"`
+	RepostedByMe     bool      `json:"reposted_by_me"`
+	// Reactions counts each reaction type, NumLike is their total.
+	Reactions  ReactionCounts `json:"reactions"`
+	MyReaction *string        `json:"my_reaction"`
+	// BookmarkedByMe is only ever true for the viewer, bookmarks are private.
+	BookmarkedByMe bool `json:"bookmarked_by_me"`
+	// Edited is set once the content changed after publishing, EditedAt is
+	// the latest edit.
+	Edited   bool       `json:"edited"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	Poll     *Poll      `json:"poll,omitempty"`
+	// LinkPreview appears once the first link in Content has been fetched.
+	LinkPreview *linkpreview.Preview `json:"link_preview,omitempty"`
+	// Original is the reposted or quoted post, nil on a quote whose original
+	// is no longer visible.
+	Original *PostResponse `json:"original,omitempty"`
 }
 
 type PostCreated struct {
-	UUID             string `json:"uuid"`
-	Content          string `json:"content"`
-	NumLike          int64  `json:"num_like,omitempty"`
-	UserUUID         string `json:"user_uuid"`
-	VisibilityTypeId int    `json:"visibility_type_id"`
+	UUID             string       `json:"uuid"`
+	Content          string       `json:"content"`
+	NumLike          int64        `json:"num_like,omitempty"`
+	UserUUID         string       `json:"user_uuid"`
+	VisibilityTypeId int          `json:"visibility_type_id"`
+	QuotePostUUID    string       `json:"quote_post_uuid,omitempty"`
+	Draft            bool         `json:"draft,omitempty"`
+	PublishAt        *time.Time   `json:"publish_at,omitempty"`
+	Poll             *PollCreated `json:"poll,omitempty"`
+	Hashtags         []string     `json:"-"`
+	Mentions         Mentions     `json:"-"`
+}
+
+// PollCreated attaches a poll to a new post, options keep their order.
+type PollCreated struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+// Poll is a post's poll as the viewer sees it. Votes and TotalVotes stay nil
+// until the viewer has voted or the poll has closed.
+type Poll struct {
+	Options    []PollOption `json:"options"`
+	ClosesAt   time.Time    `json:"closes_at"`
+	Closed     bool         `json:"closed"`
+	MyVote     *int         `json:"my_vote"`
+	TotalVotes *int64       `json:"total_votes,omitempty"`
+}
+
+type PollOption struct {
+	Position int    `json:"position"`
+	Text     string `json:"text"`
+	Votes    *int64 `json:"votes,omitempty"`
+}
+
+type PollVote struct {
+	Position int `json:"position"`
+}
+
+// Publishes reports whether the post goes out as soon as it is created. Draft
+// holds it back until the author publishes it, PublishAt until the scheduler
+// does.
+func (p PostCreated) Publishes() bool {
+	return !p.Draft && p.PublishAt == nil
+}
+
+// Draft is an unpublished post as its author sees it, PublishAt is nil until
+// the draft is scheduled.
+type Draft struct {
+	UUID             string     `json:"uuid"`
+	Content          string     `json:"content"`
+	VisibilityTypeId int        `json:"visibility_type_id"`
+	QuotePostUUID    *string    `json:"quote_post_uuid,omitempty"`
+	PublishAt        *time.Time `json:"publish_at"`
+	UpdateAt         time.Time  `json:"update_at"`
+}
+
+// DraftUpdated replaces the editable fields of a draft, a nil PublishAt
+// unschedules it.
+type DraftUpdated struct {
+	UUID             string     `json:"-"`
+	UserUUID         string     `json:"-"`
+	Content          string     `json:"content"`
+	VisibilityTypeId int        `json:"visibility_type_id"`
+	PublishAt        *time.Time `json:"publish_at"`
+	Hashtags         []string   `json:"-"`
+	Mentions         Mentions   `json:"-"`
+}
+
+// PostUpdated replaces the content of a published post.
+type PostUpdated struct {
+	UUID     string   `json:"-"`
+	UserUUID string   `json:"-"`
+	Content  string   `json:"content"`
+	Hashtags []string `json:"-"`
+	Mentions Mentions `json:"-"`
+}
+
+// PostRevision is a replaced version of a post, CreatedAt is when that
+// version was written.
+type PostRevision struct {
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type VisibilityType struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
+
+type TrendingTag struct {
+	Name      string `json:"name"`
+	PostCount int64  `json:"post_count"`
+}
+
+type Mention struct {
+	UserUUID string `json:"user_uuid"`
+	Username string `json:"username"`
+	// Offset and Length count Unicode code points of Content and cover the
+	// leading '@'.
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+type Mentions []Mention
+
+// Scan reads the json array built by mentionsColumn.
+func (m *Mentions) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported mentions type %T", src)
+	}
+	if err := json.Unmarshal(b, (*[]Mention)(m)); err != nil {
+		return err
+	}
+	if len(*m) == 0 {
+		*m = nil
+	}
+	return nil
+}
+
+// ReactionCounts maps a reaction type to how many users reacted with it.
+type ReactionCounts map[string]int64
+
+// Scan reads the json object built by json_object_agg, a post without
+// reactions comes back as an empty object rather than null.
+func (c *ReactionCounts) Scan(src interface{}) error {
+	*c = ReactionCounts{}
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported reaction counts type %T", src)
+	}
+	return json.Unmarshal(b, (*map[string]int64)(c))
+}
+
+type embeddedPost struct {
+	post *PostResponse
+}
+
+// Scan reads the json object built for the original in PostColumns.
+func (e *embeddedPost) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		e.post = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported original post type %T", src)
+	}
+	e.post = &PostResponse{}
+	return json.Unmarshal(b, e.post)
+}
+
+type embeddedPoll struct {
+	poll *Poll
+}
+
+// Scan reads the json object built for the poll in PostColumns.
+func (e *embeddedPoll) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		e.poll = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported poll type %T", src)
+	}
+	e.poll = &Poll{}
+	return json.Unmarshal(b, e.poll)
+}
+
+type embeddedPreview struct {
+	preview *linkpreview.Preview
+}
+
+// Scan reads the json object built for the link preview in PostColumns.
+func (e *embeddedPreview) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		e.preview = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported link preview type %T", src)
+	}
+	e.preview = &linkpreview.Preview{}
+	return json.Unmarshal(b, e.preview)
+}
+
+type RepostCreated struct {
+	UUID string `json:"uuid"`
+}
@@ -5,8 +5,12 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/dsypasit/social-clone/server/internal/filter"
 	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
 )
 
 var (
@@ -17,8 +21,18 @@ var (
 
 type IPostService interface {
 	CreatePost(p PostCreated) (int64, error)
-	GetPostsByUserUUID(string) ([]PostResponse, error)
-	GetPosts() ([]PostResponse, error)
+	GetPostsByUserUUID(userUUID, viewerUUID string) ([]PostResponse, error)
+	GetPosts(viewerUUID string) ([]PostResponse, error)
+	GetPostsByHashtag(tag, viewerUUID string, limit, offset int) ([]PostResponse, error)
+	GetTrendingHashtags(window time.Duration, limit int) ([]TrendingTag, error)
+	Repost(userUUID, postUUID string) (string, error)
+	Unrepost(userUUID, postUUID string) error
+	GetDrafts(userUUID string) ([]Draft, error)
+	UpdateDraft(DraftUpdated) error
+	PublishDraft(userUUID, postUUID string) error
+	UpdatePost(PostUpdated) error
+	GetRevisions(postUUID, viewerUUID string) ([]PostRevision, error)
+	Vote(userUUID, postUUID string, position int) error
 }
 
 type PostHandler struct {
@@ -62,10 +76,26 @@ func (h *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if newPost.QuotePostUUID != "" && !util.IsValidUUID(newPost.QuotePostUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
 	_, err := h.postService.CreatePost(newPost)
 	if err != nil {
-		errServicErr := util.BuildErrResponse("service error")
-		util.SendJson(w, errServicErr(err), http.StatusInternalServerError)
+		switch err {
+		case ErrInvalidPublish, ErrInvalidPoll, util.ErrTextEmpty, util.ErrTextTooLong, util.ErrZeroWidthAbuse,
+			filter.ErrBlockedWord, filter.ErrLinkSpam, filter.ErrDuplicate:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case filter.ErrTooFrequent:
+			util.SendJson(w, util.BuildErrResponse("too many requests")(err), http.StatusTooManyRequests)
+		case ErrPostNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrNotRepostable:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -82,7 +112,8 @@ func (h *PostHandler) GetPostsByUserUUID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	posts, err := h.postService.GetPostsByUserUUID(userUUID)
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	posts, err := h.postService.GetPostsByUserUUID(userUUID, viewerUUID)
 	if err != nil {
 		errRes := util.BuildErrResponse("service failed")
 		util.SendJson(w, errRes(err), http.StatusInternalServerError)
@@ -91,3 +122,311 @@ func (h *PostHandler) GetPostsByUserUUID(w http.ResponseWriter, r *http.Request)
 
 	util.SendJson(w, posts, http.StatusOK)
 }
+
+func (h *PostHandler) GetPostsByHashtag(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	posts, err := h.postService.GetPostsByHashtag(mux.Vars(r)["tag"], viewerUUID, page.Limit, page.Offset)
+	if err != nil {
+		if err == ErrInvalidHashtag {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, posts, http.StatusOK)
+}
+
+// GetTrendingHashtags accepts an optional window as a Go duration ("6h") and
+// an optional limit.
+func (h *PostHandler) GetTrendingHashtags(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	q := r.URL.Query()
+
+	var window time.Duration
+	if v := q.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			util.SendJson(w, errInvalidReq(ErrInvalidWindow), http.StatusBadRequest)
+			return
+		}
+		window = d
+	}
+
+	var limit int
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			util.SendJson(w, errInvalidReq(util.ErrInvalidPage), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	tags, err := h.postService.GetTrendingHashtags(window, limit)
+	if err != nil {
+		if err == ErrInvalidWindow {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, tags, http.StatusOK)
+}
+
+func (h *PostHandler) Repost(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := h.postService.Repost(userUUID, postUUID)
+	if err != nil {
+		switch err {
+		case ErrPostNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrNotRepostable:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		case ErrAlreadyReposted:
+			util.SendJson(w, util.BuildErrResponse("duplicate repost")(err), http.StatusConflict)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, RepostCreated{uuid}, http.StatusCreated)
+}
+
+func (h *PostHandler) Unrepost(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.postService.Unrepost(userUUID, postUUID); err != nil {
+		if err == ErrNotReposted {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("removed repost successful!"), http.StatusOK)
+}
+
+func (h *PostHandler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	drafts, err := h.postService.GetDrafts(userUUID)
+	if err != nil {
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, drafts, http.StatusOK)
+}
+
+func (h *PostHandler) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	var d DraftUpdated
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+	if d.Content == "" || d.VisibilityTypeId == 0 {
+		util.SendJson(w, errInvalidReq(ErrInCompleteInfo), http.StatusBadRequest)
+		return
+	}
+	d.UUID, d.UserUUID = postUUID, userUUID
+
+	if err := h.postService.UpdateDraft(d); err != nil {
+		switch err {
+		case ErrInvalidPublish, ErrInvalidPoll, util.ErrTextEmpty, util.ErrTextTooLong, util.ErrZeroWidthAbuse,
+			filter.ErrBlockedWord, filter.ErrLinkSpam:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrPostNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("updated draft successful!"), http.StatusOK)
+}
+
+func (h *PostHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.postService.PublishDraft(userUUID, postUUID); err != nil {
+		if err == ErrPostNotFound {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("published post successful!"), http.StatusOK)
+}
+
+func (h *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	var p PostUpdated
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+	if p.Content == "" {
+		util.SendJson(w, errInvalidReq(ErrInCompleteInfo), http.StatusBadRequest)
+		return
+	}
+	p.UUID, p.UserUUID = postUUID, userUUID
+
+	if err := h.postService.UpdatePost(p); err != nil {
+		switch err {
+		case util.ErrTextEmpty, util.ErrTextTooLong, util.ErrZeroWidthAbuse, filter.ErrBlockedWord, filter.ErrLinkSpam:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrPostNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrEditWindowClosed:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("updated post successful!"), http.StatusOK)
+}
+
+func (h *PostHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	revisions, err := h.postService.GetRevisions(postUUID, viewerUUID)
+	if err != nil {
+		if err == ErrPostNotFound {
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, revisions, http.StatusOK)
+}
+
+func (h *PostHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	postUUID := mux.Vars(r)["uuid"]
+	if !util.IsValidUUID(postUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	var v PollVote
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+	if v.Position < 1 || v.Position > MaxPollOptions {
+		util.SendJson(w, errInvalidReq(ErrInvalidOption), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.postService.Vote(userUUID, postUUID, v.Position); err != nil {
+		switch err {
+		case ErrInvalidOption:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrPollNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrPollClosed:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		case ErrAlreadyVoted:
+			util.SendJson(w, util.BuildErrResponse("duplicate vote")(err), http.StatusConflict)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("voted successful!"), http.StatusCreated)
+}
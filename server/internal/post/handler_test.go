@@ -8,9 +8,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dsypasit/social-clone/server/internal/filter"
 	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,14 +27,54 @@ func (m *mService) CreatePost(PostCreated) (int64, error) {
 	return 1, m.isErr
 }
 
-func (m *mService) GetPostsByUserUUID(string) ([]PostResponse, error) {
+func (m *mService) GetPostsByUserUUID(string, string) ([]PostResponse, error) {
 	return m.postsResp, m.isErr
 }
 
-func (m *mService) GetPosts() ([]PostResponse, error) {
+func (m *mService) GetPosts(string) ([]PostResponse, error) {
 	return m.postsResp, m.isErr
 }
 
+func (m *mService) GetPostsByHashtag(string, string, int, int) ([]PostResponse, error) {
+	return m.postsResp, m.isErr
+}
+
+func (m *mService) GetTrendingHashtags(time.Duration, int) ([]TrendingTag, error) {
+	return []TrendingTag{{"golang", 3}}, m.isErr
+}
+
+func (m *mService) Repost(string, string) (string, error) {
+	return "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", m.isErr
+}
+
+func (m *mService) Unrepost(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) GetDrafts(string) ([]Draft, error) {
+	return []Draft{}, m.isErr
+}
+
+func (m *mService) UpdateDraft(DraftUpdated) error {
+	return m.isErr
+}
+
+func (m *mService) PublishDraft(string, string) error {
+	return m.isErr
+}
+
+func (m *mService) UpdatePost(PostUpdated) error {
+	return m.isErr
+}
+
+func (m *mService) Vote(string, string, int) error {
+	return m.isErr
+}
+
+func (m *mService) GetRevisions(string, string) ([]PostRevision, error) {
+	return []PostRevision{}, m.isErr
+}
+
 func TestHandlerCreatePost(t *testing.T) {
 	post, _ := json.Marshal(PostCreated{
 		Content: "hello", UserUUID: "1eb64cd3-03ef-4ac7-9008-e0ab63f4105f",
@@ -47,11 +91,19 @@ func TestHandlerCreatePost(t *testing.T) {
 			"should create post success", post, nil, http.StatusCreated,
 			util.BuildResponse("created post successful!"),
 		},
+		{
+			"should reject filtered post", post, filter.ErrBlockedWord, http.StatusBadRequest,
+			util.BuildErrResponse("invalid request")(filter.ErrBlockedWord),
+		},
+		{
+			"should reject too frequent post", post, filter.ErrTooFrequent, http.StatusTooManyRequests,
+			util.BuildErrResponse("too many requests")(filter.ErrTooFrequent),
+		},
 	}
 
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
-			mService := mService{}
+			mService := mService{nil, v.serviceErr}
 			h := NewPostHandler(&mService)
 
 			req, _ := http.NewRequest(http.MethodGet, "/", bytes.NewReader(v.post))
@@ -246,3 +298,326 @@ func TestHandlerGetPost(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerGetPostsByHashtag(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return posts", "", nil, http.StatusOK},
+		{"should bad request cause invalid page", "?limit=abc", nil, http.StatusBadRequest},
+		{"should bad request cause invalid tag", "", ErrInvalidHashtag, http.StatusBadRequest},
+		{"should service failed", "", errors.New("db err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			handler := NewPostHandler(&mService{[]PostResponse{}, v.serviceErr})
+			req := httptest.NewRequest(http.MethodGet, "/post/hashtag/golang"+v.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"tag": "golang"})
+			rec := httptest.NewRecorder()
+			handler.GetPostsByHashtag(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetTrendingHashtags(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return trending tags", "?window=6h&limit=5", nil, http.StatusOK},
+		{"should bad request cause invalid window", "?window=abc", nil, http.StatusBadRequest},
+		{"should bad request cause invalid limit", "?limit=-1", nil, http.StatusBadRequest},
+		{"should bad request cause window too large", "?window=1000h", ErrInvalidWindow, http.StatusBadRequest},
+		{"should service failed", "", errors.New("db err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			handler := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodGet, "/post/trending-tags"+v.query, nil)
+			rec := httptest.NewRecorder()
+			handler.GetTrendingHashtags(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerCreatePost_Quote(t *testing.T) {
+	testTable := []struct {
+		title      string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should create quote", `{"content":"so true","visibility_type_id":1,"quote_post_uuid":"f307d2db-d2ea-4ec9-8d31-27b7443d7c72"}`, nil, http.StatusCreated},
+		{"should bad request cause invalid quote uuid", `{"content":"so true","visibility_type_id":1,"quote_post_uuid":"abc"}`, nil, http.StatusBadRequest},
+		{"should not found", `{"content":"so true","visibility_type_id":1,"quote_post_uuid":"f307d2db-d2ea-4ec9-8d31-27b7443d7c72"}`, ErrPostNotFound, http.StatusNotFound},
+		{"should forbidden", `{"content":"so true","visibility_type_id":1,"quote_post_uuid":"f307d2db-d2ea-4ec9-8d31-27b7443d7c72"}`, ErrNotRepostable, http.StatusForbidden},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPost, "/post", bytes.NewReader([]byte(v.body)))
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.CreatePost(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerRepost(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should repost", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", nil, http.StatusCreated},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest},
+		{"should not found", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", ErrPostNotFound, http.StatusNotFound},
+		{"should forbidden", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", ErrNotRepostable, http.StatusForbidden},
+		{"should conflict", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", ErrAlreadyReposted, http.StatusConflict},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.Repost(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerUnrepost(t *testing.T) {
+	testTable := []struct {
+		title      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should remove repost", nil, http.StatusOK},
+		{"should not found", ErrNotReposted, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": "f307d2db-d2ea-4ec9-8d31-27b7443d7c72"})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.Unrepost(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetDrafts(t *testing.T) {
+	testTable := []struct {
+		title      string
+		userUUID   any
+		serviceErr error
+		wantStatus int
+	}{
+		{"should list drafts", "e936e164-52fa-4fd5-b0e0-597c2f270245", nil, http.StatusOK},
+		{"should unauthorized", nil, nil, http.StatusUnauthorized},
+		{"should service error", "e936e164-52fa-4fd5-b0e0-597c2f270245", errors.New("db err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if v.userUUID != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "userUUID", v.userUUID))
+			}
+			rec := httptest.NewRecorder()
+			h.GetDrafts(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerUpdateDraft(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should update draft", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"hi","visibility_type_id":1}`, nil, http.StatusOK},
+		{"should schedule draft", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"hi","visibility_type_id":1,"publish_at":"2026-10-20T09:00:00Z"}`, nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", `{"content":"hi","visibility_type_id":1}`, nil, http.StatusBadRequest},
+		{"should bad request cause empty content", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"visibility_type_id":1}`, nil, http.StatusBadRequest},
+		{"should bad request cause past publish_at", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"hi","visibility_type_id":1}`, ErrInvalidPublish, http.StatusBadRequest},
+		{"should bad request cause poll outside schedule", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"hi","visibility_type_id":1}`, ErrInvalidPoll, http.StatusBadRequest},
+		{"should not found", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"hi","visibility_type_id":1}`, ErrPostNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(v.body))
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.UpdateDraft(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerPublishDraft(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should publish", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest},
+		{"should not found", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", ErrPostNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.PublishDraft(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerUpdatePost(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should update post", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"fixed"}`, nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", `{"content":"fixed"}`, nil, http.StatusBadRequest},
+		{"should bad request cause empty content", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{}`, nil, http.StatusBadRequest},
+		{"should not found", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"fixed"}`, ErrPostNotFound, http.StatusNotFound},
+		{"should forbidden after edit window", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"content":"fixed"}`, ErrEditWindowClosed, http.StatusForbidden},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(v.body))
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.UpdatePost(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerGetRevisions(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should list revisions", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", nil, http.StatusOK},
+		{"should bad request cause invalid uuid", "abc", nil, http.StatusBadRequest},
+		{"should not found", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", ErrPostNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			rec := httptest.NewRecorder()
+			h.GetRevisions(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerCreatePost_Poll(t *testing.T) {
+	body := `{"content":"tabs or spaces?","visibility_type_id":1,"poll":{"options":["tabs","spaces"],"closes_at":"2026-10-21T00:00:00Z"}}`
+	testTable := []struct {
+		title      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should create post with poll", nil, http.StatusCreated},
+		{"should bad request cause invalid poll", ErrInvalidPoll, http.StatusBadRequest},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.CreatePost(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerVote(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should vote", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"position":2}`, nil, http.StatusCreated},
+		{"should bad request cause invalid uuid", "abc", `{"position":2}`, nil, http.StatusBadRequest},
+		{"should bad request cause out of range option", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"position":5}`, nil, http.StatusBadRequest},
+		{"should bad request cause missing option", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"position":3}`, ErrInvalidOption, http.StatusBadRequest},
+		{"should not found", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"position":1}`, ErrPollNotFound, http.StatusNotFound},
+		{"should forbidden cause closed", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"position":1}`, ErrPollClosed, http.StatusForbidden},
+		{"should conflict cause voted", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", `{"position":1}`, ErrAlreadyVoted, http.StatusConflict},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewPostHandler(&mService{nil, v.serviceErr})
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(v.body))
+			req = mux.SetURLVars(req, map[string]string{"uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.Vote(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
@@ -1,6 +1,24 @@
 package post
 
-import "database/sql"
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrPostNotFound     = errors.New("post not found")
+	ErrAlreadyReposted  = errors.New("post already reposted")
+	ErrNotReposted      = errors.New("post not reposted")
+	ErrEditWindowClosed = errors.New("post can no longer be edited")
+	ErrPollNotFound     = errors.New("poll not found")
+	ErrPollClosed       = errors.New("poll closed")
+	ErrInvalidOption    = errors.New("invalid poll option")
+	ErrAlreadyVoted     = errors.New("already voted")
+)
 
 type PostRepository struct {
 	db *sql.DB
@@ -11,39 +29,247 @@ func NewPostRepository(db *sql.DB) *PostRepository {
 }
 
 func (r *PostRepository) CreatePost(p PostCreated) (int64, error) {
-	query := `INSERT INTO post (uuid, content, num_like, visibility_type_id, app_user_id)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO post (uuid, content, num_like, visibility_type_id, app_user_id, original_post_id, is_quote,
+      publish_at, published_at)
     VALUES (
         $1,
         $2,
         $3,
         $4,
-        (SELECT id FROM app_user WHERE uuid = $5)
+        (SELECT id FROM app_user WHERE uuid = $5),
+        (SELECT id FROM post WHERE uuid = $6),
+        $6::uuid IS NOT NULL,
+        $7::timestamptz,
+        CASE WHEN $8 THEN CURRENT_TIMESTAMP END
     ) RETURNING id`
 
+	var quoteUUID any
+	if p.QuotePostUUID != "" {
+		quoteUUID = p.QuotePostUUID
+	}
+	var publishAt any
+	if p.PublishAt != nil {
+		publishAt = *p.PublishAt
+	}
+
 	var id int64
-	err := r.db.QueryRow(query, p.UUID, p.Content, 0,
-		p.VisibilityTypeId, p.UserUUID).Scan(&id)
-	return id, err
+	err = tx.QueryRow(query, p.UUID, p.Content, 0,
+		p.VisibilityTypeId, p.UserUUID, quoteUUID, publishAt, p.Publishes()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertHashtags(tx, id, p.Hashtags); err != nil {
+		return 0, err
+	}
+	if err := insertMentions(tx, id, p.Mentions); err != nil {
+		return 0, err
+	}
+	if err := insertPoll(tx, id, p.Poll); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
 }
 
-func (r *PostRepository) GetPostsByUserUUID(userUUID string) ([]PostResponse, error) {
+func insertPoll(tx *sql.Tx, postID int64, poll *PollCreated) error {
+	if poll == nil {
+		return nil
+	}
+
+	var pollID int64
+	err := tx.QueryRow(`INSERT INTO poll (post_id, closes_at) VALUES ($1, $2) RETURNING id`,
+		postID, poll.ClosesAt.UTC()).Scan(&pollID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO poll_option (poll_id, position, text)
+    SELECT $1, o.position, o.text FROM unnest($2::text[]) WITH ORDINALITY AS o(text, position)`,
+		pollID, pq.Array(poll.Options))
+	return err
+}
+
+func insertMentions(tx *sql.Tx, postID int64, mentions Mentions) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	userUUIDs := make([]string, len(mentions))
+	offsets := make([]int64, len(mentions))
+	lengths := make([]int64, len(mentions))
+	for i, m := range mentions {
+		userUUIDs[i], offsets[i], lengths[i] = m.UserUUID, int64(m.Offset), int64(m.Length)
+	}
+
+	_, err := tx.Exec(`INSERT INTO post_mention (post_id, user_id, start_index, length)
+    SELECT $1, u.id, m.start_index, m.length
+    FROM unnest($2::uuid[], $3::int[], $4::int[]) AS m(user_uuid, start_index, length)
+    JOIN app_user AS u ON u.uuid = m.user_uuid`,
+		postID, pq.Array(userUUIDs), pq.Array(offsets), pq.Array(lengths))
+	return err
+}
+
+func insertHashtags(tx *sql.Tx, postID int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`INSERT INTO hashtag (name) SELECT unnest($1::text[])
+    ON CONFLICT (name) DO NOTHING`, pq.Array(tags))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO post_hashtag (post_id, hashtag_id)
+    SELECT $1, id FROM hashtag WHERE name = ANY($2)`, postID, pq.Array(tags))
+	return err
+}
+
+// VisibleTo is the condition under which the viewer ($1) may read the post
+// aliased postAlias written by userAlias: their own posts, public ones, and
+// followers-only ones from authors they follow.
+func VisibleTo(postAlias, userAlias string) string {
+	return fmt.Sprintf(`(
+    %[2]s.uuid = $1
+    OR %[1]s.visibility_type_id = %[3]d
+    OR (%[1]s.visibility_type_id = %[4]d AND EXISTS (
+      SELECT 1 FROM follows AS f
+      WHERE f.followed_id = %[2]s.id AND f.follower_id = (SELECT id FROM app_user WHERE uuid = $1)
+    ))
+  )`, postAlias, userAlias, VisibilityPublic, VisibilityFollowers)
+}
+
+// NotBlocked is false when userAlias and the viewer ($1) have blocked each
+// other in either direction.
+func NotBlocked(userAlias string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+    SELECT 1 FROM blocks AS b
+    WHERE (b.blocker_id = %[1]s.id AND b.blocked_id = (SELECT id FROM app_user WHERE uuid = $1))
+    OR (b.blocked_id = %[1]s.id AND b.blocker_id = (SELECT id FROM app_user WHERE uuid = $1))
+  )`, userAlias)
+}
+
+// originalJoin finds the original of p if the viewer ($1) can still read it.
+var originalJoin = `FROM post AS op
+    JOIN app_user AS ou ON ou.id = op.app_user_id
+    WHERE op.id = p.original_post_id AND op.deleted_at IS NULL AND op.hidden_at IS NULL
+    AND ` + VisibleTo("op", "ou") + ` AND ` + NotBlocked("ou")
+
+// PostColumns is the select list for a PostResponse, read back by ScanPost.
+// Poll tallies are left out until the viewer has voted or the poll closed.
+// The query must alias the post as p, its author as u, and pass the viewer
+// as $1. The original's timestamps are cast to timestamptz so the json
+// carries the database zone offset.
+var PostColumns = `p.uuid, p.content, p.num_like, p.visibility_type_id, u.uuid, u.username, p.updated_at,
+  (SELECT json_agg(json_build_object(
+      'user_uuid', mu.uuid, 'username', mu.username, 'offset', pm.start_index, 'length', pm.length
+    ) ORDER BY pm.start_index)
+    FROM post_mention AS pm
+    JOIN app_user AS mu ON mu.id = pm.user_id
+    WHERE pm.post_id = p.id),
+  p.original_post_id IS NOT NULL AND NOT p.is_quote, p.is_quote,
+  (SELECT count(*) FROM post AS rp
+    WHERE rp.original_post_id = p.id AND NOT rp.is_quote AND rp.deleted_at IS NULL AND rp.hidden_at IS NULL),
+  (SELECT count(*) FROM post AS rp
+    WHERE rp.original_post_id = p.id AND rp.is_quote AND rp.deleted_at IS NULL AND rp.hidden_at IS NULL),
+  EXISTS (SELECT 1 FROM post AS rp
+    WHERE rp.original_post_id = p.id AND NOT rp.is_quote AND rp.deleted_at IS NULL
+    AND rp.app_user_id = (SELECT id FROM app_user WHERE uuid = $1)),
+  (SELECT json_build_object(
+      'uuid', op.uuid, 'content', op.content, 'num_like', op.num_like, 'visibility_type_id', op.visibility_type_id,
+      'user_uuid', ou.uuid, 'username', ou.username, 'update_at', op.updated_at::timestamptz,
+      'edited', op.edited_at IS NOT NULL, 'edited_at', op.edited_at::timestamptz
+    )
+    ` + originalJoin + `),
+  (SELECT json_object_agg(rt.type, rt.n)
+    FROM (SELECT type, count(*) AS n FROM reaction WHERE post_id = p.id GROUP BY type) AS rt),
+  (SELECT type FROM reaction
+    WHERE post_id = p.id AND app_user_id = (SELECT id FROM app_user WHERE uuid = $1)),
+  EXISTS (SELECT 1 FROM bookmark
+    WHERE post_id = p.id AND app_user_id = (SELECT id FROM app_user WHERE uuid = $1)),
+  p.edited_at,
+  (SELECT json_build_object(
+      'options', (SELECT json_agg(json_build_object(
+          'position', o.position, 'text', o.text,
+          'votes', CASE WHEN rv.revealed THEN (SELECT count(*) FROM poll_vote WHERE option_id = o.id) END
+        ) ORDER BY o.position)
+        FROM poll_option AS o WHERE o.poll_id = pl.id),
+      'closes_at', pl.closes_at AT TIME ZONE 'UTC',
+      'closed', pl.closes_at <= (now() AT TIME ZONE 'UTC'),
+      'my_vote', mo.position,
+      'total_votes', CASE WHEN rv.revealed THEN (SELECT count(*) FROM poll_vote WHERE poll_id = pl.id) END
+    )
+    FROM poll AS pl
+    LEFT JOIN poll_vote AS mv ON mv.poll_id = pl.id AND mv.app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+    LEFT JOIN poll_option AS mo ON mo.id = mv.option_id
+    CROSS JOIN LATERAL (SELECT mv.id IS NOT NULL OR pl.closes_at <= (now() AT TIME ZONE 'UTC') AS revealed) AS rv
+    WHERE pl.post_id = p.id),
+  (SELECT json_build_object(
+      'url', lp.url, 'title', lp.title, 'description', lp.description, 'image_url', lp.image_url,
+      'site_name', lp.site_name, 'fetched_at', lp.fetched_at AT TIME ZONE 'UTC'
+    )
+    FROM link_preview AS lp WHERE lp.post_id = p.id)`
+
+func ScanPost(rows *sql.Rows) (PostResponse, error) {
+	var p PostResponse
+	var original embeddedPost
+	var poll embeddedPoll
+	var preview embeddedPreview
+	err := rows.Scan(&p.UUID, &p.Content, &p.NumLike, &p.VisibilityTypeId, &p.UserUUID, &p.Username, &p.UpdateAt, &p.Mentions,
+		&p.IsRepost, &p.IsQuote, &p.NumRepost, &p.NumQuote, &p.RepostedByMe, &original,
+		&p.Reactions, &p.MyReaction, &p.BookmarkedByMe, &p.EditedAt, &poll, &preview)
+	p.Edited = p.EditedAt != nil
+	p.Original = original.post
+	p.Poll = poll.poll
+	p.LinkPreview = preview.preview
+	return p, err
+}
+
+// blockFilter drops posts whose author and the viewer ($1) have blocked each
+// other in either direction.
+var blockFilter = `
+  AND ` + NotBlocked("u")
+
+// muteFilter drops posts from authors the viewer ($1) has muted, it only
+// applies to the feed and not to a user's own profile.
+const muteFilter = `
+  AND NOT EXISTS (
+    SELECT 1 FROM mutes AS m
+    WHERE m.muted_id = u.id AND m.muter_id = (SELECT id FROM app_user WHERE uuid = $1)
+  )`
+
+// visibilityFilter keeps posts the viewer ($1) is allowed to read.
+var visibilityFilter = `
+  AND ` + VisibleTo("p", "u")
+
+// repostFilter drops plain reposts whose original was deleted, hidden or is
+// no longer visible to the viewer ($1). Quotes stay, without the original.
+var repostFilter = `
+  AND (p.original_post_id IS NULL OR p.is_quote OR EXISTS (SELECT 1 ` + originalJoin + `))`
+
+func (r *PostRepository) GetPostsByUserUUID(userUUID, viewerUUID string) ([]PostResponse, error) {
 	query := `
-  SELECT p.uuid, p.content, p.num_like, p.visibility_type_id, u.uuid, u.username, u.updated_at
+  SELECT ` + PostColumns + `
   FROM post as p
   LEFT JOIN app_user AS u ON u.id = p.app_user_id
-  WHERE u.uuid=$1
+  WHERE u.uuid=$2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL` + visibilityFilter + blockFilter + repostFilter + `
   ORDER BY u.updated_at DESC
   `
 
 	var posts []PostResponse
-	rows, err := r.db.Query(query, userUUID)
+	rows, err := r.db.Query(query, viewerUUID, userUUID)
 	if err != nil {
 		return nil, err
 	}
 
 	for rows.Next() {
-		var post PostResponse
-		err := rows.Scan(&post.UUID, &post.Content, &post.NumLike, &post.VisibilityTypeId, &post.UserUUID, &post.Username, &post.UpdateAt)
+		post, err := ScanPost(rows)
 		if err != nil {
 			return posts, err
 		}
@@ -53,23 +279,23 @@ func (r *PostRepository) GetPostsByUserUUID(userUUID string) ([]PostResponse, er
 	return posts, err
 }
 
-func (r *PostRepository) GetPosts() ([]PostResponse, error) {
+func (r *PostRepository) GetPosts(viewerUUID string) ([]PostResponse, error) {
 	query := `
-  SELECT p.uuid, p.content, p.num_like, p.visibility_type_id, u.uuid, u.username, u.updated_at
+  SELECT ` + PostColumns + `
   FROM post as p
   LEFT JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL` + visibilityFilter + blockFilter + muteFilter + repostFilter + `
   ORDER BY u.updated_at DESC
   `
 
 	var posts []PostResponse
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, viewerUUID)
 	if err != nil {
 		return nil, err
 	}
 
 	for rows.Next() {
-		var post PostResponse
-		err := rows.Scan(&post.UUID, &post.Content, &post.NumLike, &post.VisibilityTypeId, &post.UserUUID, &post.Username, &post.UpdateAt)
+		post, err := ScanPost(rows)
 		if err != nil {
 			return posts, err
 		}
@@ -78,3 +304,429 @@ func (r *PostRepository) GetPosts() ([]PostResponse, error) {
 
 	return posts, err
 }
+
+func (r *PostRepository) GetPostsByHashtag(tag, viewerUUID string, limit, offset int) ([]PostResponse, error) {
+	query := `
+  SELECT ` + PostColumns + `
+  FROM post_hashtag AS ph
+  JOIN hashtag AS h ON h.id = ph.hashtag_id
+  JOIN post AS p ON p.id = ph.post_id
+  JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE h.name = $2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL` + visibilityFilter + blockFilter + `
+  ORDER BY ph.post_id DESC
+  LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, viewerUUID, tag, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []PostResponse{}
+	for rows.Next() {
+		post, err := ScanPost(rows)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, rows.Err()
+}
+
+// GetTrendingHashtags ranks tags by how many distinct public posts used them
+// within the window. The cutoff is taken in SQL, published_at is in the
+// database time zone. Hidden, deleted and non-public posts do not count so
+// trending cannot leak content the caller may not see. Posts count from when
+// they were published, so a scheduled post trends when it goes out.
+func (r *PostRepository) GetTrendingHashtags(window time.Duration, limit int) ([]TrendingTag, error) {
+	query := `
+  SELECT h.name, count(DISTINCT ph.post_id) AS post_count
+  FROM post_hashtag AS ph
+  JOIN hashtag AS h ON h.id = ph.hashtag_id
+  JOIN post AS p ON p.id = ph.post_id
+  WHERE p.published_at >= CURRENT_TIMESTAMP - make_interval(secs => $1)
+  AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.visibility_type_id = $2
+  GROUP BY h.name
+  ORDER BY post_count DESC, h.name
+  LIMIT $3`
+
+	rows, err := r.db.Query(query, window.Seconds(), VisibilityPublic, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []TrendingTag{}
+	for rows.Next() {
+		var t TrendingTag
+		if err := rows.Scan(&t.Name, &t.PostCount); err != nil {
+			return tags, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// GetPostForViewer returns the post if the viewer can read it, ErrPostNotFound
+// otherwise so hidden posts are indistinguishable from missing ones.
+func (r *PostRepository) GetPostForViewer(postUUID, viewerUUID string) (PostResponse, error) {
+	query := `
+  SELECT ` + PostColumns + `
+  FROM post AS p
+  JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE p.uuid = $2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL` + visibilityFilter + blockFilter + repostFilter
+
+	rows, err := r.db.Query(query, viewerUUID, postUUID)
+	if err != nil {
+		return PostResponse{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return PostResponse{}, err
+		}
+		return PostResponse{}, ErrPostNotFound
+	}
+	return ScanPost(rows)
+}
+
+// IsVisibleTo reports whether the viewer may read the post, for deciding who
+// is told about it.
+func (r *PostRepository) IsVisibleTo(postUUID, viewerUUID string) (bool, error) {
+	query := `
+  SELECT EXISTS (SELECT 1 FROM post AS p
+    JOIN app_user AS u ON u.id = p.app_user_id
+    WHERE p.uuid = $2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL` + visibilityFilter + blockFilter + `)`
+
+	var visible bool
+	err := r.db.QueryRow(query, viewerUUID, postUUID).Scan(&visible)
+	return visible, err
+}
+
+// Repost stores a plain repost, which is a public post with no content that
+// points at the original.
+func (r *PostRepository) Repost(uuid, userUUID, originalUUID string) (int64, error) {
+	query := `INSERT INTO post (uuid, content, num_like, visibility_type_id, app_user_id, original_post_id, is_quote, published_at)
+    VALUES ($1, '', 0, $2, (SELECT id FROM app_user WHERE uuid = $3), (SELECT id FROM post WHERE uuid = $4), false,
+      CURRENT_TIMESTAMP)
+    ON CONFLICT (app_user_id, original_post_id) WHERE NOT is_quote AND deleted_at IS NULL DO NOTHING
+    RETURNING id`
+
+	var id int64
+	err := r.db.QueryRow(query, uuid, VisibilityPublic, userUUID, originalUUID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, ErrAlreadyReposted
+	}
+	return id, err
+}
+
+func (r *PostRepository) Unrepost(userUUID, originalUUID string) error {
+	query := `UPDATE post SET deleted_at = current_date
+    WHERE app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND original_post_id = (SELECT id FROM post WHERE uuid = $2)
+    AND NOT is_quote AND deleted_at IS NULL`
+
+	result, err := r.db.Exec(query, userUUID, originalUUID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotReposted
+	}
+	return nil
+}
+
+// draftColumns is the select list for a Draft, the query must alias the post
+// as p. publish_at is kept in the database zone like published_at, so it is
+// written and read as timestamptz.
+const draftColumns = `p.uuid, p.content, p.visibility_type_id,
+  (SELECT uuid FROM post WHERE id = p.original_post_id), p.publish_at::timestamptz, p.updated_at`
+
+// GetDrafts lists the author's unpublished posts, scheduled ones first in the
+// order they go out.
+func (r *PostRepository) GetDrafts(userUUID string) ([]Draft, error) {
+	query := `
+  SELECT ` + draftColumns + `
+  FROM post AS p
+  WHERE p.app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+  AND p.published_at IS NULL AND p.deleted_at IS NULL
+  ORDER BY p.publish_at NULLS LAST, p.id DESC`
+
+	rows, err := r.db.Query(query, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []Draft{}
+	for rows.Next() {
+		var d Draft
+		if err := rows.Scan(&d.UUID, &d.Content, &d.VisibilityTypeId, &d.QuotePostUUID, &d.PublishAt, &d.UpdateAt); err != nil {
+			return drafts, err
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, rows.Err()
+}
+
+// UpdateDraft replaces the content of an unpublished post together with its
+// hashtags and mentions. Drafts of other users read as missing. A poll on
+// the draft must still fit the new schedule, ErrInvalidPoll otherwise.
+func (r *PostRepository) UpdateDraft(d DraftUpdated) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE post SET content = $3, visibility_type_id = $4, publish_at = $5::timestamptz, updated_at = CURRENT_TIMESTAMP
+    WHERE uuid = $1 AND app_user_id = (SELECT id FROM app_user WHERE uuid = $2)
+    AND published_at IS NULL AND deleted_at IS NULL
+    RETURNING id, (SELECT closes_at FROM poll WHERE post_id = post.id)`
+
+	var publishAt any
+	if d.PublishAt != nil {
+		publishAt = *d.PublishAt
+	}
+
+	var id int64
+	var closesAt sql.NullTime
+	err = tx.QueryRow(query, d.UUID, d.UserUUID, d.Content, d.VisibilityTypeId, publishAt).Scan(&id, &closesAt)
+	if err == sql.ErrNoRows {
+		return ErrPostNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if closesAt.Valid && !pollWindowValid(closesAt.Time, d.PublishAt) {
+		return ErrInvalidPoll
+	}
+
+	if _, err := tx.Exec("DELETE FROM post_hashtag WHERE post_id = $1", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM post_mention WHERE post_id = $1", id); err != nil {
+		return err
+	}
+	if err := insertHashtags(tx, id, d.Hashtags); err != nil {
+		return err
+	}
+	if err := insertMentions(tx, id, d.Mentions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// publishedReturning reads back what the service needs to announce a post
+// that was just published.
+const publishedReturning = `RETURNING p.uuid, (SELECT uuid FROM app_user WHERE id = p.app_user_id), p.visibility_type_id,
+    (SELECT array_agg(mu.uuid) FROM post_mention AS pm JOIN app_user AS mu ON mu.id = pm.user_id WHERE pm.post_id = p.id)`
+
+func scanPublished(rows *sql.Rows) (PostCreated, error) {
+	var p PostCreated
+	var mentioned []string
+	err := rows.Scan(&p.UUID, &p.UserUUID, &p.VisibilityTypeId, pq.Array(&mentioned))
+	for _, u := range mentioned {
+		p.Mentions = append(p.Mentions, Mention{UserUUID: u})
+	}
+	return p, err
+}
+
+// PublishDraft publishes one of the author's drafts right away.
+func (r *PostRepository) PublishDraft(userUUID, postUUID string) (PostCreated, error) {
+	query := `UPDATE post AS p SET published_at = CURRENT_TIMESTAMP
+    WHERE p.uuid = $2 AND p.app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND p.published_at IS NULL AND p.deleted_at IS NULL
+    ` + publishedReturning
+
+	rows, err := r.db.Query(query, userUUID, postUUID)
+	if err != nil {
+		return PostCreated{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return PostCreated{}, err
+		}
+		return PostCreated{}, ErrPostNotFound
+	}
+	return scanPublished(rows)
+}
+
+// PublishDue publishes up to limit scheduled posts whose time has come. The
+// rows are claimed with FOR UPDATE SKIP LOCKED and published in the same
+// statement, so when several instances run the scheduler each post is
+// returned to exactly one of them.
+func (r *PostRepository) PublishDue(limit int) ([]PostCreated, error) {
+	query := `UPDATE post AS p SET published_at = CURRENT_TIMESTAMP
+    WHERE p.id IN (
+      SELECT id FROM post
+      WHERE published_at IS NULL AND publish_at <= CURRENT_TIMESTAMP AND deleted_at IS NULL
+      ORDER BY publish_at
+      LIMIT $1
+      FOR UPDATE SKIP LOCKED
+    ) AND p.published_at IS NULL
+    ` + publishedReturning
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []PostCreated
+	for rows.Next() {
+		p, err := scanPublished(rows)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// UpdatePost replaces the content of the author's published post and keeps
+// the previous version as a revision, both in one transaction. The row is
+// locked so concurrent edits record every version. Posts published longer
+// than window ago are no longer editable, the cutoff is taken in SQL since
+// published_at is in the database zone.
+func (r *PostRepository) UpdatePost(p PostUpdated, window time.Duration) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	var content string
+	var expired bool
+	err = tx.QueryRow(`SELECT id, content, published_at < CURRENT_TIMESTAMP - make_interval(secs => $3) FROM post
+    WHERE uuid = $1 AND app_user_id = (SELECT id FROM app_user WHERE uuid = $2)
+    AND deleted_at IS NULL AND hidden_at IS NULL AND published_at IS NOT NULL
+    AND (original_post_id IS NULL OR is_quote)
+    FOR UPDATE`, p.UUID, p.UserUUID, window.Seconds()).Scan(&id, &content, &expired)
+	if err == sql.ErrNoRows {
+		return ErrPostNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if expired {
+		return ErrEditWindowClosed
+	}
+	if content == p.Content {
+		return nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO post_revision (post_id, content, created_at)
+    SELECT id, content, COALESCE(edited_at, published_at) FROM post WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE post SET content = $2, edited_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+    WHERE id = $1`, id, p.Content)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM post_hashtag WHERE post_id = $1", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM post_mention WHERE post_id = $1", id); err != nil {
+		return err
+	}
+	if err := insertHashtags(tx, id, p.Hashtags); err != nil {
+		return err
+	}
+	if err := insertMentions(tx, id, p.Mentions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetRevisions lists the replaced versions of a post, newest first. Callers
+// check the post is visible first.
+func (r *PostRepository) GetRevisions(postUUID string) ([]PostRevision, error) {
+	query := `
+  SELECT r.content, r.created_at
+  FROM post_revision AS r
+  JOIN post AS p ON p.id = r.post_id
+  WHERE p.uuid = $1
+  ORDER BY r.id DESC`
+
+	rows, err := r.db.Query(query, postUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []PostRevision{}
+	for rows.Next() {
+		var rev PostRevision
+		if err := rows.Scan(&rev.Content, &rev.CreatedAt); err != nil {
+			return revisions, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// Vote records the viewer's choice on the poll of a post they can read. The
+// poll row is locked so a vote cannot slip in after it closes.
+func (r *PostRepository) Vote(userUUID, postUUID string, position int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var pollID int64
+	var closed bool
+	err = tx.QueryRow(`
+  SELECT pl.id, pl.closes_at <= (now() AT TIME ZONE 'UTC')
+  FROM poll AS pl
+  JOIN post AS p ON p.id = pl.post_id
+  JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE p.uuid = $2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL`+
+		visibilityFilter+blockFilter+`
+  FOR UPDATE OF pl`, userUUID, postUUID).Scan(&pollID, &closed)
+	if err == sql.ErrNoRows {
+		return ErrPollNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrPollClosed
+	}
+
+	var id int64
+	err = tx.QueryRow(`INSERT INTO poll_vote (poll_id, option_id, app_user_id)
+    SELECT $1, o.id, (SELECT id FROM app_user WHERE uuid = $3)
+    FROM poll_option AS o WHERE o.poll_id = $1 AND o.position = $2
+    ON CONFLICT (poll_id, app_user_id) DO NOTHING
+    RETURNING id`, pollID, position, userUUID).Scan(&id)
+	if err == sql.ErrNoRows {
+		// no row either because the option does not exist or the user voted
+		var exists bool
+		err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM poll_option WHERE poll_id = $1 AND position = $2)`,
+			pollID, position).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrInvalidOption
+		}
+		return ErrAlreadyVoted
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -1,15 +1,22 @@
 package post
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+var postColumnNames = []string{"uuid", "content", "num_like", "visibility_type_id", "uuid", "username", "updated_at", "mentions",
+	"is_repost", "is_quote", "num_repost", "num_quote", "reposted_by_me", "original", "reactions", "my_reaction", "bookmarked_by_me", "edited_at", "poll", "link_preview"}
+
 func TestCreatePost(t *testing.T) {
 	testTable := []struct {
 		title   string
@@ -33,12 +40,15 @@ func TestCreatePost(t *testing.T) {
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
 			db, mock, _ := sqlmock.New()
+			mock.ExpectBegin()
 			mock.ExpectQuery("INSERT INTO post").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectCommit()
 
 			postRepo := NewPostRepository(db)
 			id, err := postRepo.CreatePost(v.post)
 			assert.Equalf(t, v.wantErr, err, "Want %v but got %v", v.wantErr, err)
 			assert.Equalf(t, v.wantId, id, "Want %v but got %v", v.wantId, id)
+			assert.Nil(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -62,6 +72,7 @@ func TestGetPostByUserUUID(t *testing.T) {
 					UserUUID:         util.Ptr("f6630558-b800-48ff-9a09-5863d6055154"),
 					Username:         util.Ptr("ronaldo"),
 					UpdateAt:         time.Now(),
+					Reactions:        ReactionCounts{},
 				},
 			},
 			nil,
@@ -71,11 +82,12 @@ func TestGetPostByUserUUID(t *testing.T) {
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
 			db, mock, _ := sqlmock.New()
-			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"uuid", "content", "num_like", "visibility_type_id", "uuid", "username", "updated_at"}).
-				AddRow(v.wantPost[0].UUID, v.wantPost[0].Content, v.wantPost[0].NumLike, v.wantPost[0].VisibilityTypeId, v.wantPost[0].UserUUID, v.wantPost[0].Username, v.wantPost[0].UpdateAt))
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(postColumnNames).
+				AddRow(v.wantPost[0].UUID, v.wantPost[0].Content, v.wantPost[0].NumLike, v.wantPost[0].VisibilityTypeId, v.wantPost[0].UserUUID, v.wantPost[0].Username, v.wantPost[0].UpdateAt, nil,
+					false, false, 0, 0, false, nil, nil, nil, false, nil, nil, nil))
 
 			postRepo := NewPostRepository(db)
-			posts, err := postRepo.GetPostsByUserUUID(v.userUUID)
+			posts, err := postRepo.GetPostsByUserUUID(v.userUUID, "7a053eee-a70d-442c-81ba-c36d72d3f87b")
 			assert.Equalf(t, v.wantErr, err, "unexpected error: %v", err)
 			assert.Equalf(t, v.wantPost, posts, "Want %v but got %v", v.wantPost, posts)
 		})
@@ -103,7 +115,7 @@ func TestGetPostByUserUUID_SQL_ERR(t *testing.T) {
 			mock.ExpectQuery("SELECT").WillReturnError(errors.New("some errors"))
 
 			postRepo := NewPostRepository(db)
-			posts, err := postRepo.GetPostsByUserUUID(v.userUUID)
+			posts, err := postRepo.GetPostsByUserUUID(v.userUUID, "7a053eee-a70d-442c-81ba-c36d72d3f87b")
 			assert.Equalf(t, v.wantErr, err, "unexpected error: %v", err)
 			assert.Equalf(t, v.wantPost, posts, "Want %v but got %v", v.wantPost, posts)
 		})
@@ -129,6 +141,7 @@ func TestGetPosts(t *testing.T) {
 					UserUUID:         util.Ptr("f6630558-b800-48ff-9a09-5863d6055154"),
 					Username:         util.Ptr("ronaldo"),
 					UpdateAt:         time.Now(),
+					Reactions:        ReactionCounts{},
 				},
 			},
 			nil,
@@ -138,13 +151,698 @@ func TestGetPosts(t *testing.T) {
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
 			db, mock, _ := sqlmock.New()
-			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"uuid", "content", "num_like", "visibility_type_id", "uuid", "username", "updated_at"}).
-				AddRow(v.wantPost[0].UUID, v.wantPost[0].Content, v.wantPost[0].NumLike, v.wantPost[0].VisibilityTypeId, v.wantPost[0].UserUUID, v.wantPost[0].Username, v.wantPost[0].UpdateAt))
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(postColumnNames).
+				AddRow(v.wantPost[0].UUID, v.wantPost[0].Content, v.wantPost[0].NumLike, v.wantPost[0].VisibilityTypeId, v.wantPost[0].UserUUID, v.wantPost[0].Username, v.wantPost[0].UpdateAt, nil,
+					false, false, 0, 0, false, nil, nil, nil, false, nil, nil, nil))
 
 			postRepo := NewPostRepository(db)
-			posts, err := postRepo.GetPosts()
+			posts, err := postRepo.GetPosts("7a053eee-a70d-442c-81ba-c36d72d3f87b")
 			assert.Equalf(t, v.wantErr, err, "unexpected error: %v", err)
 			assert.Equalf(t, v.wantPost, posts, "Want %v but got %v", v.wantPost, posts)
 		})
 	}
 }
+
+func TestGetPosts_FilterVisibility(t *testing.T) {
+	viewerUUID := "7a053eee-a70d-442c-81ba-c36d72d3f87b"
+	visible := fmt.Sprintf(`p.published_at IS NOT NULL AND \( u.uuid = \$1 OR p.visibility_type_id = %d OR \(p.visibility_type_id = %d AND EXISTS \( SELECT 1 FROM follows AS f`,
+		VisibilityPublic, VisibilityFollowers)
+	testTable := []struct {
+		title string
+		get   func(*PostRepository) ([]PostResponse, error)
+		args  []driver.Value
+	}{
+		{"should filter feed", func(r *PostRepository) ([]PostResponse, error) { return r.GetPosts(viewerUUID) }, []driver.Value{viewerUUID}},
+		{"should filter profile", func(r *PostRepository) ([]PostResponse, error) {
+			return r.GetPostsByUserUUID("f6630558-b800-48ff-9a09-5863d6055154", viewerUUID)
+		}, []driver.Value{viewerUUID, "f6630558-b800-48ff-9a09-5863d6055154"}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery(visible).WithArgs(v.args...).WillReturnRows(sqlmock.NewRows(postColumnNames))
+
+			_, err := v.get(NewPostRepository(db))
+			assert.Nilf(t, err, "unexpected error: %v", err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetPosts_FilterBlockedAndMuted(t *testing.T) {
+	viewerUUID := "7a053eee-a70d-442c-81ba-c36d72d3f87b"
+	db, mock, _ := sqlmock.New()
+	mock.ExpectQuery("FROM blocks AS b(.|\n)*FROM mutes AS m").WithArgs(viewerUUID).
+		WillReturnRows(sqlmock.NewRows(postColumnNames))
+
+	postRepo := NewPostRepository(db)
+	_, err := postRepo.GetPosts(viewerUUID)
+	assert.Nilf(t, err, "unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPostByUserUUID_FilterBlocked(t *testing.T) {
+	viewerUUID := "7a053eee-a70d-442c-81ba-c36d72d3f87b"
+	userUUID := "f6630558-b800-48ff-9a09-5863d6055154"
+	db, mock, _ := sqlmock.New()
+	mock.ExpectQuery("FROM blocks AS b").WithArgs(viewerUUID, userUUID).
+		WillReturnRows(sqlmock.NewRows(postColumnNames))
+
+	postRepo := NewPostRepository(db)
+	_, err := postRepo.GetPostsByUserUUID(userUUID, viewerUUID)
+	assert.Nilf(t, err, "unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePost_WithHashtags(t *testing.T) {
+	p := PostCreated{
+		UUID:             "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		Content:          "Hello #go #web",
+		UserUUID:         "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		VisibilityTypeId: 1,
+		Hashtags:         []string{"go", "web"},
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO post").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO hashtag").WithArgs(pq.Array(p.Hashtags)).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO post_hashtag").WithArgs(7, pq.Array(p.Hashtags)).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	postRepo := NewPostRepository(db)
+	id, err := postRepo.CreatePost(p)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, int64(7), id, "Want %v but got %v", 7, id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePost_HashtagErrorRollback(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO post").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO hashtag").WillReturnError(errors.New("db err"))
+	mock.ExpectRollback()
+
+	postRepo := NewPostRepository(db)
+	_, err := postRepo.CreatePost(PostCreated{Hashtags: []string{"go"}})
+	assert.NotNil(t, err, "expected error")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPostsByHashtag(t *testing.T) {
+	updateAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("FROM post_hashtag").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "golang", 20, 0).
+		WillReturnRows(sqlmock.NewRows(postColumnNames).
+			AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "#golang @ong", 0, 1, "f6630558-b800-48ff-9a09-5863d6055154", "ong", updateAt,
+				`[{"user_uuid":"f6630558-b800-48ff-9a09-5863d6055154","username":"ong","offset":8,"length":4}]`,
+				false, false, 0, 0, false, nil, nil, nil, false, nil, nil, nil))
+
+	postRepo := NewPostRepository(db)
+	posts, err := postRepo.GetPostsByHashtag("golang", "e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0)
+	want := []PostResponse{{
+		UUID:             util.Ptr("f307d2db-d2ea-4ec9-8d31-27b7443d7c72"),
+		Content:          util.Ptr("#golang @ong"),
+		Username:         util.Ptr("ong"),
+		UserUUID:         util.Ptr("f6630558-b800-48ff-9a09-5863d6055154"),
+		VisibilityTypeId: 1,
+		Reactions:        ReactionCounts{},
+		UpdateAt:         updateAt,
+		Mentions:         Mentions{{"f6630558-b800-48ff-9a09-5863d6055154", "ong", 8, 4}},
+	}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, posts, "Want %v but got %v", want, posts)
+}
+
+func TestGetTrendingHashtags(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery(`p.published_at >= CURRENT_TIMESTAMP - make_interval\(secs => \$1\)(.+)GROUP BY h.name`).
+		WithArgs(float64(86400), VisibilityPublic, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "post_count"}).AddRow("golang", 3).AddRow("web", 1))
+
+	postRepo := NewPostRepository(db)
+	tags, err := postRepo.GetTrendingHashtags(24*time.Hour, 10)
+	want := []TrendingTag{{"golang", 3}, {"web", 1}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, tags, "Want %v but got %v", want, tags)
+}
+
+func TestIsVisibleTo(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM post AS p(.+)visibility_type_id(.+)FROM blocks AS b`).
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	visible, err := NewPostRepository(db).IsVisibleTo("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.False(t, visible)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePost_WithMentions(t *testing.T) {
+	p := PostCreated{
+		UUID:     "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		Content:  "hi @ong",
+		UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		Mentions: Mentions{{"f6630558-b800-48ff-9a09-5863d6055154", "ong", 3, 4}},
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO post").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO post_mention").
+		WithArgs(7, pq.Array([]string{"f6630558-b800-48ff-9a09-5863d6055154"}), pq.Array([]int64{3}), pq.Array([]int64{4})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	postRepo := NewPostRepository(db)
+	_, err := postRepo.CreatePost(p)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMentionsScan(t *testing.T) {
+	testTable := []struct {
+		title string
+		src   interface{}
+		want  Mentions
+	}{
+		{"should scan null", nil, nil},
+		{"should scan empty array", []byte("[]"), nil},
+		{"should scan mentions", []byte(`[{"user_uuid":"a","username":"ong","offset":1,"length":4}]`), Mentions{{"a", "ong", 1, 4}}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			var m Mentions
+			err := m.Scan(v.src)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, m, "Want %v but got %v", v.want, m)
+		})
+	}
+}
+
+func TestGetPostForViewer(t *testing.T) {
+	updateAt := time.Now()
+	original := `{"uuid":"0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11","content":"original","num_like":0,"visibility_type_id":1,` +
+		`"user_uuid":"ea151663-aad6-45b2-808b-e3f160956612","username":"bob","update_at":"2026-10-19T17:00:00+07:00"}`
+	testTable := []struct {
+		title        string
+		rows         *sqlmock.Rows
+		wantOriginal bool
+		wantErr      error
+	}{
+		{"should return quote with original", sqlmock.NewRows(postColumnNames).
+			AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "so true", 0, 1, "f6630558-b800-48ff-9a09-5863d6055154", "ong", updateAt, nil,
+				false, true, 2, 1, true, original, `{"like":2,"love":1}`, "love", true, nil, nil, nil), true, nil},
+		{"should not found", sqlmock.NewRows(postColumnNames), false, ErrPostNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("SELECT (.+) FROM post AS p").
+				WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+				WillReturnRows(v.rows)
+
+			postRepo := NewPostRepository(db)
+			post, err := postRepo.GetPostForViewer("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			if !v.wantOriginal {
+				return
+			}
+			assert.True(t, post.IsQuote)
+			assert.Equal(t, int64(2), post.NumRepost)
+			assert.Equal(t, int64(1), post.NumQuote)
+			assert.True(t, post.RepostedByMe)
+			assert.Equal(t, ReactionCounts{"like": 2, "love": 1}, post.Reactions)
+			assert.Equal(t, "love", *post.MyReaction)
+			assert.True(t, post.BookmarkedByMe)
+			assert.Equal(t, "original", *post.Original.Content)
+			assert.Equal(t, "bob", *post.Original.Username)
+		})
+	}
+}
+
+func TestGetPosts_FilterInvisibleReposts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery(`p.original_post_id IS NULL OR p.is_quote OR EXISTS`).
+		WillReturnRows(sqlmock.NewRows(postColumnNames))
+
+	postRepo := NewPostRepository(db)
+	_, err := postRepo.GetPosts("7a053eee-a70d-442c-81ba-c36d72d3f87b")
+	assert.Nilf(t, err, "unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRepost(t *testing.T) {
+	testTable := []struct {
+		title    string
+		queryErr error
+		wantErr  error
+	}{
+		{"should repost", nil, nil},
+		{"should duplicate repost", sql.ErrNoRows, ErrAlreadyReposted},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			q := mock.ExpectQuery("INSERT INTO post (.+) ON CONFLICT").
+				WithArgs("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", VisibilityPublic, "e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+			if v.queryErr != nil {
+				q.WillReturnError(v.queryErr)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+			}
+
+			postRepo := NewPostRepository(db)
+			_, err := postRepo.Repost("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestUnrepost(t *testing.T) {
+	testTable := []struct {
+		title    string
+		affected int64
+		wantErr  error
+	}{
+		{"should remove repost", 1, nil},
+		{"should not reposted", 0, ErrNotReposted},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectExec("UPDATE post SET deleted_at").
+				WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+				WillReturnResult(sqlmock.NewResult(0, v.affected))
+
+			postRepo := NewPostRepository(db)
+			err := postRepo.Unrepost("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestCreatePost_Schedule(t *testing.T) {
+	publishAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.FixedZone("ICT", 7*60*60))
+	testTable := []struct {
+		title       string
+		post        PostCreated
+		wantPublish any
+		wantNow     bool
+	}{
+		{"should publish now", PostCreated{}, nil, true},
+		{"should keep draft", PostCreated{Draft: true}, nil, false},
+		{"should schedule", PostCreated{PublishAt: &publishAt}, publishAt, false},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO post (.+) published_at(.+)\$7::timestamptz`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, v.wantPublish, v.wantNow).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectCommit()
+
+			postRepo := NewPostRepository(db)
+			_, err := postRepo.CreatePost(v.post)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetDrafts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	now := time.Now()
+	mock.ExpectQuery("FROM post AS p(.|\n)*published_at IS NULL").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245").
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "content", "visibility_type_id", "quote", "publish_at", "updated_at"}).
+			AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "soon", 1, nil, now, now).
+			AddRow("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "later", 2, nil, nil, now))
+
+	postRepo := NewPostRepository(db)
+	drafts, err := postRepo.GetDrafts("e936e164-52fa-4fd5-b0e0-597c2f270245")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []Draft{
+		{UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", Content: "soon", VisibilityTypeId: 1, PublishAt: &now, UpdateAt: now},
+		{UUID: "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", Content: "later", VisibilityTypeId: 2, UpdateAt: now},
+	}, drafts)
+}
+
+func TestUpdateDraft(t *testing.T) {
+	d := DraftUpdated{
+		UUID:             "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		UserUUID:         "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		Content:          "edited #go",
+		VisibilityTypeId: 1,
+		Hashtags:         []string{"go"},
+	}
+
+	t.Run("should replace content and hashtags", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE post SET content").
+			WithArgs(d.UUID, d.UserUUID, d.Content, d.VisibilityTypeId, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "closes_at"}).AddRow(7, nil))
+		mock.ExpectExec("DELETE FROM post_hashtag").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM post_mention").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO hashtag").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO post_hashtag").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := NewPostRepository(db).UpdateDraft(d)
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject poll closing before publish", func(t *testing.T) {
+		publishAt := time.Now().Add(2 * time.Hour)
+		scheduled := d
+		scheduled.PublishAt = &publishAt
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE post SET content").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "closes_at"}).AddRow(7, time.Now().Add(time.Hour)))
+		mock.ExpectRollback()
+
+		err := NewPostRepository(db).UpdateDraft(scheduled)
+		assert.Equal(t, ErrInvalidPoll, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not found published or foreign post", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE post SET content").WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := NewPostRepository(db).UpdateDraft(d)
+		assert.Equal(t, ErrPostNotFound, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+var publishedColumnNames = []string{"uuid", "user_uuid", "visibility_type_id", "mentions"}
+
+func TestPublishDraft(t *testing.T) {
+	t.Run("should publish", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectQuery("UPDATE post AS p SET published_at").
+			WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+			WillReturnRows(sqlmock.NewRows(publishedColumnNames).
+				AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "e936e164-52fa-4fd5-b0e0-597c2f270245", 1, "{ea151663-aad6-45b2-808b-e3f160956612}"))
+
+		p, err := NewPostRepository(db).PublishDraft("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Equal(t, PostCreated{
+			UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: 1,
+			Mentions: Mentions{{UserUUID: "ea151663-aad6-45b2-808b-e3f160956612"}},
+		}, p)
+	})
+
+	t.Run("should not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectQuery("UPDATE post AS p SET published_at").WillReturnRows(sqlmock.NewRows(publishedColumnNames))
+
+		_, err := NewPostRepository(db).PublishDraft("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+		assert.Equal(t, ErrPostNotFound, err)
+	})
+}
+
+func TestPublishDue(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("UPDATE post AS p SET published_at(.|\n)*publish_at <= CURRENT_TIMESTAMP(.|\n)*FOR UPDATE SKIP LOCKED").
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows(publishedColumnNames).
+			AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "e936e164-52fa-4fd5-b0e0-597c2f270245", 1, nil).
+			AddRow("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "e936e164-52fa-4fd5-b0e0-597c2f270245", 3, nil))
+
+	posts, err := NewPostRepository(db).PublishDue(100)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []PostCreated{
+		{UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: 1},
+		{UUID: "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: 3},
+	}, posts)
+}
+
+func TestUpdatePost(t *testing.T) {
+	p := PostUpdated{
+		UUID:     "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		Content:  "fixed",
+	}
+	window := time.Hour
+	lockRows := func(content string, expired bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "content", "expired"}).AddRow(7, content, expired)
+	}
+
+	t.Run("should keep revision and update", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, content(.|\n)*FOR UPDATE").
+			WithArgs(p.UUID, p.UserUUID, float64(3600)).WillReturnRows(lockRows("typo", false))
+		mock.ExpectExec("INSERT INTO post_revision").WithArgs(7).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE post SET content").WithArgs(7, "fixed").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM post_hashtag").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM post_mention").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := NewPostRepository(db).UpdatePost(p, window)
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should skip unchanged content", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, content").WillReturnRows(lockRows("fixed", false))
+		mock.ExpectRollback()
+
+		err := NewPostRepository(db).UpdatePost(p, window)
+		assert.Nilf(t, err, "Unexpected error: %v", err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject after edit window", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id, content, published_at < CURRENT_TIMESTAMP - make_interval\(secs => \$3\)`).
+			WithArgs(p.UUID, p.UserUUID, float64(3600)).WillReturnRows(lockRows("typo", true))
+		mock.ExpectRollback()
+
+		err := NewPostRepository(db).UpdatePost(p, window)
+		assert.Equal(t, ErrEditWindowClosed, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, content").WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := NewPostRepository(db).UpdatePost(p, window)
+		assert.Equal(t, ErrPostNotFound, err)
+	})
+}
+
+// TestUpdatePost_EditWindowBoundary checks the window is passed to SQL as
+// seconds and compared strictly, a post published exactly window ago is
+// still editable.
+func TestUpdatePost_EditWindowBoundary(t *testing.T) {
+	p := PostUpdated{UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", Content: "fixed"}
+	testTable := []struct {
+		title    string
+		window   time.Duration
+		wantSecs float64
+	}{
+		{"should pass default window", DefaultEditWindow, 3600},
+		{"should pass minutes", 15 * time.Minute, 900},
+		{"should keep fraction", 90*time.Second + 500*time.Millisecond, 90.5},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery(`published_at < CURRENT_TIMESTAMP - make_interval\(secs => \$3\) FROM post`).
+				WithArgs(p.UUID, p.UserUUID, v.wantSecs).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "content", "expired"}).AddRow(7, "fixed", false))
+			mock.ExpectRollback()
+
+			err := NewPostRepository(db).UpdatePost(p, v.window)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetRevisions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	writtenAt := time.Now()
+	mock.ExpectQuery("FROM post_revision").
+		WithArgs("f307d2db-d2ea-4ec9-8d31-27b7443d7c72").
+		WillReturnRows(sqlmock.NewRows([]string{"content", "created_at"}).AddRow("typo", writtenAt))
+
+	revisions, err := NewPostRepository(db).GetRevisions("f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []PostRevision{{Content: "typo", CreatedAt: writtenAt}}, revisions)
+}
+
+func TestScanPost_Edited(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	editedAt := time.Now()
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(postColumnNames).
+		AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "fixed", 0, 1, "f6630558-b800-48ff-9a09-5863d6055154", "ong", editedAt, nil,
+			false, false, 0, 0, false, nil, nil, nil, false, editedAt, nil, nil))
+
+	post, err := NewPostRepository(db).GetPostForViewer("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, post.Edited)
+	assert.Equal(t, &editedAt, post.EditedAt)
+}
+
+func TestCreatePost_Poll(t *testing.T) {
+	closesAt := time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO post").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery("INSERT INTO poll").WithArgs(7, closesAt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec("INSERT INTO poll_option").WithArgs(3, pq.Array([]string{"tabs", "spaces"})).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	_, err := NewPostRepository(db).CreatePost(PostCreated{Poll: &PollCreated{[]string{"tabs", "spaces"}, closesAt}})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestScanPost_Poll(t *testing.T) {
+	testTable := []struct {
+		title    string
+		poll     string
+		wantPoll *Poll
+	}{
+		{
+			"should hide tallies before voting",
+			`{"options":[{"position":1,"text":"tabs","votes":null},{"position":2,"text":"spaces","votes":null}],
+			"closes_at":"2026-10-21T00:00:00+00:00","closed":false,"my_vote":null,"total_votes":null}`,
+			&Poll{
+				Options:  []PollOption{{Position: 1, Text: "tabs"}, {Position: 2, Text: "spaces"}},
+				ClosesAt: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"should show tallies and choice after voting",
+			`{"options":[{"position":1,"text":"tabs","votes":3},{"position":2,"text":"spaces","votes":1}],
+			"closes_at":"2026-10-21T00:00:00+00:00","closed":false,"my_vote":2,"total_votes":4}`,
+			&Poll{
+				Options:    []PollOption{{1, "tabs", util.Ptr(int64(3))}, {2, "spaces", util.Ptr(int64(1))}},
+				ClosesAt:   time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+				MyVote:     util.Ptr(2),
+				TotalVotes: util.Ptr(int64(4)),
+			},
+		},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("FROM poll AS pl").WillReturnRows(sqlmock.NewRows(postColumnNames).
+				AddRow("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "tabs or spaces?", 0, 1, "f6630558-b800-48ff-9a09-5863d6055154", "ong", time.Now(), nil,
+					false, false, 0, 0, false, nil, nil, nil, false, nil, v.poll, nil))
+
+			post, err := NewPostRepository(db).GetPostForViewer("f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantPoll.Options, post.Poll.Options)
+			assert.True(t, v.wantPoll.ClosesAt.Equal(post.Poll.ClosesAt))
+			assert.Equal(t, v.wantPoll.MyVote, post.Poll.MyVote)
+			assert.Equal(t, v.wantPoll.TotalVotes, post.Poll.TotalVotes)
+		})
+	}
+}
+
+func TestVote(t *testing.T) {
+	userUUID, postUUID := "e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72"
+	pollRows := func(closed bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "closed"}).AddRow(3, closed)
+	}
+	testTable := []struct {
+		title   string
+		setup   func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{"should vote", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM poll AS pl(.|\n)*FOR UPDATE OF pl").WithArgs(userUUID, postUUID).WillReturnRows(pollRows(false))
+			mock.ExpectQuery("INSERT INTO poll_vote").WithArgs(3, 2, userUUID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectCommit()
+		}, nil},
+		{"should not found", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM poll AS pl").WillReturnError(sql.ErrNoRows)
+			mock.ExpectRollback()
+		}, ErrPollNotFound},
+		{"should reject closed poll", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM poll AS pl").WillReturnRows(pollRows(true))
+			mock.ExpectRollback()
+		}, ErrPollClosed},
+		{"should reject missing option", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM poll AS pl").WillReturnRows(pollRows(false))
+			mock.ExpectQuery("INSERT INTO poll_vote").WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("SELECT EXISTS").WithArgs(3, 2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			mock.ExpectRollback()
+		}, ErrInvalidOption},
+		{"should reject second vote", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM poll AS pl").WillReturnRows(pollRows(false))
+			mock.ExpectQuery("INSERT INTO poll_vote").WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("SELECT EXISTS").WithArgs(3, 2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			mock.ExpectRollback()
+		}, ErrAlreadyVoted},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			v.setup(mock)
+
+			err := NewPostRepository(db).Vote(userUUID, postUUID, 2)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
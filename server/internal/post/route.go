@@ -3,20 +3,38 @@ package post
 import (
 	"net/http"
 
-	"github.com/dsypasit/social-clone/server/internal/auth"
-	"github.com/dsypasit/social-clone/server/internal/middleware"
 	"github.com/gorilla/mux"
 )
 
 type IPostHandler interface {
 	CreatePost(http.ResponseWriter, *http.Request)
 	GetPostsByUserUUID(http.ResponseWriter, *http.Request)
+	GetPostsByHashtag(http.ResponseWriter, *http.Request)
+	GetTrendingHashtags(http.ResponseWriter, *http.Request)
+	Repost(http.ResponseWriter, *http.Request)
+	Unrepost(http.ResponseWriter, *http.Request)
+	GetDrafts(http.ResponseWriter, *http.Request)
+	UpdateDraft(http.ResponseWriter, *http.Request)
+	PublishDraft(http.ResponseWriter, *http.Request)
+	UpdatePost(http.ResponseWriter, *http.Request)
+	GetRevisions(http.ResponseWriter, *http.Request)
+	Vote(http.ResponseWriter, *http.Request)
 }
 
-func RegisterPostRouter(router *mux.Router, postHandler IPostHandler, jwtService *auth.JwtService) {
+func RegisterPostRouter(router *mux.Router, postHandler IPostHandler, authMiddleware mux.MiddlewareFunc) {
 	srouter := router.PathPrefix("/post").Subrouter()
-	srouter.Use(middleware.AuthMiddleware(jwtService))
+	srouter.Use(authMiddleware)
 
 	srouter.HandleFunc("", postHandler.GetPostsByUserUUID).Methods(http.MethodGet)
 	srouter.HandleFunc("", postHandler.CreatePost).Methods(http.MethodPost)
+	srouter.HandleFunc("/hashtag/{tag}", postHandler.GetPostsByHashtag).Methods(http.MethodGet)
+	srouter.HandleFunc("/trending-tags", postHandler.GetTrendingHashtags).Methods(http.MethodGet)
+	srouter.HandleFunc("/drafts", postHandler.GetDrafts).Methods(http.MethodGet)
+	srouter.HandleFunc("/{uuid}/draft", postHandler.UpdateDraft).Methods(http.MethodPut)
+	srouter.HandleFunc("/{uuid}/publish", postHandler.PublishDraft).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}", postHandler.UpdatePost).Methods(http.MethodPut)
+	srouter.HandleFunc("/{uuid}/revisions", postHandler.GetRevisions).Methods(http.MethodGet)
+	srouter.HandleFunc("/{uuid}/poll/vote", postHandler.Vote).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}/repost", postHandler.Repost).Methods(http.MethodPost)
+	srouter.HandleFunc("/{uuid}/repost", postHandler.Unrepost).Methods(http.MethodDelete)
 }
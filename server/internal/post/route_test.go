@@ -7,13 +7,56 @@ import (
 	"testing"
 
 	"github.com/dsypasit/social-clone/server/internal/auth"
+	"github.com/dsypasit/social-clone/server/internal/middleware"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
 type MockHandler struct {
-	createPostCalled     bool
-	getPostsByUUIDCalled bool
+	createPostCalled        bool
+	getPostsByUUIDCalled    bool
+	getPostsByHashtagCalled bool
+	getTrendingCalled       bool
+	repostCalled            bool
+	unrepostCalled          bool
+	getDraftsCalled         bool
+	updateDraftCalled       bool
+	publishDraftCalled      bool
+	updatePostCalled        bool
+	getRevisionsCalled      bool
+	voteCalled              bool
+}
+
+func (m *MockHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	m.voteCalled = true
+}
+
+func (m *MockHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	m.updatePostCalled = true
+}
+
+func (m *MockHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	m.getRevisionsCalled = true
+}
+
+func (m *MockHandler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	m.getDraftsCalled = true
+}
+
+func (m *MockHandler) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	m.updateDraftCalled = true
+}
+
+func (m *MockHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	m.publishDraftCalled = true
+}
+
+func (m *MockHandler) Repost(w http.ResponseWriter, r *http.Request) {
+	m.repostCalled = true
+}
+
+func (m *MockHandler) Unrepost(w http.ResponseWriter, r *http.Request) {
+	m.unrepostCalled = true
 }
 
 func (m *MockHandler) GetPostsByUserUUID(w http.ResponseWriter, r *http.Request) {
@@ -24,11 +67,19 @@ func (m *MockHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	m.createPostCalled = true
 }
 
+func (m *MockHandler) GetPostsByHashtag(w http.ResponseWriter, r *http.Request) {
+	m.getPostsByHashtagCalled = true
+}
+
+func (m *MockHandler) GetTrendingHashtags(w http.ResponseWriter, r *http.Request) {
+	m.getTrendingCalled = true
+}
+
 func TestRoute(t *testing.T) {
 	router := mux.NewRouter()
-	mHandler := MockHandler{false, false}
+	mHandler := MockHandler{}
 	jwtSer := auth.NewJwtService("test")
-	RegisterPostRouter(router, &mHandler, jwtSer)
+	RegisterPostRouter(router, &mHandler, middleware.AuthMiddleware(jwtSer, &MockUserSrv{}))
 
 	token, _ := jwtSer.GenerateToken("1234")
 
@@ -41,4 +92,54 @@ func TestRoute(t *testing.T) {
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	router.ServeHTTP(httptest.NewRecorder(), req)
 	assert.True(t, mHandler.getPostsByUUIDCalled, "get post not called")
+
+	req = httptest.NewRequest(http.MethodGet, "/post/hashtag/golang", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.getPostsByHashtagCalled, "get posts by hashtag not called")
+
+	req = httptest.NewRequest(http.MethodGet, "/post/trending-tags", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.getTrendingCalled, "get trending tags not called")
+
+	req = httptest.NewRequest(http.MethodPost, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72/repost", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.repostCalled, "repost not called")
+
+	req = httptest.NewRequest(http.MethodDelete, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72/repost", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.unrepostCalled, "unrepost not called")
+
+	req = httptest.NewRequest(http.MethodGet, "/post/drafts", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.getDraftsCalled, "get drafts not called")
+
+	req = httptest.NewRequest(http.MethodPut, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72/draft", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.updateDraftCalled, "update draft not called")
+
+	req = httptest.NewRequest(http.MethodPost, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72/publish", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.publishDraftCalled, "publish draft not called")
+
+	req = httptest.NewRequest(http.MethodPut, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.updatePostCalled, "update post not called")
+
+	req = httptest.NewRequest(http.MethodGet, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72/revisions", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.getRevisionsCalled, "get revisions not called")
+
+	req = httptest.NewRequest(http.MethodPost, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72/poll/vote", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, mHandler.voteCalled, "vote not called")
 }
@@ -0,0 +1,53 @@
+package post
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type IPostServiceForScheduler interface {
+	PublishDue(limit int) (int, error)
+}
+
+// Scheduler publishes scheduled posts once their publish_at has passed. Every
+// server instance may run one, claiming posts is safe to race.
+type Scheduler struct {
+	postService IPostServiceForScheduler
+	interval    time.Duration
+	batchSize   int
+}
+
+func NewScheduler(postService IPostServiceForScheduler, interval time.Duration, batchSize int) *Scheduler {
+	return &Scheduler{postService, interval, batchSize}
+}
+
+// Run polls until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue drains the backlog batch by batch, a short batch means nothing
+// else is due right now.
+func (s *Scheduler) publishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := s.postService.PublishDue(s.batchSize)
+		if err != nil {
+			log.Println("failed to publish scheduled posts:", err)
+			return
+		}
+		if n < s.batchSize {
+			return
+		}
+	}
+}
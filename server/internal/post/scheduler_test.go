@@ -0,0 +1,66 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mSchedulerSrv struct {
+	results []int
+	err     error
+	calls   int
+}
+
+func (m *mSchedulerSrv) PublishDue(limit int) (int, error) {
+	m.calls++
+	if m.err != nil {
+		return 0, m.err
+	}
+	if len(m.results) == 0 {
+		return 0, nil
+	}
+	n := m.results[0]
+	m.results = m.results[1:]
+	return n, nil
+}
+
+func TestSchedulerPublishDue(t *testing.T) {
+	testTable := []struct {
+		title     string
+		srv       mSchedulerSrv
+		wantCalls int
+	}{
+		{"should stop on short batch", mSchedulerSrv{results: []int{3}}, 1},
+		{"should drain full batches", mSchedulerSrv{results: []int{10, 10, 4}}, 3},
+		{"should stop on error", mSchedulerSrv{err: errors.New("db err")}, 1},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewScheduler(&v.srv, time.Minute, 10)
+			s.publishDue(context.Background())
+			assert.Equal(t, v.wantCalls, v.srv.calls)
+		})
+	}
+}
+
+func TestSchedulerRun_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := mSchedulerSrv{}
+	done := make(chan struct{})
+	go func() {
+		NewScheduler(&srv, time.Hour, 10).Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+}
@@ -3,30 +3,95 @@ package post
 import (
 	"database/sql"
 	"errors"
+	"log"
+	"time"
 
+	"github.com/dsypasit/social-clone/server/internal/filter"
+	"github.com/dsypasit/social-clone/server/internal/linkpreview"
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/dsypasit/social-clone/server/internal/user"
 	"github.com/google/uuid"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+const (
+	DefaultTrendingWindow = 24 * time.Hour
+	MaxTrendingWindow     = 7 * 24 * time.Hour
+	DefaultTrendingLimit  = 10
+	MaxTrendingLimit      = 50
+
+	DefaultEditWindow = time.Hour
+)
+
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrInvalidHashtag = errors.New("invalid hashtag")
+	ErrInvalidWindow  = errors.New("invalid trending window")
+	ErrNotRepostable  = errors.New("only public posts can be reposted or quoted")
+	ErrInvalidPublish = errors.New("publish_at must be in the future")
+	ErrInvalidPoll    = errors.New("invalid poll")
+)
 
 type IUserServiceForPost interface {
 	GetUserByUUID(s string) (user.User, error)
+	GetUserByUsername(string) (user.User, error)
+}
+
+type INotificationServiceForPost interface {
+	Notify(notification.Event) error
+}
+
+type IPublisherForPost interface {
+	Publish(realtime.Event) error
+}
+
+type IPreviewerForPost interface {
+	Enqueue(postUUID, content string)
+}
+
+type IContentFilterForPost interface {
+	Check(filter.Content) filter.Result
+	Flag(targetType, targetUUID string, res filter.Result) error
 }
 
 type IPostRepository interface {
 	CreatePost(PostCreated) (int64, error)
-	GetPostsByUserUUID(string) ([]PostResponse, error)
-	GetPosts() ([]PostResponse, error)
+	GetPostsByUserUUID(userUUID, viewerUUID string) ([]PostResponse, error)
+	GetPosts(viewerUUID string) ([]PostResponse, error)
+	GetPostsByHashtag(tag, viewerUUID string, limit, offset int) ([]PostResponse, error)
+	GetTrendingHashtags(window time.Duration, limit int) ([]TrendingTag, error)
+	GetPostForViewer(postUUID, viewerUUID string) (PostResponse, error)
+	IsVisibleTo(postUUID, viewerUUID string) (bool, error)
+	Repost(uuid, userUUID, originalUUID string) (int64, error)
+	Unrepost(userUUID, originalUUID string) error
+	GetDrafts(userUUID string) ([]Draft, error)
+	UpdateDraft(DraftUpdated) error
+	PublishDraft(userUUID, postUUID string) (PostCreated, error)
+	PublishDue(limit int) ([]PostCreated, error)
+	UpdatePost(p PostUpdated, window time.Duration) error
+	GetRevisions(postUUID string) ([]PostRevision, error)
+	Vote(userUUID, postUUID string, position int) error
 }
 
 type PostService struct {
-	postRepo    IPostRepository
-	userService IUserServiceForPost
+	postRepo            IPostRepository
+	userService         IUserServiceForPost
+	notificationService INotificationServiceForPost
+	publisher           IPublisherForPost
+	previewer           IPreviewerForPost
+	contentFilter       IContentFilterForPost
+	editWindow          time.Duration
+	contentRule         util.TextRule
 }
 
-func NewPostService(postRepo IPostRepository, userService IUserServiceForPost) *PostService {
-	return &PostService{postRepo, userService}
+// NewPostService takes how long after publishing a post may still be edited
+// and the rule post content is sanitized with.
+func NewPostService(postRepo IPostRepository, userService IUserServiceForPost,
+	notificationService INotificationServiceForPost, publisher IPublisherForPost, previewer IPreviewerForPost,
+	contentFilter IContentFilterForPost, editWindow time.Duration, contentRule util.TextRule,
+) *PostService {
+	return &PostService{postRepo, userService, notificationService, publisher, previewer, contentFilter, editWindow, contentRule}
 }
 
 func (s *PostService) CreatePost(p PostCreated) (int64, error) {
@@ -34,13 +99,292 @@ func (s *PostService) CreatePost(p PostCreated) (int64, error) {
 	if err == user.ErrUserNotFound {
 		return 0, ErrUserNotFound
 	}
+	if p.PublishAt != nil && !p.PublishAt.After(time.Now()) {
+		return 0, ErrInvalidPublish
+	}
+	if p.Content, err = s.contentRule.Sanitize(p.Content); err != nil {
+		return 0, err
+	}
+	screened := s.contentFilter.Check(filter.Content{TargetType: filter.TargetPost, UserUUID: p.UserUUID, Text: p.Content})
+	if screened.Verdict == filter.Reject {
+		return 0, screened.Reason
+	}
+	if p.Poll != nil {
+		if err := validPoll(p); err != nil {
+			return 0, err
+		}
+	}
+	if p.QuotePostUUID != "" {
+		p.QuotePostUUID, err = s.repostTarget(p.QuotePostUUID, p.UserUUID)
+		if err != nil {
+			return 0, err
+		}
+	}
 	p.UUID = uuid.NewString()
-	return s.postRepo.CreatePost(p)
+	p.Hashtags = util.ExtractHashtags(p.Content)
+	p.Mentions, err = s.resolveMentions(p.Content)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.postRepo.CreatePost(p)
+	if err != nil {
+		return 0, err
+	}
+	s.flag(p.UUID, screened)
+	// new posts have no preview to clear, only queue the ones with a link
+	if linkpreview.FirstURL(p.Content) != "" {
+		s.previewer.Enqueue(p.UUID, p.Content)
+	}
+	if p.Publishes() {
+		s.announce(p)
+	}
+	return id, nil
+}
+
+// validPoll sanitizes the options and checks the poll closes after the post goes
+// out, within MaxPollDuration of it.
+func validPoll(p PostCreated) error {
+	if len(p.Poll.Options) < MinPollOptions || len(p.Poll.Options) > MaxPollOptions {
+		return ErrInvalidPoll
+	}
+	rule := util.TextRule{MaxLength: MaxPollOptionLength}
+	seen := map[string]bool{}
+	for i, o := range p.Poll.Options {
+		o, err := rule.Sanitize(o)
+		if err != nil || seen[o] {
+			return ErrInvalidPoll
+		}
+		seen[o] = true
+		p.Poll.Options[i] = o
+	}
+
+	if !pollWindowValid(p.Poll.ClosesAt, p.PublishAt) {
+		return ErrInvalidPoll
+	}
+	return nil
+}
+
+// pollWindowValid reports whether a poll closing at closesAt closes after the
+// post goes out, now when publishAt is nil, and within MaxPollDuration of it.
+func pollWindowValid(closesAt time.Time, publishAt *time.Time) bool {
+	opensAt := time.Now()
+	if publishAt != nil {
+		opensAt = *publishAt
+	}
+	return closesAt.After(opensAt) && closesAt.Sub(opensAt) <= MaxPollDuration
+}
+
+// flag queues a stored post the content filter flagged for moderators, the
+// post stands either way.
+func (s *PostService) flag(postUUID string, screened filter.Result) {
+	if err := s.contentFilter.Flag(filter.TargetPost, postUUID, screened); err != nil {
+		log.Println("failed to flag post:", err)
+	}
+}
+
+// announce tells mentioned users and the feed about a post once it is
+// published, drafts stay silent until then.
+func (s *PostService) announce(p PostCreated) {
+	s.notifyMentioned(p)
+	s.publishFeedItem(p)
 }
 
-func (s *PostService) GetPostsByUserUUID(userUUID string) ([]PostResponse, error) {
+func (s *PostService) GetDrafts(userUUID string) ([]Draft, error) {
+	return s.postRepo.GetDrafts(userUUID)
+}
+
+func (s *PostService) UpdateDraft(d DraftUpdated) error {
+	if d.PublishAt != nil && !d.PublishAt.After(time.Now()) {
+		return ErrInvalidPublish
+	}
+	var err error
+	if d.Content, err = s.contentRule.Sanitize(d.Content); err != nil {
+		return err
+	}
+	// a draft was never public, rewriting it is screened like a new post
+	screened := s.contentFilter.Check(filter.Content{TargetType: filter.TargetPost, UserUUID: d.UserUUID, Text: d.Content, UUID: d.UUID})
+	if screened.Verdict == filter.Reject {
+		return screened.Reason
+	}
+	d.Hashtags = util.ExtractHashtags(d.Content)
+	d.Mentions, err = s.resolveMentions(d.Content)
+	if err != nil {
+		return err
+	}
+	if err := s.postRepo.UpdateDraft(d); err != nil {
+		return err
+	}
+	s.flag(d.UUID, screened)
+	s.previewer.Enqueue(d.UUID, d.Content)
+	return nil
+}
+
+func (s *PostService) PublishDraft(userUUID, postUUID string) error {
+	p, err := s.postRepo.PublishDraft(userUUID, postUUID)
+	if err != nil {
+		return err
+	}
+	s.announce(p)
+	return nil
+}
+
+// UpdatePost edits a published post within the edit window. Mentions are
+// resolved again but not notified, the post was already announced.
+func (s *PostService) UpdatePost(p PostUpdated) error {
+	var err error
+	if p.Content, err = s.contentRule.Sanitize(p.Content); err != nil {
+		return err
+	}
+	screened := s.contentFilter.Check(filter.Content{TargetType: filter.TargetPost, UserUUID: p.UserUUID, Text: p.Content, Edit: true})
+	if screened.Verdict == filter.Reject {
+		return screened.Reason
+	}
+	p.Hashtags = util.ExtractHashtags(p.Content)
+	p.Mentions, err = s.resolveMentions(p.Content)
+	if err != nil {
+		return err
+	}
+	if err := s.postRepo.UpdatePost(p, s.editWindow); err != nil {
+		return err
+	}
+	s.flag(p.UUID, screened)
+	s.previewer.Enqueue(p.UUID, p.Content)
+	return nil
+}
+
+// GetRevisions lists earlier versions of a post the viewer can read.
+func (s *PostService) GetRevisions(postUUID, viewerUUID string) ([]PostRevision, error) {
+	if _, err := s.postRepo.GetPostForViewer(postUUID, viewerUUID); err != nil {
+		return nil, err
+	}
+	return s.postRepo.GetRevisions(postUUID)
+}
+
+func (s *PostService) Vote(userUUID, postUUID string, position int) error {
+	return s.postRepo.Vote(userUUID, postUUID, position)
+}
+
+// PublishDue publishes one batch of scheduled posts that are due and returns
+// how many went out.
+func (s *PostService) PublishDue(limit int) (int, error) {
+	posts, err := s.postRepo.PublishDue(limit)
+	for _, p := range posts {
+		s.announce(p)
+	}
+	return len(posts), err
+}
+
+// publishFeedItem pushes only public posts and only by reference, clients
+// refetch the post so block and mute filters still apply.
+func (s *PostService) publishFeedItem(p PostCreated) {
+	if p.VisibilityTypeId != VisibilityPublic {
+		return
+	}
+	e, err := realtime.NewEvent(realtime.TypeFeed, "", realtime.FeedItem{PostUUID: p.UUID, UserUUID: p.UserUUID})
+	if err == nil {
+		err = s.publisher.Publish(e)
+	}
+	if err != nil {
+		log.Println("failed to publish feed item:", err)
+	}
+}
+
+// repostTarget resolves the post a repost or quote should point at. Reposting
+// a plain repost targets its original, and only public posts qualify so a
+// repost cannot widen the audience of a restricted post.
+func (s *PostService) repostTarget(postUUID, viewerUUID string) (string, error) {
+	target, err := s.postRepo.GetPostForViewer(postUUID, viewerUUID)
+	if err != nil {
+		return "", err
+	}
+	if target.IsRepost {
+		if target.Original == nil {
+			return "", ErrPostNotFound
+		}
+		target = *target.Original
+	}
+	if target.VisibilityTypeId != VisibilityPublic {
+		return "", ErrNotRepostable
+	}
+	return *target.UUID, nil
+}
+
+func (s *PostService) Repost(userUUID, postUUID string) (string, error) {
+	originalUUID, err := s.repostTarget(postUUID, userUUID)
+	if err != nil {
+		return "", err
+	}
+
+	p := PostCreated{UUID: uuid.NewString(), UserUUID: userUUID, VisibilityTypeId: VisibilityPublic}
+	if _, err := s.postRepo.Repost(p.UUID, userUUID, originalUUID); err != nil {
+		return "", err
+	}
+	s.publishFeedItem(p)
+	return p.UUID, nil
+}
+
+func (s *PostService) Unrepost(userUUID, postUUID string) error {
+	return s.postRepo.Unrepost(userUUID, postUUID)
+}
+
+// resolveMentions keeps only mentions of existing users, unknown names are
+// left as plain text.
+func (s *PostService) resolveMentions(content string) (Mentions, error) {
+	var mentions Mentions
+	resolved := map[string]user.User{}
+	for _, t := range util.ExtractMentions(content) {
+		u, ok := resolved[t.Username]
+		if !ok {
+			var err error
+			u, err = s.userService.GetUserByUsername(t.Username)
+			if err == user.ErrUserNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			resolved[t.Username] = u
+		}
+		mentions = append(mentions, Mention{u.UUID, u.Username, t.Offset, t.Length})
+	}
+	return mentions, nil
+}
+
+// notifyMentioned sends one notification per mentioned user. The post is
+// already stored, so failures are only logged.
+func (s *PostService) notifyMentioned(p PostCreated) {
+	notified := map[string]bool{}
+	for _, m := range p.Mentions {
+		if notified[m.UserUUID] {
+			continue
+		}
+		notified[m.UserUUID] = true
+		// users who cannot open the post are not told about it
+		visible, err := s.postRepo.IsVisibleTo(p.UUID, m.UserUUID)
+		if err != nil {
+			log.Println("failed to check mention visibility:", err)
+			continue
+		}
+		if !visible {
+			continue
+		}
+		err = s.notificationService.Notify(notification.Event{
+			Type:          notification.TypeMention,
+			ActorUUID:     p.UserUUID,
+			RecipientUUID: m.UserUUID,
+			TargetType:    notification.TargetPost,
+			TargetUUID:    p.UUID,
+		})
+		if err != nil {
+			log.Println("failed to notify mention:", err)
+		}
+	}
+}
+
+func (s *PostService) GetPostsByUserUUID(userUUID, viewerUUID string) ([]PostResponse, error) {
 	if userUUID == "" {
-		posts, err := s.postRepo.GetPosts()
+		posts, err := s.postRepo.GetPosts(viewerUUID)
 		if err == sql.ErrNoRows {
 			return nil, ErrNoRows
 		}
@@ -51,17 +395,41 @@ func (s *PostService) GetPostsByUserUUID(userUUID string) ([]PostResponse, error
 	if err == user.ErrUserNotFound {
 		return nil, ErrNoRows
 	}
-	posts, err := s.postRepo.GetPostsByUserUUID(userUUID)
+	posts, err := s.postRepo.GetPostsByUserUUID(userUUID, viewerUUID)
 	if err == sql.ErrNoRows {
 		return nil, ErrNoRows
 	}
 	return posts, err
 }
 
-func (s *PostService) GetPosts() ([]PostResponse, error) {
-	posts, err := s.postRepo.GetPosts()
+func (s *PostService) GetPosts(viewerUUID string) ([]PostResponse, error) {
+	posts, err := s.postRepo.GetPosts(viewerUUID)
 	if err == sql.ErrNoRows {
 		return nil, ErrNoRows
 	}
 	return posts, err
 }
+
+func (s *PostService) GetPostsByHashtag(tag, viewerUUID string, limit, offset int) ([]PostResponse, error) {
+	tag = util.NormalizeHashtag(tag)
+	if !util.IsValidHashtag(tag) {
+		return nil, ErrInvalidHashtag
+	}
+	return s.postRepo.GetPostsByHashtag(tag, viewerUUID, limit, offset)
+}
+
+func (s *PostService) GetTrendingHashtags(window time.Duration, limit int) ([]TrendingTag, error) {
+	if window == 0 {
+		window = DefaultTrendingWindow
+	}
+	if window < 0 || window > MaxTrendingWindow {
+		return nil, ErrInvalidWindow
+	}
+	if limit <= 0 {
+		limit = DefaultTrendingLimit
+	}
+	if limit > MaxTrendingLimit {
+		limit = MaxTrendingLimit
+	}
+	return s.postRepo.GetTrendingHashtags(window, limit)
+}
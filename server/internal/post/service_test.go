@@ -2,68 +2,225 @@ package post
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dsypasit/social-clone/server/internal/filter"
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/realtime"
 	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/dsypasit/social-clone/server/internal/user"
 	"github.com/stretchr/testify/assert"
 )
 
+var testContentRule = util.TextRule{MaxLength: 280, Multiline: true}
+
 type MockUserSrv struct{}
 
 func (m *MockUserSrv) GetUserByUUID(s string) (user.User, error) {
 	return user.User{}, nil
 }
 
+func (m *MockUserSrv) GetUserByUsername(username string) (user.User, error) {
+	users := map[string]user.User{
+		"ong": {UUID: "f6630558-b800-48ff-9a09-5863d6055154", Username: "ong"},
+		"bob": {UUID: "ea151663-aad6-45b2-808b-e3f160956612", Username: "bob"},
+	}
+	if u, ok := users[username]; ok {
+		return u, nil
+	}
+	return user.User{}, user.ErrUserNotFound
+}
+
+type MockNotificationSrv struct {
+	events []notification.Event
+}
+
+func (m *MockNotificationSrv) Notify(e notification.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+type MockPublisher struct {
+	events []realtime.Event
+}
+
+func (m *MockPublisher) Publish(e realtime.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+type MockPreviewer struct {
+	queued []string
+}
+
+func (m *MockPreviewer) Enqueue(postUUID, content string) {
+	m.queued = append(m.queued, content)
+}
+
+type MockFilter struct {
+	result  filter.Result
+	checked []filter.Content
+	flagged []string
+}
+
+func (m *MockFilter) Check(c filter.Content) filter.Result {
+	m.checked = append(m.checked, c)
+	return m.result
+}
+
+func (m *MockFilter) Flag(targetType, targetUUID string, res filter.Result) error {
+	if res.Verdict == filter.Flag {
+		m.flagged = append(m.flagged, targetUUID)
+	}
+	return nil
+}
+
 type MockRepo struct {
-	repoErr error
-	postRes []PostResponse
+	targets   map[string]PostResponse
+	reposted  string
+	repoErr   error
+	postRes   []PostResponse
+	created   PostCreated
+	gotTag    string
+	gotWindow time.Duration
+	gotLimit  int
+	updated   DraftUpdated
+	due       []PostCreated
+	edited    PostUpdated
+	// hiddenFrom lists the viewers IsVisibleTo turns away.
+	hiddenFrom map[string]bool
 }
 
-func (m *MockRepo) CreatePost(PostCreated) (int64, error) {
+func (m *MockRepo) CreatePost(p PostCreated) (int64, error) {
+	m.created = p
 	if m.repoErr != nil {
 		return 0, m.repoErr
 	}
 	return 1, nil
 }
 
-func (m *MockRepo) GetPostsByUserUUID(string) ([]PostResponse, error) {
+func (m *MockRepo) GetPostsByUserUUID(string, string) ([]PostResponse, error) {
 	if m.repoErr != nil {
 		return []PostResponse{}, m.repoErr
 	}
 	return m.postRes, nil
 }
 
-func (m *MockRepo) GetPosts() ([]PostResponse, error) {
+func (m *MockRepo) GetPosts(string) ([]PostResponse, error) {
 	if m.repoErr != nil {
 		return []PostResponse{}, m.repoErr
 	}
 	return m.postRes, nil
 }
 
+func (m *MockRepo) GetPostsByHashtag(tag, _ string, _, _ int) ([]PostResponse, error) {
+	m.gotTag = tag
+	return m.postRes, m.repoErr
+}
+
+func (m *MockRepo) GetTrendingHashtags(window time.Duration, limit int) ([]TrendingTag, error) {
+	m.gotWindow, m.gotLimit = window, limit
+	return []TrendingTag{}, m.repoErr
+}
+
+func (m *MockRepo) GetPostForViewer(postUUID, _ string) (PostResponse, error) {
+	p, ok := m.targets[postUUID]
+	if !ok {
+		return PostResponse{}, ErrPostNotFound
+	}
+	return p, nil
+}
+
+func (m *MockRepo) IsVisibleTo(_, viewerUUID string) (bool, error) {
+	return !m.hiddenFrom[viewerUUID], nil
+}
+
+func (m *MockRepo) Repost(_, _, originalUUID string) (int64, error) {
+	m.reposted = originalUUID
+	return 1, m.repoErr
+}
+
+func (m *MockRepo) Unrepost(string, string) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) GetDrafts(string) ([]Draft, error) {
+	return []Draft{}, m.repoErr
+}
+
+func (m *MockRepo) UpdateDraft(d DraftUpdated) error {
+	m.updated = d
+	return m.repoErr
+}
+
+func (m *MockRepo) PublishDraft(userUUID, postUUID string) (PostCreated, error) {
+	if m.repoErr != nil {
+		return PostCreated{}, m.repoErr
+	}
+	return PostCreated{UUID: postUUID, UserUUID: userUUID, VisibilityTypeId: VisibilityPublic,
+		Mentions: Mentions{{UserUUID: "ea151663-aad6-45b2-808b-e3f160956612"}}}, nil
+}
+
+func (m *MockRepo) UpdatePost(p PostUpdated, window time.Duration) error {
+	m.edited, m.gotWindow = p, window
+	return m.repoErr
+}
+
+func (m *MockRepo) GetRevisions(string) ([]PostRevision, error) {
+	return []PostRevision{{Content: "before"}}, m.repoErr
+}
+
+func (m *MockRepo) Vote(string, string, int) error {
+	return m.repoErr
+}
+
+func (m *MockRepo) PublishDue(limit int) ([]PostCreated, error) {
+	m.gotLimit = limit
+	return m.due, m.repoErr
+}
+
 func TestServiceCreatePost(t *testing.T) {
 	testTable := []struct {
-		title   string
-		input   PostCreated
-		wantId  int64
-		wantErr error
+		title       string
+		input       PostCreated
+		wantId      int64
+		wantContent string
+		wantErr     error
 	}{
 		{"should create success", PostCreated{
 			Content:          "Hello",
 			UserUUID:         "cfaecdf4-2a2a-47fb-a1fa-114b18383feb",
 			VisibilityTypeId: 1,
-		}, 1, nil},
+		}, 1, "Hello", nil},
+		{"should store sanitized content", PostCreated{
+			Content:          " \u202eHel\u200blo\r\nworld\x07 ",
+			UserUUID:         "cfaecdf4-2a2a-47fb-a1fa-114b18383feb",
+			VisibilityTypeId: 1,
+		}, 1, "Hello\nworld", nil},
+		{"should reject blank content", PostCreated{
+			Content:          "\u200b \u200b",
+			UserUUID:         "cfaecdf4-2a2a-47fb-a1fa-114b18383feb",
+			VisibilityTypeId: 1,
+		}, 0, "", util.ErrTextEmpty},
+		{"should reject too long content", PostCreated{
+			Content:          strings.Repeat("a", 281),
+			UserUUID:         "cfaecdf4-2a2a-47fb-a1fa-114b18383feb",
+			VisibilityTypeId: 1,
+		}, 0, "", util.ErrTextTooLong},
 	}
 
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
-			m := MockRepo{nil, nil}
+			m := MockRepo{}
 			mu := MockUserSrv{}
 
-			s := NewPostService(&m, &mu)
+			s := NewPostService(&m, &mu, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
 			id, err := s.CreatePost(v.input)
 			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
 			assert.Equalf(t, v.wantId, id, "Want %v but got %v", v.wantId, id)
+			assert.Equal(t, v.wantContent, m.created.Content)
 		})
 	}
 }
@@ -97,9 +254,9 @@ func TestServiceGetPostByUserUUID(t *testing.T) {
 
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
-			m := MockRepo{v.mErr, v.wantPost}
-			s := NewPostService(&m, &MockUserSrv{})
-			_, err := s.GetPostsByUserUUID(v.input)
+			m := MockRepo{repoErr: v.mErr, postRes: v.wantPost}
+			s := NewPostService(&m, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.GetPostsByUserUUID(v.input, "ea151663-aad6-45b2-808b-e3f160956612")
 			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
 		})
 	}
@@ -134,10 +291,407 @@ func TestServiceGetPost(t *testing.T) {
 
 	for _, v := range testTable {
 		t.Run(v.title, func(t *testing.T) {
-			m := MockRepo{v.mErr, v.wantPost}
-			s := NewPostService(&m, &MockUserSrv{})
-			_, err := s.GetPosts()
+			m := MockRepo{repoErr: v.mErr, postRes: v.wantPost}
+			s := NewPostService(&m, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.GetPosts("ea151663-aad6-45b2-808b-e3f160956612")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceCreatePost_Hashtags(t *testing.T) {
+	mRepo := MockRepo{}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+	_, err := s.CreatePost(PostCreated{Content: "learning #Go with #golang #go", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+
+	want := []string{"go", "golang"}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, mRepo.created.Hashtags, "Want %v but got %v", want, mRepo.created.Hashtags)
+}
+
+func TestServiceGetPostsByHashtag(t *testing.T) {
+	testTable := []struct {
+		title   string
+		tag     string
+		wantTag string
+		wantErr error
+	}{
+		{"should normalize tag", "#GoLang", "golang", nil},
+		{"should reject invalid tag", "go-lang", "", ErrInvalidHashtag},
+		{"should reject numeric tag", "2024", "", ErrInvalidHashtag},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{}
+			s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.GetPostsByHashtag(v.tag, "", 20, 0)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.wantTag, mRepo.gotTag, "Want %v but got %v", v.wantTag, mRepo.gotTag)
+		})
+	}
+}
+
+func TestServiceGetTrendingHashtags(t *testing.T) {
+	testTable := []struct {
+		title      string
+		window     time.Duration
+		limit      int
+		wantWindow time.Duration
+		wantLimit  int
+		wantErr    error
+	}{
+		{"should use defaults", 0, 0, DefaultTrendingWindow, DefaultTrendingLimit, nil},
+		{"should cap limit", time.Hour, 1000, time.Hour, MaxTrendingLimit, nil},
+		{"should reject negative window", -time.Hour, 0, 0, 0, ErrInvalidWindow},
+		{"should reject too large window", MaxTrendingWindow + time.Hour, 0, 0, 0, ErrInvalidWindow},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{}
+			s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.GetTrendingHashtags(v.window, v.limit)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			if v.wantErr != nil {
+				return
+			}
+			assert.Equalf(t, v.wantLimit, mRepo.gotLimit, "Want %v but got %v", v.wantLimit, mRepo.gotLimit)
+			assert.Equalf(t, v.wantWindow, mRepo.gotWindow, "Want %v but got %v", v.wantWindow, mRepo.gotWindow)
+		})
+	}
+}
+
+func TestServiceCreatePost_Mentions(t *testing.T) {
+	mRepo := MockRepo{}
+	mNotification := MockNotificationSrv{}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &mNotification, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+	_, err := s.CreatePost(PostCreated{
+		Content:  "hi @ong and @ghost, @ong @bob",
+		UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+	})
+
+	wantMentions := Mentions{
+		{"f6630558-b800-48ff-9a09-5863d6055154", "ong", 3, 4},
+		{"f6630558-b800-48ff-9a09-5863d6055154", "ong", 20, 4},
+		{"ea151663-aad6-45b2-808b-e3f160956612", "bob", 25, 4},
+	}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, wantMentions, mRepo.created.Mentions, "Want %v but got %v", wantMentions, mRepo.created.Mentions)
+
+	assert.Lenf(t, mNotification.events, 2, "want one notification per mentioned user")
+	for _, e := range mNotification.events {
+		assert.Equal(t, notification.TypeMention, e.Type)
+		assert.Equal(t, notification.TargetPost, e.TargetType)
+		assert.Equal(t, mRepo.created.UUID, e.TargetUUID)
+		assert.Equal(t, "e936e164-52fa-4fd5-b0e0-597c2f270245", e.ActorUUID)
+	}
+}
+
+func TestServiceCreatePost_MentionCannotView(t *testing.T) {
+	mRepo := MockRepo{hiddenFrom: map[string]bool{"ea151663-aad6-45b2-808b-e3f160956612": true}}
+	mNotification := MockNotificationSrv{}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &mNotification, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+	_, err := s.CreatePost(PostCreated{
+		Content:          "for followers @ong @bob",
+		UserUUID:         "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		VisibilityTypeId: VisibilityFollowers,
+	})
+
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Lenf(t, mNotification.events, 1, "a mention who cannot view the post is not notified")
+	assert.Equal(t, "f6630558-b800-48ff-9a09-5863d6055154", mNotification.events[0].RecipientUUID)
+}
+
+func TestServiceCreatePost_PublishFeedItem(t *testing.T) {
+	testTable := []struct {
+		title      string
+		visibility int
+		wantEvents int
+	}{
+		{"should publish public post", VisibilityPublic, 1},
+		{"should not publish followers only post", VisibilityFollowers, 0},
+		{"should not publish private post", VisibilityPrivate, 0},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mPublisher := MockPublisher{}
+			s := NewPostService(&MockRepo{}, &MockUserSrv{}, &MockNotificationSrv{}, &mPublisher, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.CreatePost(PostCreated{Content: "hello", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: v.visibility})
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Lenf(t, mPublisher.events, v.wantEvents, "Want %v events but got %v", v.wantEvents, len(mPublisher.events))
+		})
+	}
+}
+
+func repostTargets() map[string]PostResponse {
+	public := PostResponse{UUID: util.Ptr("f307d2db-d2ea-4ec9-8d31-27b7443d7c72"), VisibilityTypeId: VisibilityPublic}
+	return map[string]PostResponse{
+		"f307d2db-d2ea-4ec9-8d31-27b7443d7c72": public,
+		"0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11": {UUID: util.Ptr("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"), VisibilityTypeId: VisibilityFollowers},
+		"d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21": {UUID: util.Ptr("d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21"), VisibilityTypeId: VisibilityPublic, IsRepost: true, Original: &public},
+	}
+}
+
+func TestServiceRepost(t *testing.T) {
+	testTable := []struct {
+		title        string
+		postUUID     string
+		wantOriginal string
+		wantEvents   int
+		wantErr      error
+	}{
+		{"should repost public post", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", 1, nil},
+		{"should repost original of a repost", "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", 1, nil},
+		{"should reject followers only post", "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "", 0, ErrNotRepostable},
+		{"should not found invisible post", "5f2d7a55-0a3c-4e0e-8f0f-4b6c0f3e2a10", "", 0, ErrPostNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{targets: repostTargets()}
+			mPublisher := MockPublisher{}
+			s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &mPublisher, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.Repost("e936e164-52fa-4fd5-b0e0-597c2f270245", v.postUUID)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantOriginal, mRepo.reposted)
+			assert.Len(t, mPublisher.events, v.wantEvents)
+		})
+	}
+}
+
+func TestServiceCreatePost_Quote(t *testing.T) {
+	mRepo := MockRepo{targets: repostTargets()}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+	_, err := s.CreatePost(PostCreated{
+		Content: "so true", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: VisibilityPublic,
+		QuotePostUUID: "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21",
+	})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", mRepo.created.QuotePostUUID)
+
+	_, err = s.CreatePost(PostCreated{
+		Content: "so true", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: VisibilityPublic,
+		QuotePostUUID: "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11",
+	})
+	assert.Equal(t, ErrNotRepostable, err)
+}
+
+func TestServiceCreatePost_Unpublished(t *testing.T) {
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	testTable := []struct {
+		title   string
+		input   PostCreated
+		wantErr error
+	}{
+		{"should keep draft silent", PostCreated{Content: "hi @bob", VisibilityTypeId: VisibilityPublic, Draft: true}, nil},
+		{"should keep scheduled post silent", PostCreated{Content: "hi @bob", VisibilityTypeId: VisibilityPublic, PublishAt: &future}, nil},
+		{"should reject past publish_at", PostCreated{Content: "hi @bob", VisibilityTypeId: VisibilityPublic, PublishAt: &past}, ErrInvalidPublish},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mNotification, mPublisher := MockNotificationSrv{}, MockPublisher{}
+			s := NewPostService(&MockRepo{}, &MockUserSrv{}, &mNotification, &mPublisher, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			_, err := s.CreatePost(v.input)
 			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Empty(t, mNotification.events)
+			assert.Empty(t, mPublisher.events)
 		})
 	}
 }
+
+func TestServiceUpdateDraft(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	mRepo := MockRepo{}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+
+	err := s.UpdateDraft(DraftUpdated{Content: "hi @bob #go", VisibilityTypeId: VisibilityPublic})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []string{"go"}, mRepo.updated.Hashtags)
+	assert.Len(t, mRepo.updated.Mentions, 1)
+
+	err = s.UpdateDraft(DraftUpdated{Content: "hi", VisibilityTypeId: VisibilityPublic, PublishAt: &past})
+	assert.Equal(t, ErrInvalidPublish, err)
+}
+
+func TestServicePublishDraft(t *testing.T) {
+	mNotification, mPublisher := MockNotificationSrv{}, MockPublisher{}
+	s := NewPostService(&MockRepo{}, &MockUserSrv{}, &mNotification, &mPublisher, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+
+	err := s.PublishDraft("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Len(t, mNotification.events, 1)
+	assert.Len(t, mPublisher.events, 1)
+
+	s = NewPostService(&MockRepo{repoErr: ErrPostNotFound}, &MockUserSrv{}, &mNotification, &mPublisher, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+	err = s.PublishDraft("e936e164-52fa-4fd5-b0e0-597c2f270245", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72")
+	assert.Equal(t, ErrPostNotFound, err)
+}
+
+func TestServicePublishDue(t *testing.T) {
+	mRepo := MockRepo{due: []PostCreated{
+		{UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: VisibilityPublic},
+		{UUID: "d4b7c2f0-1b7d-4a43-9d0e-0d1c4a1f3b21", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", VisibilityTypeId: VisibilityPrivate},
+	}}
+	mPublisher := MockPublisher{}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &mPublisher, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+
+	n, err := s.PublishDue(10)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 10, mRepo.gotLimit)
+	assert.Len(t, mPublisher.events, 1, "only the public post reaches the feed")
+}
+
+func TestServiceUpdatePost(t *testing.T) {
+	mRepo := MockRepo{}
+	s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, 15*time.Minute, testContentRule)
+
+	err := s.UpdatePost(PostUpdated{Content: "fixed #typo @ong"})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []string{"typo"}, mRepo.edited.Hashtags)
+	assert.Len(t, mRepo.edited.Mentions, 1)
+	assert.Equal(t, 15*time.Minute, mRepo.gotWindow)
+}
+
+func TestServiceGetRevisions(t *testing.T) {
+	testTable := []struct {
+		title    string
+		postUUID string
+		wantLen  int
+		wantErr  error
+	}{
+		{"should list revisions", "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", 1, nil},
+		{"should not found invisible post", "5f2d7a55-0a3c-4e0e-8f0f-4b6c0f3e2a10", 0, ErrPostNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewPostService(&MockRepo{targets: repostTargets()}, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			revisions, err := s.GetRevisions(v.postUUID, "e936e164-52fa-4fd5-b0e0-597c2f270245")
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Len(t, revisions, v.wantLen)
+		})
+	}
+}
+
+func TestServiceCreatePost_Poll(t *testing.T) {
+	now := time.Now()
+	publishAt := now.Add(48 * time.Hour)
+	testTable := []struct {
+		title       string
+		poll        PollCreated
+		publishAt   *time.Time
+		wantOptions []string
+		wantErr     error
+	}{
+		{"should trim options", PollCreated{[]string{" tabs ", "spaces"}, now.Add(time.Hour)}, nil, []string{"tabs", "spaces"}, nil},
+		{"should close after scheduled publish", PollCreated{[]string{"a", "b", "c", "d"}, publishAt.Add(time.Hour)}, &publishAt, []string{"a", "b", "c", "d"}, nil},
+		{"should reject one option", PollCreated{[]string{"a"}, now.Add(time.Hour)}, nil, nil, ErrInvalidPoll},
+		{"should reject five options", PollCreated{[]string{"a", "b", "c", "d", "e"}, now.Add(time.Hour)}, nil, nil, ErrInvalidPoll},
+		{"should reject blank option", PollCreated{[]string{"a", " "}, now.Add(time.Hour)}, nil, nil, ErrInvalidPoll},
+		{"should reject duplicate option", PollCreated{[]string{"a", "a"}, now.Add(time.Hour)}, nil, nil, ErrInvalidPoll},
+		{"should reject long option", PollCreated{[]string{"a", "abcdefghijklmnopqrstuvwxyz"}, now.Add(time.Hour)}, nil, nil, ErrInvalidPoll},
+		{"should reject past expiry", PollCreated{[]string{"a", "b"}, now.Add(-time.Hour)}, nil, nil, ErrInvalidPoll},
+		{"should reject expiry before publish", PollCreated{[]string{"a", "b"}, now.Add(time.Hour)}, &publishAt, nil, ErrInvalidPoll},
+		{"should reject too long poll", PollCreated{[]string{"a", "b"}, now.Add(MaxPollDuration + time.Hour)}, nil, nil, ErrInvalidPoll},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{}
+			s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &MockFilter{}, DefaultEditWindow, testContentRule)
+			poll := v.poll
+			_, err := s.CreatePost(PostCreated{Content: "vote", VisibilityTypeId: VisibilityPublic, PublishAt: v.publishAt, Poll: &poll})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			if v.wantErr == nil {
+				assert.Equal(t, v.wantOptions, mRepo.created.Poll.Options)
+			}
+		})
+	}
+}
+
+func TestServiceLinkPreview(t *testing.T) {
+	mPreviewer := MockPreviewer{}
+	s := NewPostService(&MockRepo{}, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &mPreviewer, &MockFilter{}, DefaultEditWindow, testContentRule)
+
+	_, err := s.CreatePost(PostCreated{Content: "no link here", VisibilityTypeId: VisibilityPublic})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Empty(t, mPreviewer.queued, "posts without a link are not queued")
+
+	_, err = s.CreatePost(PostCreated{Content: "read https://example.com/a", VisibilityTypeId: VisibilityPublic})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	err = s.UpdatePost(PostUpdated{Content: "link removed"})
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []string{"read https://example.com/a", "link removed"}, mPreviewer.queued, "edits always queue to clear stale previews")
+}
+
+func TestServiceContentFilter(t *testing.T) {
+	testTable := []struct {
+		title       string
+		result      filter.Result
+		wantErr     error
+		wantCreated bool
+		wantFlagged bool
+	}{
+		{"should create allowed post", filter.Result{}, nil, true, false},
+		{"should create and flag flagged post", filter.Result{Verdict: filter.Flag, Reason: filter.ErrLinkSpam}, nil, true, true},
+		{"should not create rejected post", filter.Result{Verdict: filter.Reject, Reason: filter.ErrTooFrequent}, filter.ErrTooFrequent, false, false},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := MockRepo{}
+			mFilter := MockFilter{result: v.result}
+			s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &mFilter, DefaultEditWindow, testContentRule)
+			_, err := s.CreatePost(PostCreated{Content: "  hello  ", UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245"})
+
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.wantCreated, mRepo.created.UUID != "")
+			assert.Equal(t, v.wantFlagged, len(mFilter.flagged) == 1)
+			assert.Equal(t, []filter.Content{{TargetType: filter.TargetPost, UserUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245", Text: "hello"}}, mFilter.checked)
+		})
+	}
+}
+
+func TestServiceContentFilter_Edit(t *testing.T) {
+	mFilter := MockFilter{result: filter.Result{Verdict: filter.Reject, Reason: filter.ErrBlockedWord}}
+	s := NewPostService(&MockRepo{}, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, &mFilter, DefaultEditWindow, testContentRule)
+
+	err := s.UpdatePost(PostUpdated{Content: "edited"})
+	assert.Equal(t, filter.ErrBlockedWord, err)
+	err = s.UpdateDraft(DraftUpdated{UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", Content: "edited", VisibilityTypeId: VisibilityPublic})
+	assert.Equal(t, filter.ErrBlockedWord, err)
+
+	assert.Len(t, mFilter.checked, 2)
+	assert.True(t, mFilter.checked[0].Edit, "edits of published posts should be checked as edits")
+	assert.False(t, mFilter.checked[1].Edit, "a draft was never public, rewriting it is not an edit")
+	assert.Equal(t, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", mFilter.checked[1].UUID)
+}
+
+type mockRecentRepo struct {
+	gotSkip string
+}
+
+func (m *mockRecentRepo) HasRecentContent(_, _, skipUUID, _ string, _ time.Duration) (bool, error) {
+	m.gotSkip = skipUUID
+	return true, nil
+}
+
+func (m *mockRecentRepo) CountRecent(string, string, string, time.Duration) (int, error) {
+	return 0, nil
+}
+
+func TestServiceUpdateDraft_Duplicate(t *testing.T) {
+	mRecent := mockRecentRepo{}
+	mRepo := MockRepo{}
+	contentFilter := filter.NewFilterService(nil, filter.NewDuplicate(&mRecent, 10*time.Minute))
+	s := NewPostService(&mRepo, &MockUserSrv{}, &MockNotificationSrv{}, &MockPublisher{}, &MockPreviewer{}, contentFilter, DefaultEditWindow, testContentRule)
+
+	err := s.UpdateDraft(DraftUpdated{UUID: "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", Content: "repeat of a recent post", VisibilityTypeId: VisibilityPublic})
+	assert.Equal(t, filter.ErrDuplicate, err)
+	assert.Equal(t, "f307d2db-d2ea-4ec9-8d31-27b7443d7c72", mRecent.gotSkip, "the draft must not match itself")
+	assert.Empty(t, mRepo.updated.UUID, "the duplicate draft is not stored")
+}
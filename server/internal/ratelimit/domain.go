@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Limit is a token bucket holding Burst tokens and refilling PerMinute of
+// them a minute. Each request takes one.
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+func (l Limit) perSecond() float64 {
+	return float64(l.PerMinute) / 60
+}
+
+// Decision is what a store decided about one request. Reset is how long the
+// bucket takes to be full again, RetryAfter how long a denied request should
+// wait for the next token.
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// take refills a bucket for the time since it was last used and takes a
+// token when there is one. It returns the tokens left and the decision.
+func take(tokens float64, elapsed time.Duration, l Limit) (float64, Decision) {
+	if elapsed > 0 {
+		tokens = math.Min(float64(l.Burst), tokens+elapsed.Seconds()*l.perSecond())
+	}
+
+	d := Decision{Allowed: tokens >= 1}
+	if d.Allowed {
+		tokens--
+	} else {
+		d.RetryAfter = l.wait(1 - tokens)
+	}
+	d.Remaining = int(tokens)
+	d.Reset = l.wait(float64(l.Burst) - tokens)
+	return tokens, d
+}
+
+// wait is how long refilling n tokens takes.
+func (l Limit) wait(n float64) time.Duration {
+	if n <= 0 || l.PerMinute <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(n / l.perSecond() * float64(time.Second)))
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	limit  Limit
+	usedAt time.Time
+}
+
+// MemoryStore keeps buckets in process. Limits are per instance, run a
+// single instance or use the Postgres store.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweptAt time.Time
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, l Limit) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.sweptAt) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.Burst), usedAt: now}
+		s.buckets[key] = b
+	}
+	var d Decision
+	b.tokens, d = take(b.tokens, now.Sub(b.usedAt), l)
+	b.limit, b.usedAt = l, now
+	return d, nil
+}
+
+// sweep drops buckets that refilled completely, they are the same as a new one.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.usedAt) >= b.limit.wait(float64(b.limit.Burst)-b.tokens) {
+			delete(s.buckets, key)
+		}
+	}
+	s.sweptAt = now
+}
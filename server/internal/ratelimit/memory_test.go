@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	l := Limit{PerMinute: 60, Burst: 2}
+
+	d, _ := s.Take(context.Background(), "a", l)
+	assert.Equal(t, Decision{Allowed: true, Remaining: 1, Reset: time.Second}, d)
+	d, _ = s.Take(context.Background(), "a", l)
+	assert.Equal(t, Decision{Allowed: true, Remaining: 0, Reset: 2 * time.Second}, d)
+	d, _ = s.Take(context.Background(), "a", l)
+	assert.Equal(t, Decision{Allowed: false, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second}, d)
+
+	d, _ = s.Take(context.Background(), "b", l)
+	assert.True(t, d.Allowed, "keys should have their own bucket")
+
+	now = now.Add(500 * time.Millisecond)
+	d, _ = s.Take(context.Background(), "a", l)
+	assert.False(t, d.Allowed, "half a token is not enough")
+	assert.Equal(t, 500*time.Millisecond, d.RetryAfter)
+
+	now = now.Add(time.Hour)
+	d, _ = s.Take(context.Background(), "a", l)
+	assert.Equal(t, Decision{Allowed: true, Remaining: 1, Reset: time.Second}, d, "refill should stop at burst")
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		s.Take(context.Background(), "slow", Limit{PerMinute: 1, Burst: 5})
+	}
+	s.Take(context.Background(), "fast", Limit{PerMinute: 60, Burst: 5})
+
+	now = now.Add(sweepInterval)
+	s.Take(context.Background(), "other", Limit{PerMinute: 60, Burst: 5})
+	assert.Contains(t, s.buckets, "slow", "a bucket still refilling should stay")
+	assert.NotContains(t, s.buckets, "fast", "a full bucket should be swept")
+}
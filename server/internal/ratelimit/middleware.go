@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsypasit/social-clone/server/internal/auth"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+type IStore interface {
+	Take(ctx context.Context, key string, l Limit) (Decision, error)
+}
+
+type ITokenVerifier interface {
+	VerifyToken(token string) (*auth.AuthJWTClaim, error)
+}
+
+// Limiter rate limits each client per route. Routes are keyed by method and
+// path template, like "POST /api/v1/post", and share the default limit when
+// they have none of their own.
+type Limiter struct {
+	store      IStore
+	verifier   ITokenVerifier
+	dflt       Limit
+	routes     map[string]Limit
+	trustProxy bool
+}
+
+// NewLimiter takes whether a proxy in front sets X-Forwarded-For. Without
+// one the header is client controlled and ignored.
+func NewLimiter(store IStore, verifier ITokenVerifier, dflt Limit, routes map[string]Limit, trustProxy bool) *Limiter {
+	return &Limiter{store, verifier, dflt, routes, trustProxy}
+}
+
+// Middleware must run on a router so the matched route is known. It runs
+// before the auth middleware, so it reads the user from the token itself.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, limit := l.limitFor(r)
+		if limit.PerMinute <= 0 || limit.Burst <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d, err := l.store.Take(r.Context(), name+" "+l.client(r), limit)
+		if err != nil {
+			// an unavailable store should not take the whole API down with it
+			log.Println("rate limit store failed:", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+seconds(limit.wait(float64(limit.Burst))))
+		h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		h.Set("RateLimit-Reset", seconds(d.Reset))
+		if !d.Allowed {
+			h.Set("Retry-After", seconds(d.RetryAfter))
+			util.SendJson(w, util.BuildErrResponse("too many requests")(ErrRateLimited), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Limiter) limitFor(r *http.Request) (string, Limit) {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			name := r.Method + " " + tpl
+			if limit, ok := l.routes[name]; ok {
+				return name, limit
+			}
+		}
+	}
+	return "*", l.dflt
+}
+
+// client keys a request by its user when it carries a valid token, header or
+// session cookie, else by its address. An invalid token is limited by address, auth rejects it later.
+func (l *Limiter) client(r *http.Request) string {
+	if token, _, err := auth.TokenFromRequest(r); err == nil {
+		if claim, err := l.verifier.VerifyToken(token); err == nil {
+			return "user:" + claim.UserUUID
+		}
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+// clientIP takes the last X-Forwarded-For entry, the one the trusted proxy
+// appended, earlier ones are whatever the client sent.
+func (l *Limiter) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// seconds rounds up, a client waiting the advertised time must get through.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
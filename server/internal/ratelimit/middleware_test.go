@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dsypasit/social-clone/server/internal/auth"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockStore struct {
+	decision Decision
+	err      error
+	keys     []string
+	limits   []Limit
+}
+
+func (m *MockStore) Take(_ context.Context, key string, l Limit) (Decision, error) {
+	m.keys = append(m.keys, key)
+	m.limits = append(m.limits, l)
+	return m.decision, m.err
+}
+
+func newTestRouter(l *Limiter) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(l.Middleware)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/post", ok).Methods(http.MethodPost)
+	router.HandleFunc("/post/{uuid}", ok).Methods(http.MethodGet)
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	dflt := Limit{PerMinute: 60, Burst: 10}
+	routes := map[string]Limit{"POST /post": {PerMinute: 6, Burst: 2}}
+	jwtSer := auth.NewJwtService("test")
+	token, _ := jwtSer.GenerateToken("e936e164-52fa-4fd5-b0e0-597c2f270245")
+
+	testTable := []struct {
+		title     string
+		method    string
+		path      string
+		token     string
+		cookie    string
+		wantKey   string
+		wantLimit Limit
+	}{
+		{"should key anonymous by ip", http.MethodGet, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "", "", "* ip:192.0.2.1", dflt},
+		{"should key by user", http.MethodGet, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", token, "", "* user:e936e164-52fa-4fd5-b0e0-597c2f270245", dflt},
+		{"should key invalid token by ip", http.MethodGet, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "bad", "", "* ip:192.0.2.1", dflt},
+		{"should key session cookie by user", http.MethodGet, "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "", token, "* user:e936e164-52fa-4fd5-b0e0-597c2f270245", dflt},
+		{"should use route limit", http.MethodPost, "/post", token, "", "POST /post user:e936e164-52fa-4fd5-b0e0-597c2f270245", routes["POST /post"]},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mStore := MockStore{decision: Decision{Allowed: true, Remaining: 3, Reset: 1500 * time.Millisecond}}
+			router := newTestRouter(NewLimiter(&mStore, jwtSer, dflt, routes, false))
+
+			req := httptest.NewRequest(v.method, v.path, nil)
+			if v.token != "" {
+				req.Header.Set("Authorization", "Bearer "+v.token)
+			}
+			if v.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: v.cookie})
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, []string{v.wantKey}, mStore.keys)
+			assert.Equal(t, []Limit{v.wantLimit}, mStore.limits)
+			assert.Equal(t, "3", rec.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+		})
+	}
+}
+
+func TestMiddleware_Denied(t *testing.T) {
+	mStore := MockStore{decision: Decision{Allowed: false, Reset: 10 * time.Second, RetryAfter: 1200 * time.Millisecond}}
+	router := newTestRouter(NewLimiter(&mStore, auth.NewJwtService("test"), Limit{PerMinute: 60, Burst: 10}, nil, false))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/post", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "10;w=10", rec.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "10", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+func TestMiddleware_StoreFailure(t *testing.T) {
+	mStore := MockStore{err: errors.New("db down")}
+	router := newTestRouter(NewLimiter(&mStore, auth.NewJwtService("test"), Limit{PerMinute: 60, Burst: 10}, nil, false))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/post", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "a failing store should let requests through")
+}
+
+func TestClientIP(t *testing.T) {
+	testTable := []struct {
+		title      string
+		trustProxy bool
+		xff        string
+		want       string
+	}{
+		{"should use remote address", false, "", "192.0.2.1"},
+		{"should ignore forwarded header without proxy", false, "203.0.113.9", "192.0.2.1"},
+		{"should use last forwarded entry behind proxy", true, "10.0.0.1, 203.0.113.9", "203.0.113.9"},
+		{"should fall back without forwarded header", true, "", "192.0.2.1"},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			l := NewLimiter(&MockStore{}, auth.NewJwtService("test"), Limit{}, nil, v.trustProxy)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if v.xff != "" {
+				req.Header.Set("X-Forwarded-For", v.xff)
+			}
+			assert.Equal(t, v.want, l.clientIP(req))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+)
+
+// pruneAfter is how long a bucket stays unused before it is deleted. It must
+// be longer than any limit takes to refill, a pruned bucket starts full.
+const pruneAfter = 24 * time.Hour
+
+// RateLimitRepository keeps buckets in Postgres so every instance shares
+// them. Each take locks its bucket row for one short transaction.
+type RateLimitRepository struct {
+	db *sql.DB
+
+	mu       sync.Mutex
+	prunedAt time.Time
+}
+
+func NewRateLimitRepository(db *sql.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+func (r *RateLimitRepository) Take(ctx context.Context, key string, l Limit) (Decision, error) {
+	r.pruneEvery(sweepInterval)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Decision{}, err
+	}
+	defer tx.Rollback()
+
+	// the no-op update locks an existing row so concurrent takes queue up,
+	// a new row starts full
+	var tokens, elapsed float64
+	err = tx.QueryRowContext(ctx, `INSERT INTO rate_limit_bucket AS b (key, tokens, updated_at)
+    VALUES ($1, $2, now())
+    ON CONFLICT (key) DO UPDATE SET key = b.key
+    RETURNING b.tokens, EXTRACT(EPOCH FROM now() - b.updated_at)`, key, float64(l.Burst)).Scan(&tokens, &elapsed)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	tokens, d := take(tokens, time.Duration(elapsed*float64(time.Second)), l)
+	_, err = tx.ExecContext(ctx, `UPDATE rate_limit_bucket SET tokens = $2, updated_at = now() WHERE key = $1`, key, tokens)
+	if err != nil {
+		return Decision{}, err
+	}
+	return d, tx.Commit()
+}
+
+// pruneEvery deletes long unused buckets at most once an interval, off the
+// request path.
+func (r *RateLimitRepository) pruneEvery(interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.prunedAt) < interval {
+		return
+	}
+	r.prunedAt = time.Now()
+
+	go func() {
+		_, err := r.db.Exec(`DELETE FROM rate_limit_bucket WHERE updated_at < now() - make_interval(secs => $1)`,
+			pruneAfter.Seconds())
+		if err != nil {
+			log.Println("failed to prune rate limit buckets:", err)
+		}
+	}()
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryTake(t *testing.T) {
+	testTable := []struct {
+		title      string
+		tokens     float64
+		elapsed    float64
+		wantTokens float64
+		want       Decision
+	}{
+		{"should take from new bucket", 5, 0, 4, Decision{Allowed: true, Remaining: 4, Reset: time.Second}},
+		{"should refill then take", 0, 2.5, 1.5, Decision{Allowed: true, Remaining: 1, Reset: 3500 * time.Millisecond}},
+		{"should deny empty bucket", 0.5, 0, 0.5, Decision{Allowed: false, Remaining: 0, Reset: 4500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery("INSERT INTO rate_limit_bucket (.+) ON CONFLICT \\(key\\) DO UPDATE").WithArgs("* ip:1.2.3.4", 5.0).
+				WillReturnRows(sqlmock.NewRows([]string{"tokens", "elapsed"}).AddRow(v.tokens, v.elapsed))
+			mock.ExpectExec("UPDATE rate_limit_bucket SET tokens").WithArgs("* ip:1.2.3.4", v.wantTokens).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			repo := NewRateLimitRepository(db)
+			repo.prunedAt = time.Now()
+			d, err := repo.Take(context.Background(), "* ip:1.2.3.4", Limit{PerMinute: 60, Burst: 5})
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.want, d)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package reaction
+
+import "github.com/dsypasit/social-clone/server/internal/post"
+
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+)
+
+// Target is a post or comment the viewer may react to. Public is true when
+// everyone can read it, which decides who receives the realtime update.
+type Target struct {
+	Type       string
+	UUID       string
+	AuthorUUID string
+	Public     bool
+	// PostAuthorUUID is the author of the post a comment belongs to, the
+	// same as AuthorUUID for a post.
+	PostAuthorUUID string
+}
+
+type ReactionSet struct {
+	Type string `json:"type"`
+}
+
+type ReactionResponse struct {
+	TargetType string              `json:"target_type"`
+	TargetUUID string              `json:"target_uuid"`
+	Reactions  post.ReactionCounts `json:"reactions"`
+	MyReaction *string             `json:"my_reaction"`
+}
+
+// ReactionUpdate is pushed over realtime after the counts of a target change.
+type ReactionUpdate struct {
+	TargetType string              `json:"target_type"`
+	TargetUUID string              `json:"target_uuid"`
+	Reactions  post.ReactionCounts `json:"reactions"`
+}
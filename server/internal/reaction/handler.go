@@ -0,0 +1,80 @@
+package reaction
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IReactionService interface {
+	GetTypes() []string
+	React(userUUID, targetType, targetUUID, reactionType string) (ReactionResponse, error)
+	Unreact(userUUID, targetType, targetUUID string) (ReactionResponse, error)
+}
+
+type ReactionHandler struct {
+	reactionService IReactionService
+}
+
+func NewReactionHandler(reactionService IReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionService}
+}
+
+func (h *ReactionHandler) GetTypes(w http.ResponseWriter, r *http.Request) {
+	util.SendJson(w, map[string][]string{"types": h.reactionService.GetTypes()}, http.StatusOK)
+}
+
+func (h *ReactionHandler) React(w http.ResponseWriter, r *http.Request) {
+	var body ReactionSet
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(err), http.StatusBadRequest)
+		return
+	}
+
+	h.change(w, r, func(userUUID, targetType, targetUUID string) (ReactionResponse, error) {
+		return h.reactionService.React(userUUID, targetType, targetUUID, body.Type)
+	})
+}
+
+func (h *ReactionHandler) Unreact(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.reactionService.Unreact)
+}
+
+func (h *ReactionHandler) change(w http.ResponseWriter, r *http.Request,
+	action func(userUUID, targetType, targetUUID string) (ReactionResponse, error),
+) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	vars := mux.Vars(r)
+	if !util.IsValidUUID(vars["uuid"]) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	res, err := action(userUUID, vars["type"], vars["uuid"])
+	if err != nil {
+		switch err {
+		case ErrInvalidTarget, ErrInvalidReaction:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrTargetNotFound, ErrReactionNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrBlocked:
+			util.SendJson(w, util.BuildErrResponse("forbidden")(err), http.StatusForbidden)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, res, http.StatusOK)
+}
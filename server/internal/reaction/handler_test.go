@@ -0,0 +1,103 @@
+package reaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr error
+}
+
+func (m *mService) GetTypes() []string {
+	return reactionTypes
+}
+
+func (m *mService) React(_, targetType, targetUUID, reactionType string) (ReactionResponse, error) {
+	return ReactionResponse{TargetType: targetType, TargetUUID: targetUUID, MyReaction: &reactionType}, m.isErr
+}
+
+func (m *mService) Unreact(_, targetType, targetUUID string) (ReactionResponse, error) {
+	return ReactionResponse{TargetType: targetType, TargetUUID: targetUUID}, m.isErr
+}
+
+func TestHandlerGetTypes(t *testing.T) {
+	h := NewReactionHandler(&mService{})
+	rec := httptest.NewRecorder()
+	h.GetTypes(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var res map[string][]string
+	json.NewDecoder(rec.Body).Decode(&res)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, reactionTypes, res["types"])
+}
+
+func TestHandlerReact(t *testing.T) {
+	testTable := []struct {
+		title      string
+		uuid       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should react", postUUID, `{"type":"love"}`, nil, http.StatusOK},
+		{"should bad request cause invalid body", postUUID, `{`, nil, http.StatusBadRequest},
+		{"should bad request cause invalid uuid", "abc", `{"type":"love"}`, nil, http.StatusBadRequest},
+		{"should bad request cause invalid reaction", postUUID, `{"type":"angry"}`, ErrInvalidReaction, http.StatusBadRequest},
+		{"should bad request cause invalid target", postUUID, `{"type":"love"}`, ErrInvalidTarget, http.StatusBadRequest},
+		{"should not found", postUUID, `{"type":"love"}`, ErrTargetNotFound, http.StatusNotFound},
+		{"should forbidden", postUUID, `{"type":"love"}`, ErrBlocked, http.StatusForbidden},
+		{"should service error", postUUID, `{"type":"love"}`, errors.New("service err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewReactionHandler(&mService{v.serviceErr})
+
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(v.body))
+			req = mux.SetURLVars(req, map[string]string{"type": TargetPost, "uuid": v.uuid})
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", ongUUID))
+			rec := httptest.NewRecorder()
+			h.React(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerUnreact(t *testing.T) {
+	testTable := []struct {
+		title      string
+		withUser   bool
+		serviceErr error
+		wantStatus int
+	}{
+		{"should remove reaction", true, nil, http.StatusOK},
+		{"should unauthorized", false, nil, http.StatusUnauthorized},
+		{"should not found", true, ErrReactionNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewReactionHandler(&mService{v.serviceErr})
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"type": TargetComment, "uuid": postUUID})
+			if v.withUser {
+				req = req.WithContext(context.WithValue(req.Context(), "userUUID", ongUUID))
+			}
+			rec := httptest.NewRecorder()
+			h.Unreact(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package reaction
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+)
+
+var (
+	ErrTargetNotFound   = errors.New("target not found")
+	ErrReactionNotFound = errors.New("reaction not found")
+	ErrInvalidTarget    = errors.New("invalid target type")
+)
+
+type ReactionRepository struct {
+	db *sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) *ReactionRepository {
+	return &ReactionRepository{db}
+}
+
+var targetQueries = map[string]string{
+	TargetPost: `
+  SELECT p.uuid, u.uuid, p.visibility_type_id = ` + fmt.Sprint(post.VisibilityPublic) + `, u.uuid
+  FROM post AS p
+  JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE p.uuid = $2 AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL
+  AND ` + post.VisibleTo("p", "u"),
+	TargetComment: `
+  SELECT c.uuid, cu.uuid, p.visibility_type_id = ` + fmt.Sprint(post.VisibilityPublic) + `, u.uuid
+  FROM comment AS c
+  JOIN app_user AS cu ON cu.id = c.app_user_id
+  JOIN post AS p ON p.id = c.post_id
+  JOIN app_user AS u ON u.id = p.app_user_id
+  WHERE c.uuid = $2 AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+  AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL
+  AND ` + post.VisibleTo("p", "u"),
+}
+
+func (r *ReactionRepository) GetTarget(targetType, targetUUID, viewerUUID string) (Target, error) {
+	query, ok := targetQueries[targetType]
+	if !ok {
+		return Target{}, ErrInvalidTarget
+	}
+
+	t := Target{Type: targetType}
+	err := r.db.QueryRow(query, viewerUUID, targetUUID).Scan(&t.UUID, &t.AuthorUUID, &t.Public, &t.PostAuthorUUID)
+	if err == sql.ErrNoRows {
+		return Target{}, ErrTargetNotFound
+	}
+	return t, err
+}
+
+// SetReaction adds the user's reaction or switches it to reactionType in a
+// single upsert, so concurrent requests never leave two reactions behind.
+// created is false when an existing reaction was switched.
+func (r *ReactionRepository) SetReaction(userUUID string, t Target, reactionType string) (bool, post.ReactionCounts, error) {
+	if _, ok := targetQueries[t.Type]; !ok {
+		return false, nil, ErrInvalidTarget
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, nil, err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`INSERT INTO reaction (app_user_id, %[1]s_id, type)
+    VALUES ((SELECT id FROM app_user WHERE uuid = $1), (SELECT id FROM %[1]s WHERE uuid = $2), $3)
+    ON CONFLICT (app_user_id, %[1]s_id) WHERE %[1]s_id IS NOT NULL
+    DO UPDATE SET type = EXCLUDED.type, updated_at = CURRENT_TIMESTAMP
+    RETURNING xmax = 0`, t.Type)
+
+	var created bool
+	if err := tx.QueryRow(query, userUUID, t.UUID, reactionType).Scan(&created); err != nil {
+		return false, nil, err
+	}
+
+	if created && t.Type == TargetPost {
+		_, err := tx.Exec(`UPDATE post SET num_like = COALESCE(num_like, 0) + 1 WHERE uuid = $1`, t.UUID)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	counts, err := countReactions(tx, t)
+	if err != nil {
+		return false, nil, err
+	}
+	return created, counts, tx.Commit()
+}
+
+func (r *ReactionRepository) RemoveReaction(userUUID string, t Target) (post.ReactionCounts, error) {
+	if _, ok := targetQueries[t.Type]; !ok {
+		return nil, ErrInvalidTarget
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(fmt.Sprintf(`DELETE FROM reaction
+    WHERE app_user_id = (SELECT id FROM app_user WHERE uuid = $1)
+    AND %[1]s_id = (SELECT id FROM %[1]s WHERE uuid = $2)`, t.Type), userUUID, t.UUID)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrReactionNotFound
+	}
+
+	if t.Type == TargetPost {
+		_, err := tx.Exec(`UPDATE post SET num_like = GREATEST(COALESCE(num_like, 0) - 1, 0) WHERE uuid = $1`, t.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	counts, err := countReactions(tx, t)
+	if err != nil {
+		return nil, err
+	}
+	return counts, tx.Commit()
+}
+
+func countReactions(tx *sql.Tx, t Target) (post.ReactionCounts, error) {
+	var counts post.ReactionCounts
+	err := tx.QueryRow(fmt.Sprintf(`SELECT json_object_agg(rt.type, rt.n)
+    FROM (
+      SELECT type, count(*) AS n FROM reaction
+      WHERE %[1]s_id = (SELECT id FROM %[1]s WHERE uuid = $1)
+      GROUP BY type
+    ) AS rt`, t.Type), t.UUID).Scan(&counts)
+	return counts, err
+}
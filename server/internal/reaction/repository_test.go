@@ -0,0 +1,133 @@
+package reaction
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ongUUID  = "e936e164-52fa-4fd5-b0e0-597c2f270245"
+	bobUUID  = "f6630558-b800-48ff-9a09-5863d6055154"
+	postUUID = "f307d2db-d2ea-4ec9-8d31-27b7443d7c72"
+	joeUUID  = "0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"
+)
+
+func TestGetTarget(t *testing.T) {
+	testTable := []struct {
+		title      string
+		targetType string
+		query      string
+		rows       *sqlmock.Rows
+		want       Target
+		wantErr    error
+	}{
+		{
+			"should return post", TargetPost, "FROM post AS p",
+			sqlmock.NewRows([]string{"uuid", "uuid", "public", "uuid"}).AddRow(postUUID, bobUUID, true, bobUUID),
+			Target{TargetPost, postUUID, bobUUID, true, bobUUID}, nil,
+		},
+		{
+			"should return comment", TargetComment, "FROM comment AS c(.+)p.published_at IS NOT NULL",
+			sqlmock.NewRows([]string{"uuid", "uuid", "public", "uuid"}).AddRow(postUUID, bobUUID, false, joeUUID),
+			Target{TargetComment, postUUID, bobUUID, false, joeUUID}, nil,
+		},
+		{"should not found", TargetPost, "FROM post AS p", sqlmock.NewRows([]string{"uuid", "uuid", "public", "uuid"}), Target{}, ErrTargetNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery(v.query).WithArgs(ongUUID, postUUID).WillReturnRows(v.rows)
+
+			repo := NewReactionRepository(db)
+			target, err := repo.GetTarget(v.targetType, postUUID, ongUUID)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, target, "Want %v but got %v", v.want, target)
+		})
+	}
+}
+
+func TestGetTarget_InvalidTarget(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := NewReactionRepository(db)
+	_, err := repo.GetTarget("user", postUUID, ongUUID)
+	assert.Equal(t, ErrInvalidTarget, err)
+}
+
+func TestSetReaction(t *testing.T) {
+	testTable := []struct {
+		title   string
+		target  Target
+		created bool
+	}{
+		{"should add reaction to post", Target{Type: TargetPost, UUID: postUUID}, true},
+		{"should switch reaction on post", Target{Type: TargetPost, UUID: postUUID}, false},
+		{"should add reaction to comment", Target{Type: TargetComment, UUID: postUUID}, true},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery("INSERT INTO reaction (.+) ON CONFLICT (.+) DO UPDATE").
+				WithArgs(ongUUID, postUUID, "love").
+				WillReturnRows(sqlmock.NewRows([]string{"created"}).AddRow(v.created))
+			if v.created && v.target.Type == TargetPost {
+				mock.ExpectExec("UPDATE post SET num_like = COALESCE").WithArgs(postUUID).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectQuery("json_object_agg").WithArgs(postUUID).
+				WillReturnRows(sqlmock.NewRows([]string{"counts"}).AddRow(`{"love":1}`))
+			mock.ExpectCommit()
+
+			repo := NewReactionRepository(db)
+			created, counts, err := repo.SetReaction(ongUUID, v.target, "love")
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equal(t, v.created, created)
+			assert.Equal(t, post.ReactionCounts{"love": 1}, counts)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRemoveReaction(t *testing.T) {
+	testTable := []struct {
+		title   string
+		affect  int64
+		wantErr error
+	}{
+		{"should remove reaction", 1, nil},
+		{"should not found", 0, ErrReactionNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectBegin()
+			mock.ExpectExec("DELETE FROM reaction").WithArgs(ongUUID, postUUID).WillReturnResult(sqlmock.NewResult(0, v.affect))
+			if v.wantErr == nil {
+				mock.ExpectExec("UPDATE post SET num_like = GREATEST").WithArgs(postUUID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("json_object_agg").WithArgs(postUUID).
+					WillReturnRows(sqlmock.NewRows([]string{"counts"}).AddRow(nil))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			repo := NewReactionRepository(db)
+			counts, err := repo.RemoveReaction(ongUUID, Target{Type: TargetPost, UUID: postUUID})
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			if v.wantErr == nil {
+				assert.Equal(t, post.ReactionCounts{}, counts)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package reaction
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IReactionHandler interface {
+	GetTypes(http.ResponseWriter, *http.Request)
+	React(http.ResponseWriter, *http.Request)
+	Unreact(http.ResponseWriter, *http.Request)
+}
+
+func RegisterReactionRouter(router *mux.Router, reactionHandler IReactionHandler, authMiddleware mux.MiddlewareFunc) {
+	rrouter := router.PathPrefix("/reaction").Subrouter()
+	rrouter.Use(authMiddleware)
+	rrouter.HandleFunc("/types", reactionHandler.GetTypes).Methods(http.MethodGet)
+	rrouter.HandleFunc("/{type}/{uuid}", reactionHandler.React).Methods(http.MethodPut)
+	rrouter.HandleFunc("/{type}/{uuid}", reactionHandler.Unreact).Methods(http.MethodDelete)
+}
@@ -0,0 +1,47 @@
+package reaction
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called map[string]bool
+}
+
+func (m *MockHandler) GetTypes(http.ResponseWriter, *http.Request) {
+	m.called["GetTypes"] = true
+}
+
+func (m *MockHandler) React(http.ResponseWriter, *http.Request) {
+	m.called["React"] = true
+}
+
+func (m *MockHandler) Unreact(http.ResponseWriter, *http.Request) {
+	m.called["Unreact"] = true
+}
+
+func TestRoute(t *testing.T) {
+	routes := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/reaction/types", "GetTypes"},
+		{http.MethodPut, "/reaction/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "React"},
+		{http.MethodDelete, "/reaction/comment/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "Unreact"},
+	}
+
+	router := mux.NewRouter()
+	mHandler := MockHandler{map[string]bool{}}
+	RegisterReactionRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	for _, v := range routes {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(v.method, v.path, nil))
+		assert.Truef(t, mHandler.called[v.handler], "%v not called", v.handler)
+	}
+}
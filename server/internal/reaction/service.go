@@ -0,0 +1,149 @@
+package reaction
+
+import (
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+)
+
+var (
+	ErrInvalidReaction = errors.New("invalid reaction type")
+	ErrBlocked         = errors.New("user is blocked")
+)
+
+type IReactionRepository interface {
+	GetTarget(targetType, targetUUID, viewerUUID string) (Target, error)
+	SetReaction(userUUID string, t Target, reactionType string) (bool, post.ReactionCounts, error)
+	RemoveReaction(userUUID string, t Target) (post.ReactionCounts, error)
+}
+
+type IBlockServiceForReaction interface {
+	IsBlocked(userUUID, otherUUID string) (bool, error)
+}
+
+type INotificationServiceForReaction interface {
+	Notify(notification.Event) error
+}
+
+type IPublisherForReaction interface {
+	Publish(realtime.Event) error
+}
+
+type ReactionService struct {
+	reactionRepo        IReactionRepository
+	blockService        IBlockServiceForReaction
+	notificationService INotificationServiceForReaction
+	publisher           IPublisherForReaction
+	types               []string
+}
+
+// NewReactionService accepts only the given reaction types, they come from
+// config.Reaction.
+func NewReactionService(reactionRepo IReactionRepository, blockService IBlockServiceForReaction,
+	notificationService INotificationServiceForReaction, publisher IPublisherForReaction, types []string,
+) *ReactionService {
+	return &ReactionService{reactionRepo, blockService, notificationService, publisher, types}
+}
+
+func (s *ReactionService) GetTypes() []string {
+	return s.types
+}
+
+func (s *ReactionService) React(userUUID, targetType, targetUUID, reactionType string) (ReactionResponse, error) {
+	if !slices.Contains(s.types, reactionType) {
+		return ReactionResponse{}, ErrInvalidReaction
+	}
+
+	t, err := s.target(userUUID, targetType, targetUUID)
+	if err != nil {
+		return ReactionResponse{}, err
+	}
+
+	created, counts, err := s.reactionRepo.SetReaction(userUUID, t, reactionType)
+	if err != nil {
+		return ReactionResponse{}, err
+	}
+
+	// switching reactions must not notify the author a second time
+	if created {
+		s.notifyAuthor(userUUID, t)
+	}
+	s.publishUpdate(t, counts)
+	return ReactionResponse{t.Type, t.UUID, counts, &reactionType}, nil
+}
+
+func (s *ReactionService) Unreact(userUUID, targetType, targetUUID string) (ReactionResponse, error) {
+	t, err := s.target(userUUID, targetType, targetUUID)
+	if err != nil {
+		return ReactionResponse{}, err
+	}
+
+	counts, err := s.reactionRepo.RemoveReaction(userUUID, t)
+	if err != nil {
+		return ReactionResponse{}, err
+	}
+
+	s.publishUpdate(t, counts)
+	return ReactionResponse{t.Type, t.UUID, counts, nil}, nil
+}
+
+// target loads the target, refused when the user and its author, or for a
+// comment the post's author, have blocked each other.
+func (s *ReactionService) target(userUUID, targetType, targetUUID string) (Target, error) {
+	t, err := s.reactionRepo.GetTarget(targetType, targetUUID, userUUID)
+	if err != nil {
+		return Target{}, err
+	}
+
+	authors := []string{t.AuthorUUID}
+	if t.PostAuthorUUID != t.AuthorUUID {
+		authors = append(authors, t.PostAuthorUUID)
+	}
+	for _, author := range authors {
+		blocked, err := s.blockService.IsBlocked(userUUID, author)
+		if err != nil {
+			return Target{}, err
+		}
+		if blocked {
+			return Target{}, ErrBlocked
+		}
+	}
+	return t, nil
+}
+
+func (s *ReactionService) notifyAuthor(userUUID string, t Target) {
+	targetType := notification.TargetPost
+	if t.Type == TargetComment {
+		targetType = notification.TargetComment
+	}
+	err := s.notificationService.Notify(notification.Event{
+		Type:          notification.TypeLike,
+		ActorUUID:     userUUID,
+		RecipientUUID: t.AuthorUUID,
+		TargetType:    targetType,
+		TargetUUID:    t.UUID,
+	})
+	if err != nil {
+		log.Println("failed to notify reaction:", err)
+	}
+}
+
+// publishUpdate broadcasts the new counts of public targets, for the others
+// only the author is told.
+func (s *ReactionService) publishUpdate(t Target, counts post.ReactionCounts) {
+	recipient := ""
+	if !t.Public {
+		recipient = t.AuthorUUID
+	}
+	e, err := realtime.NewEvent(realtime.TypeReaction, recipient, ReactionUpdate{t.Type, t.UUID, counts})
+	if err == nil {
+		err = s.publisher.Publish(e)
+	}
+	if err != nil {
+		log.Println("failed to publish reaction:", err)
+	}
+}
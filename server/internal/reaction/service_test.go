@@ -0,0 +1,167 @@
+package reaction
+
+import (
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/notification"
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/realtime"
+	"github.com/stretchr/testify/assert"
+)
+
+var reactionTypes = []string{"like", "love", "haha"}
+
+type MockRepo struct {
+	target    Target
+	targetErr error
+	created   bool
+	repoErr   error
+	setType   string
+}
+
+func (m *MockRepo) GetTarget(string, string, string) (Target, error) {
+	return m.target, m.targetErr
+}
+
+func (m *MockRepo) SetReaction(_ string, _ Target, reactionType string) (bool, post.ReactionCounts, error) {
+	m.setType = reactionType
+	return m.created, post.ReactionCounts{reactionType: 1}, m.repoErr
+}
+
+func (m *MockRepo) RemoveReaction(string, Target) (post.ReactionCounts, error) {
+	return post.ReactionCounts{}, m.repoErr
+}
+
+type MockBlockSrv struct {
+	blocked     bool
+	blockedUUID string
+}
+
+func (m *MockBlockSrv) IsBlocked(_, otherUUID string) (bool, error) {
+	return m.blocked || otherUUID == m.blockedUUID, nil
+}
+
+type MockNotificationSrv struct {
+	events []notification.Event
+}
+
+func (m *MockNotificationSrv) Notify(e notification.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+type MockPublisher struct {
+	events []realtime.Event
+}
+
+func (m *MockPublisher) Publish(e realtime.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func TestServiceReact(t *testing.T) {
+	publicPost := Target{TargetPost, postUUID, bobUUID, true, bobUUID}
+	testTable := []struct {
+		title         string
+		reactionType  string
+		repo          MockRepo
+		blocked       bool
+		wantNotified  int
+		wantPublished int
+		wantErr       error
+	}{
+		{"should react", "love", MockRepo{target: publicPost, created: true}, false, 1, 1, nil},
+		{"should switch without notify", "haha", MockRepo{target: publicPost}, false, 0, 1, nil},
+		{"should reject unknown type", "angry", MockRepo{target: publicPost}, false, 0, 0, ErrInvalidReaction},
+		{"should target not found", "love", MockRepo{targetErr: ErrTargetNotFound}, false, 0, 0, ErrTargetNotFound},
+		{"should forbid when blocked", "love", MockRepo{target: publicPost}, true, 0, 0, ErrBlocked},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mNotification, mPublisher := MockNotificationSrv{}, MockPublisher{}
+			s := NewReactionService(&v.repo, &MockBlockSrv{blocked: v.blocked}, &mNotification, &mPublisher, reactionTypes)
+			res, err := s.React(ongUUID, TargetPost, postUUID, v.reactionType)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Len(t, mNotification.events, v.wantNotified)
+			assert.Len(t, mPublisher.events, v.wantPublished)
+			if v.wantErr == nil {
+				assert.Equal(t, v.reactionType, *res.MyReaction)
+				assert.Equal(t, post.ReactionCounts{v.reactionType: 1}, res.Reactions)
+			}
+		})
+	}
+}
+
+func TestServiceReact_CommentPostAuthorBlocked(t *testing.T) {
+	mNotification := MockNotificationSrv{}
+	repo := MockRepo{target: Target{TargetComment, postUUID, bobUUID, true, joeUUID}, created: true}
+	s := NewReactionService(&repo, &MockBlockSrv{blockedUUID: joeUUID}, &mNotification, &MockPublisher{}, reactionTypes)
+	_, err := s.React(ongUUID, TargetComment, postUUID, "like")
+
+	assert.Equalf(t, ErrBlocked, err, "Unexpected error: %v", err)
+	assert.Empty(t, repo.setType)
+	assert.Empty(t, mNotification.events)
+}
+
+func TestServiceReact_NotifyComment(t *testing.T) {
+	mNotification := MockNotificationSrv{}
+	repo := MockRepo{target: Target{TargetComment, postUUID, bobUUID, true, joeUUID}, created: true}
+	s := NewReactionService(&repo, &MockBlockSrv{}, &mNotification, &MockPublisher{}, reactionTypes)
+	_, err := s.React(ongUUID, TargetComment, postUUID, "like")
+
+	want := notification.Event{
+		Type:          notification.TypeLike,
+		ActorUUID:     ongUUID,
+		RecipientUUID: bobUUID,
+		TargetType:    notification.TargetComment,
+		TargetUUID:    postUUID,
+	}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, []notification.Event{want}, mNotification.events)
+}
+
+func TestServiceReact_PublishRecipient(t *testing.T) {
+	testTable := []struct {
+		title         string
+		public        bool
+		wantRecipient string
+	}{
+		{"should broadcast public target", true, ""},
+		{"should tell only the author otherwise", false, bobUUID},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mPublisher := MockPublisher{}
+			repo := MockRepo{target: Target{TargetPost, postUUID, bobUUID, v.public, bobUUID}}
+			s := NewReactionService(&repo, &MockBlockSrv{}, &MockNotificationSrv{}, &mPublisher, reactionTypes)
+			_, err := s.React(ongUUID, TargetPost, postUUID, "like")
+
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Len(t, mPublisher.events, 1)
+			assert.Equal(t, realtime.TypeReaction, mPublisher.events[0].Type)
+			assert.Equal(t, v.wantRecipient, mPublisher.events[0].RecipientUUID)
+		})
+	}
+}
+
+func TestServiceUnreact(t *testing.T) {
+	testTable := []struct {
+		title   string
+		repo    MockRepo
+		wantErr error
+	}{
+		{"should remove reaction", MockRepo{target: Target{TargetPost, postUUID, bobUUID, true, bobUUID}}, nil},
+		{"should not found", MockRepo{target: Target{TargetPost, postUUID, bobUUID, true, bobUUID}, repoErr: ErrReactionNotFound}, ErrReactionNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewReactionService(&v.repo, &MockBlockSrv{}, &MockNotificationSrv{}, &MockPublisher{}, reactionTypes)
+			res, err := s.Unreact(ongUUID, TargetPost, postUUID)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Nil(t, res.MyReaction)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres NOTIFY channel shared by every server instance.
+const Channel = "realtime"
+
+// maxPayload stays under the 8000 byte NOTIFY limit, events carry references
+// rather than full content for this reason.
+const maxPayload = 7900
+
+var ErrPayloadTooLarge = errors.New("realtime payload too large")
+
+type IPublisher interface {
+	Publish(Event) error
+}
+
+// Broker publishes through Postgres so every instance, including this one,
+// receives the event on its listener and hands it to the local hub.
+type Broker struct {
+	db  *sql.DB
+	hub IPublisher
+}
+
+func NewBroker(db *sql.DB, hub IPublisher) *Broker {
+	return &Broker{db, hub}
+}
+
+func (b *Broker) Publish(e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		return ErrPayloadTooLarge
+	}
+
+	_, err = b.db.Exec("SELECT pg_notify($1, $2)", Channel, string(payload))
+	return err
+}
+
+// Listen blocks until ctx is done, forwarding notifications to the hub.
+// Events sent while the listener is reconnecting are lost, clients recover
+// by refetching.
+func (b *Broker) Listen(ctx context.Context, dbConnection string) error {
+	l := pq.NewListener(dbConnection, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("realtime listener:", err)
+		}
+	})
+	defer l.Close()
+
+	// Listen blocks until the database is reachable, keep it cancellable
+	listening := make(chan error, 1)
+	go func() { listening <- l.Listen(Channel) }()
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-listening:
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-l.Notify:
+			if n == nil {
+				continue
+			}
+			b.dispatch(n.Extra)
+		case <-time.After(90 * time.Second):
+			go l.Ping()
+		}
+	}
+}
+
+func (b *Broker) dispatch(payload string) {
+	var e Event
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		log.Println("failed to decode realtime event:", err)
+		return
+	}
+	b.hub.Publish(e)
+}
@@ -0,0 +1,61 @@
+package realtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrokerPublish(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	e, _ := NewEvent(TypeFeed, "", FeedItem{"f307d2db-d2ea-4ec9-8d31-27b7443d7c72", "f6630558-b800-48ff-9a09-5863d6055154"})
+	mock.ExpectExec("SELECT pg_notify").
+		WithArgs(Channel, `{"type":"feed","data":{"post_uuid":"f307d2db-d2ea-4ec9-8d31-27b7443d7c72","user_uuid":"f6630558-b800-48ff-9a09-5863d6055154"}}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := NewBroker(db, NewHub(1)).Publish(e)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBrokerPublish_TooLarge(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+	e, _ := NewEvent(TypeFeed, "", strings.Repeat("a", maxPayload))
+
+	err := NewBroker(db, NewHub(1)).Publish(e)
+	assert.Equal(t, ErrPayloadTooLarge, err)
+}
+
+func TestBrokerDispatch(t *testing.T) {
+	hub := NewHub(1)
+	s := hub.Subscribe("f6630558-b800-48ff-9a09-5863d6055154")
+	b := NewBroker(nil, hub)
+
+	b.dispatch("not json")
+	b.dispatch(`{"type":"notification","recipient_uuid":"f6630558-b800-48ff-9a09-5863d6055154","data":{}}`)
+
+	e := <-s.Events()
+	assert.Equal(t, TypeNotification, e.Type)
+}
+
+func TestBrokerListen_CancelBeforeConnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewBroker(nil, NewHub(1)).Listen(ctx, "postgres://127.0.0.1:1/none?sslmode=disable&connect_timeout=1")
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listen did not return after cancel")
+	}
+}
@@ -0,0 +1,32 @@
+package realtime
+
+import "encoding/json"
+
+const (
+	TypeFeed         = "feed"
+	TypeNotification = "notification"
+	TypeMessage      = "message"
+	TypeMessageRead  = "message_read"
+	TypeReaction     = "reaction"
+)
+
+// Event is what travels through the hub and over Postgres NOTIFY. An empty
+// RecipientUUID broadcasts to every connected user.
+type Event struct {
+	Type          string          `json:"type"`
+	RecipientUUID string          `json:"recipient_uuid,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func NewEvent(typ, recipientUUID string, data any) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{typ, recipientUUID, raw}, nil
+}
+
+type FeedItem struct {
+	PostUUID string `json:"post_uuid"`
+	UserUUID string `json:"user_uuid"`
+}
@@ -0,0 +1,82 @@
+package realtime
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+// writeTimeout bounds every write so a stalled client cannot hold the
+// connection open past the next heartbeat.
+const writeTimeout = 10 * time.Second
+
+type ISubscriber interface {
+	Subscribe(userUUID string) *Subscription
+	Unsubscribe(*Subscription)
+}
+
+type RealtimeHandler struct {
+	hub       ISubscriber
+	heartbeat time.Duration
+}
+
+func NewRealtimeHandler(hub ISubscriber, heartbeat time.Duration) *RealtimeHandler {
+	return &RealtimeHandler{hub, heartbeat}
+}
+
+// Stream is a Server-Sent Events endpoint. Each event is written with its
+// type as the SSE event name and its data as JSON.
+func (h *RealtimeHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, util.BuildErrResponse("invalid request")(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(format string, a ...any) error {
+		rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := fmt.Fprintf(w, format, a...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+	if err := write("retry: 3000\n\n"); err != nil {
+		return
+	}
+
+	sub := h.hub.Subscribe(userUUID)
+	defer h.hub.Unsubscribe(sub)
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := write("event: %s\ndata: %s\n\n", e.Type, e.Data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := write(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
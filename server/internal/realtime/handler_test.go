@@ -0,0 +1,65 @@
+package realtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mSubscriber struct {
+	sub          *Subscription
+	unsubscribed bool
+}
+
+func (m *mSubscriber) Subscribe(string) *Subscription {
+	return m.sub
+}
+
+func (m *mSubscriber) Unsubscribe(*Subscription) {
+	m.unsubscribed = true
+}
+
+func TestHandlerStream(t *testing.T) {
+	sub := newSubscription("f6630558-b800-48ff-9a09-5863d6055154", 1)
+	e, _ := NewEvent(TypeNotification, sub.UserUUID, map[string]string{"type": "like"})
+	sub.events <- e
+	sub.close()
+	ms := mSubscriber{sub: sub}
+	h := NewRealtimeHandler(&ms, time.Minute)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req = req.WithContext(context.WithValue(req.Context(), "userUUID", sub.UserUUID))
+	rec := httptest.NewRecorder()
+	h.Stream(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "event: notification\ndata: {\"type\":\"like\"}\n\n")
+	assert.True(t, ms.unsubscribed, "should unsubscribe on return")
+}
+
+func TestHandlerStream_Heartbeat(t *testing.T) {
+	ms := mSubscriber{sub: newSubscription("f6630558-b800-48ff-9a09-5863d6055154", 1)}
+	h := NewRealtimeHandler(&ms, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), "userUUID", ms.sub.UserUUID), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.Stream(rec, req)
+
+	assert.True(t, strings.Contains(rec.Body.String(), ": heartbeat\n\n"), "want heartbeat")
+}
+
+func TestHandlerStream_Unauthorized(t *testing.T) {
+	h := NewRealtimeHandler(&mSubscriber{}, time.Minute)
+	rec := httptest.NewRecorder()
+	h.Stream(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
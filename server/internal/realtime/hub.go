@@ -0,0 +1,99 @@
+package realtime
+
+import "sync"
+
+type Subscription struct {
+	UserUUID string
+	events   chan Event
+	once     sync.Once
+}
+
+func newSubscription(userUUID string, bufferSize int) *Subscription {
+	return &Subscription{UserUUID: userUUID, events: make(chan Event, bufferSize)}
+}
+
+// Events is closed when the subscription ends, either by Unsubscribe or
+// because the hub dropped a subscriber that could not keep up.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.events) })
+}
+
+// Hub fans events out to the subscribers of this instance. It never blocks
+// on a slow connection: a subscriber whose buffer is full is dropped and the
+// client is expected to reconnect and refetch.
+type Hub struct {
+	mu         sync.Mutex
+	subs       map[*Subscription]struct{}
+	bufferSize int
+	closed     bool
+}
+
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &Hub{subs: map[*Subscription]struct{}{}, bufferSize: bufferSize}
+}
+
+// Subscribe on a closed hub returns an already closed subscription.
+func (h *Hub) Subscribe(userUUID string) *Subscription {
+	s := newSubscription(userUUID, h.bufferSize)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		s.close()
+		return s
+	}
+	h.subs[s] = struct{}{}
+	return s
+}
+
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	delete(h.subs, s)
+	h.mu.Unlock()
+	s.close()
+}
+
+// Publish delivers e locally. On a single instance the hub can be used as
+// the publisher directly, otherwise publish through a Broker.
+func (h *Hub) Publish(e Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs {
+		if e.RecipientUUID != "" && e.RecipientUUID != s.UserUUID {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+			delete(h.subs, s)
+			s.close()
+		}
+	}
+	return nil
+}
+
+// Close ends every subscription, letting open streams return so the server
+// can shut down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for s := range h.subs {
+		delete(h.subs, s)
+		s.close()
+	}
+}
+
+func (h *Hub) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
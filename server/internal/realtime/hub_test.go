@@ -0,0 +1,59 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub(4)
+	ong := hub.Subscribe("f6630558-b800-48ff-9a09-5863d6055154")
+	bob := hub.Subscribe("ea151663-aad6-45b2-808b-e3f160956612")
+
+	hub.Publish(Event{Type: TypeFeed})
+	hub.Publish(Event{Type: TypeNotification, RecipientUUID: "ea151663-aad6-45b2-808b-e3f160956612"})
+
+	assert.Len(t, ong.Events(), 1, "broadcast only")
+	assert.Len(t, bob.Events(), 2, "broadcast and direct")
+}
+
+func TestHubPublish_DropSlowSubscriber(t *testing.T) {
+	hub := NewHub(1)
+	slow := hub.Subscribe("f6630558-b800-48ff-9a09-5863d6055154")
+
+	hub.Publish(Event{Type: TypeFeed})
+	hub.Publish(Event{Type: TypeFeed})
+
+	assert.Equal(t, 0, hub.Count())
+	<-slow.Events()
+	_, ok := <-slow.Events()
+	assert.False(t, ok, "events should be closed after drop")
+}
+
+func TestHubUnsubscribe(t *testing.T) {
+	hub := NewHub(1)
+	s := hub.Subscribe("f6630558-b800-48ff-9a09-5863d6055154")
+	hub.Unsubscribe(s)
+	hub.Unsubscribe(s)
+
+	_, ok := <-s.Events()
+	assert.False(t, ok)
+	assert.Equal(t, 0, hub.Count())
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub(1)
+	s := hub.Subscribe("f6630558-b800-48ff-9a09-5863d6055154")
+	hub.Close()
+
+	_, ok := <-s.Events()
+	assert.False(t, ok, "events should be closed on hub close")
+	assert.Equal(t, 0, hub.Count())
+
+	late := hub.Subscribe("ea151663-aad6-45b2-808b-e3f160956612")
+	hub.Unsubscribe(late)
+	_, ok = <-late.Events()
+	assert.False(t, ok, "subscribe after close should be closed")
+	assert.Equal(t, 0, hub.Count())
+}
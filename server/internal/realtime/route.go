@@ -0,0 +1,18 @@
+package realtime
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IRealtimeHandler interface {
+	Stream(http.ResponseWriter, *http.Request)
+}
+
+func RegisterRealtimeRouter(router *mux.Router, realtimeHandler IRealtimeHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/stream").Subrouter()
+	srouter.Use(authMiddleware)
+
+	srouter.HandleFunc("", realtimeHandler.Stream).Methods(http.MethodGet)
+}
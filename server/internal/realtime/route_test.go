@@ -0,0 +1,27 @@
+package realtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	streamCalled bool
+}
+
+func (m *MockHandler) Stream(http.ResponseWriter, *http.Request) {
+	m.streamCalled = true
+}
+
+func TestRoute(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{}
+	RegisterRealtimeRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.True(t, mHandler.streamCalled, "stream not called")
+}
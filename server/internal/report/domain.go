@@ -0,0 +1,60 @@
+package report
+
+import "time"
+
+const (
+	TargetUser    = "user"
+	TargetPost    = "post"
+	TargetComment = "comment"
+)
+
+const (
+	CategorySpam       = "spam"
+	CategoryHarassment = "harassment"
+	CategoryHate       = "hate"
+	CategoryViolence   = "violence"
+	CategoryNudity     = "nudity"
+	CategoryOther      = "other"
+
+	// CategoryFiltered marks reports the content filter files, users cannot
+	// pick it.
+	CategoryFiltered = "filtered"
+)
+
+const (
+	StatusOpen      = "open"
+	StatusResolved  = "resolved"
+	StatusDismissed = "dismissed"
+)
+
+type ReportCreated struct {
+	UUID         string `json:"uuid"`
+	ReporterUUID string `json:"reporter_uuid"`
+	TargetType   string `json:"target_type"`
+	TargetUUID   string `json:"target_uuid"`
+	Category     string `json:"category"`
+	Detail       string `json:"detail"`
+}
+
+type ReportQueueItem struct {
+	TargetType    string    `json:"target_type"`
+	TargetUUID    string    `json:"target_uuid"`
+	NumReport     int64     `json:"num_report"`
+	Categories    []string  `json:"categories"`
+	FirstReportAt time.Time `json:"first_report_at"`
+	LastReportAt  time.Time `json:"last_report_at"`
+}
+
+type QueueFilter struct {
+	Status     string
+	TargetType string
+	Limit      int
+	Offset     int
+}
+
+type ReportResolved struct {
+	TargetType    string
+	TargetUUID    string
+	Status        string
+	ModeratorUUID string
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidUUID = errors.New("invalid uuid format")
+
+type IReportService interface {
+	CreateReport(ReportCreated) (int64, error)
+	GetQueue(QueueFilter) ([]ReportQueueItem, error)
+	ResolveReports(ReportResolved) error
+}
+
+type ReportHandler struct {
+	reportService IReportService
+}
+
+func NewReportHandler(reportService IReportService) *ReportHandler {
+	return &ReportHandler{reportService}
+}
+
+func (h *ReportHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	var newReport ReportCreated
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	if err := json.NewDecoder(r.Body).Decode(&newReport); err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || userUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+	newReport.ReporterUUID = userUUID
+
+	if !util.IsValidUUID(newReport.TargetUUID) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	_, err := h.reportService.CreateReport(newReport)
+	if err != nil {
+		switch err {
+		case ErrInvalidTarget, ErrInvalidCategory, ErrSelfReport:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrTargetNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		case ErrDuplicateReport:
+			util.SendJson(w, util.BuildErrResponse("duplicate report")(err), http.StatusConflict)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("reported successful!"), http.StatusCreated)
+}
+
+func (h *ReportHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	items, err := h.reportService.GetQueue(QueueFilter{
+		Status:     q.Get("status"),
+		TargetType: q.Get("target_type"),
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+	})
+	if err != nil {
+		if err == ErrInvalidStatus || err == ErrInvalidTarget {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, items, http.StatusOK)
+}
+
+func (h *ReportHandler) ResolveReports(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, StatusResolved)
+}
+
+func (h *ReportHandler) DismissReports(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, StatusDismissed)
+}
+
+func (h *ReportHandler) resolve(w http.ResponseWriter, r *http.Request, status string) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	vars := mux.Vars(r)
+	if !util.IsValidUUID(vars["uuid"]) {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusBadRequest)
+		return
+	}
+
+	moderatorUUID, ok := r.Context().Value("userUUID").(string)
+	if !ok || moderatorUUID == "" {
+		util.SendJson(w, errInvalidReq(ErrInvalidUUID), http.StatusUnauthorized)
+		return
+	}
+
+	err := h.reportService.ResolveReports(ReportResolved{
+		TargetType:    vars["type"],
+		TargetUUID:    vars["uuid"],
+		Status:        status,
+		ModeratorUUID: moderatorUUID,
+	})
+	if err != nil {
+		switch err {
+		case ErrInvalidStatus, ErrInvalidTarget:
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		case ErrReportNotFound:
+			util.SendJson(w, util.BuildErrResponse("not found")(err), http.StatusNotFound)
+		default:
+			util.SendJson(w, util.BuildErrResponse("service error")(err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.SendJson(w, util.BuildResponse("updated reports successful!"), http.StatusOK)
+}
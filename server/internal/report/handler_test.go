@@ -0,0 +1,120 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr error
+}
+
+func (m *mService) CreateReport(ReportCreated) (int64, error) {
+	return 1, m.isErr
+}
+
+func (m *mService) GetQueue(QueueFilter) ([]ReportQueueItem, error) {
+	return []ReportQueueItem{}, m.isErr
+}
+
+func (m *mService) ResolveReports(ReportResolved) error {
+	return m.isErr
+}
+
+func TestHandlerCreateReport(t *testing.T) {
+	report, _ := json.Marshal(ReportCreated{
+		TargetType: TargetPost, TargetUUID: "f6630558-b800-48ff-9a09-5863d6055154", Category: CategorySpam,
+	})
+	testTable := []struct {
+		title      string
+		body       []byte
+		serviceErr error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{"should create report", report, nil, http.StatusCreated, util.BuildResponse("reported successful!")},
+		{"should bad request cause invalid body", []byte("abc"), nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause invalid uuid", []byte("{\"target_uuid\":\"abc\"}"), nil, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should bad request cause invalid category", report, ErrInvalidCategory, http.StatusBadRequest, util.BuildErrResponse("invalid request")(nil)},
+		{"should not found", report, ErrTargetNotFound, http.StatusNotFound, util.BuildErrResponse("not found")(nil)},
+		{"should conflict cause duplicate", report, ErrDuplicateReport, http.StatusConflict, util.BuildErrResponse("duplicate report")(nil)},
+		{"should service error", report, errors.New("service err"), http.StatusInternalServerError, util.BuildErrResponse("service error")(nil)},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewReportHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader(v.body))
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.CreateReport(rec, req)
+
+			var res map[string]string
+			json.NewDecoder(rec.Body).Decode(&res)
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantBody["message"], res["message"], "Want %v but got %v", v.wantBody, res)
+		})
+	}
+}
+
+func TestHandlerGetQueue(t *testing.T) {
+	testTable := []struct {
+		title      string
+		url        string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return queue", "/?status=open", nil, http.StatusOK},
+		{"should bad request cause invalid status", "/?status=abc", ErrInvalidStatus, http.StatusBadRequest},
+		{"should bad request cause invalid offset", "/?offset=-1", nil, http.StatusBadRequest},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewReportHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodGet, v.url, nil)
+			rec := httptest.NewRecorder()
+			h.GetQueue(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandlerResolveReports(t *testing.T) {
+	testTable := []struct {
+		title      string
+		vars       map[string]string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should resolve reports", map[string]string{"type": TargetPost, "uuid": "f6630558-b800-48ff-9a09-5863d6055154"}, nil, http.StatusOK},
+		{"should bad request cause invalid uuid", map[string]string{"type": TargetPost, "uuid": "abc"}, nil, http.StatusBadRequest},
+		{"should not found", map[string]string{"type": TargetPost, "uuid": "f6630558-b800-48ff-9a09-5863d6055154"}, ErrReportNotFound, http.StatusNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			h := NewReportHandler(&mService{v.serviceErr})
+
+			req, _ := http.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, v.vars)
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.ResolveReports(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
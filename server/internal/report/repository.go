@@ -0,0 +1,137 @@
+package report
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrDuplicateReport = errors.New("already reported")
+	ErrTargetNotFound  = errors.New("target not found")
+	ErrReportNotFound  = errors.New("no open reports")
+)
+
+type ReportRepository struct {
+	db *sql.DB
+}
+
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db}
+}
+
+var targetQuery = map[string]string{
+	TargetUser:    "SELECT EXISTS (SELECT 1 FROM app_user WHERE uuid = $1 AND delete_at IS NULL)",
+	TargetPost:    "SELECT EXISTS (SELECT 1 FROM post WHERE uuid = $1 AND deleted_at IS NULL AND published_at IS NOT NULL)",
+	TargetComment: "SELECT EXISTS (SELECT 1 FROM comment WHERE uuid = $1 AND deleted_at IS NULL)",
+}
+
+func (r *ReportRepository) IsTargetExist(targetType, targetUUID string) (bool, error) {
+	query, ok := targetQuery[targetType]
+	if !ok {
+		return false, fmt.Errorf("unknown target type %q", targetType)
+	}
+	var exist bool
+	err := r.db.QueryRow(query, targetUUID).Scan(&exist)
+	return exist, err
+}
+
+func (r *ReportRepository) CreateReport(rp ReportCreated) (int64, error) {
+	query := `INSERT INTO report (uuid, reporter_id, target_type, target_uuid, category, detail)
+    VALUES (
+        $1,
+        (SELECT id FROM app_user WHERE uuid = $2),
+        $3,
+        $4,
+        $5,
+        $6
+    )
+    ON CONFLICT (reporter_id, target_type, target_uuid) DO NOTHING
+    RETURNING id`
+
+	var id int64
+	err := r.db.QueryRow(query, rp.UUID, rp.ReporterUUID, rp.TargetType, rp.TargetUUID,
+		rp.Category, rp.Detail).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, ErrDuplicateReport
+	}
+	return id, err
+}
+
+// CreateFlag files a report without a reporter. A target keeps at most one
+// open flag, later ones are dropped until it is resolved.
+func (r *ReportRepository) CreateFlag(rp ReportCreated) error {
+	query := `INSERT INTO report (uuid, reporter_id, target_type, target_uuid, category, detail)
+    SELECT $1, NULL, $2, $3, $4, $5
+    WHERE NOT EXISTS (
+        SELECT 1 FROM report
+        WHERE reporter_id IS NULL AND target_type = $2 AND target_uuid = $3 AND status = 'open'
+    )`
+
+	_, err := r.db.Exec(query, rp.UUID, rp.TargetType, rp.TargetUUID, rp.Category, rp.Detail)
+	return err
+}
+
+func (r *ReportRepository) CountOpenReports(targetType, targetUUID string) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(`SELECT count(*) FROM report
+    WHERE target_type = $1 AND target_uuid = $2 AND status = 'open' AND reporter_id IS NOT NULL`,
+		targetType, targetUUID).Scan(&count)
+	return count, err
+}
+
+func (r *ReportRepository) GetQueue(f QueueFilter) ([]ReportQueueItem, error) {
+	args := []interface{}{f.Status}
+	query := `
+  SELECT target_type, target_uuid, count(*), array_agg(DISTINCT category), min(created_at), max(created_at)
+  FROM report
+  WHERE status = $1`
+	if f.TargetType != "" {
+		args = append(args, f.TargetType)
+		query += " AND target_type = $2"
+	}
+	args = append(args, f.Limit, f.Offset)
+	query += fmt.Sprintf(`
+  GROUP BY target_type, target_uuid
+  ORDER BY count(*) DESC, max(created_at) DESC
+  LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ReportQueueItem{}
+	for rows.Next() {
+		var item ReportQueueItem
+		err := rows.Scan(&item.TargetType, &item.TargetUUID, &item.NumReport, pq.Array(&item.Categories),
+			&item.FirstReportAt, &item.LastReportAt)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *ReportRepository) ResolveReports(rr ReportResolved) error {
+	query := `UPDATE report SET status = $3, resolved_at = current_timestamp,
+    resolved_by = (SELECT id FROM app_user WHERE uuid = $4)
+    WHERE target_type = $1 AND target_uuid = $2 AND status = 'open'`
+
+	result, err := r.db.Exec(query, rr.TargetType, rr.TargetUUID, rr.Status, rr.ModeratorUUID)
+	if err != nil {
+		return err
+	}
+	numAffect, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numAffect == 0 {
+		return ErrReportNotFound
+	}
+	return nil
+}
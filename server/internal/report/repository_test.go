@@ -0,0 +1,126 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReport(t *testing.T) {
+	rp := ReportCreated{
+		UUID:         "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		ReporterUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+		TargetType:   TargetPost,
+		TargetUUID:   "f6630558-b800-48ff-9a09-5863d6055154",
+		Category:     CategorySpam,
+	}
+	testTable := []struct {
+		title   string
+		rows    *sqlmock.Rows
+		wantId  int64
+		wantErr error
+	}{
+		{"should create report", sqlmock.NewRows([]string{"id"}).AddRow(1), 1, nil},
+		{"should duplicate report", sqlmock.NewRows([]string{"id"}), 0, ErrDuplicateReport},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectQuery("INSERT INTO report").
+				WithArgs(rp.UUID, rp.ReporterUUID, rp.TargetType, rp.TargetUUID, rp.Category, rp.Detail).
+				WillReturnRows(v.rows)
+
+			repo := NewReportRepository(db)
+			id, err := repo.CreateReport(rp)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.wantId, id, "Want %v but got %v", v.wantId, id)
+		})
+	}
+}
+
+func TestCreateFlag(t *testing.T) {
+	rp := ReportCreated{
+		UUID:       "f307d2db-d2ea-4ec9-8d31-27b7443d7c72",
+		TargetType: TargetComment,
+		TargetUUID: "f6630558-b800-48ff-9a09-5863d6055154",
+		Category:   CategoryFiltered,
+		Detail:     "duplicate content",
+	}
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectExec("INSERT INTO report (.+) WHERE NOT EXISTS").
+		WithArgs(rp.UUID, rp.TargetType, rp.TargetUUID, rp.Category, rp.Detail).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := NewReportRepository(db)
+	err := repo.CreateFlag(rp)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestIsTargetExist(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM comment").WithArgs("f6630558-b800-48ff-9a09-5863d6055154").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	repo := NewReportRepository(db)
+	exist, err := repo.IsTargetExist(TargetComment, "f6630558-b800-48ff-9a09-5863d6055154")
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.True(t, exist, "target should exist")
+
+	_, err = repo.IsTargetExist("group", "f6630558-b800-48ff-9a09-5863d6055154")
+	assert.NotNil(t, err, "unknown target type should error")
+}
+
+func TestGetQueue(t *testing.T) {
+	reportAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("SELECT target_type, target_uuid, count").WithArgs(StatusOpen, TargetPost, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"target_type", "target_uuid", "count", "array_agg", "min", "max"}).
+			AddRow(TargetPost, "f6630558-b800-48ff-9a09-5863d6055154", 3, pq.StringArray{"hate", "spam"}, reportAt, reportAt))
+
+	repo := NewReportRepository(db)
+	items, err := repo.GetQueue(QueueFilter{Status: StatusOpen, TargetType: TargetPost, Limit: 20})
+	want := []ReportQueueItem{{
+		TargetType:    TargetPost,
+		TargetUUID:    "f6630558-b800-48ff-9a09-5863d6055154",
+		NumReport:     3,
+		Categories:    []string{"hate", "spam"},
+		FirstReportAt: reportAt,
+		LastReportAt:  reportAt,
+	}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, items, "Want %v but got %v", want, items)
+}
+
+func TestResolveReports(t *testing.T) {
+	rr := ReportResolved{TargetPost, "f6630558-b800-48ff-9a09-5863d6055154", StatusDismissed, "e936e164-52fa-4fd5-b0e0-597c2f270245"}
+	testTable := []struct {
+		title   string
+		affect  int64
+		wantErr error
+	}{
+		{"should resolve reports", 2, nil},
+		{"should not found open reports", 0, ErrReportNotFound},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			mock.ExpectExec("UPDATE report").WithArgs(rr.TargetType, rr.TargetUUID, rr.Status, rr.ModeratorUUID).
+				WillReturnResult(sqlmock.NewResult(0, v.affect))
+
+			repo := NewReportRepository(db)
+			err := repo.ResolveReports(rr)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
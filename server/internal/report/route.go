@@ -0,0 +1,28 @@
+package report
+
+import (
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/middleware"
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/gorilla/mux"
+)
+
+type IReportHandler interface {
+	CreateReport(http.ResponseWriter, *http.Request)
+	GetQueue(http.ResponseWriter, *http.Request)
+	ResolveReports(http.ResponseWriter, *http.Request)
+	DismissReports(http.ResponseWriter, *http.Request)
+}
+
+func RegisterReportRouter(router *mux.Router, reportHandler IReportHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/report").Subrouter()
+	srouter.Use(authMiddleware)
+	srouter.HandleFunc("", reportHandler.CreateReport).Methods(http.MethodPost)
+
+	arouter := router.PathPrefix("/admin/reports").Subrouter()
+	arouter.Use(authMiddleware, middleware.RequireRole(user.RoleAdmin))
+	arouter.HandleFunc("", reportHandler.GetQueue).Methods(http.MethodGet)
+	arouter.HandleFunc("/{type}/{uuid}/resolve", reportHandler.ResolveReports).Methods(http.MethodPost)
+	arouter.HandleFunc("/{type}/{uuid}/dismiss", reportHandler.DismissReports).Methods(http.MethodPost)
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/user"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	createReportCalled   bool
+	getQueueCalled       bool
+	resolveReportsCalled bool
+	dismissReportsCalled bool
+}
+
+func (m *MockHandler) CreateReport(http.ResponseWriter, *http.Request) {
+	m.createReportCalled = true
+}
+
+func (m *MockHandler) GetQueue(http.ResponseWriter, *http.Request) {
+	m.getQueueCalled = true
+}
+
+func (m *MockHandler) ResolveReports(http.ResponseWriter, *http.Request) {
+	m.resolveReportsCalled = true
+}
+
+func (m *MockHandler) DismissReports(http.ResponseWriter, *http.Request) {
+	m.dismissReportsCalled = true
+}
+
+func fakeAuth(role string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "userRole", role)))
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{}
+	RegisterReportRouter(router, &mHandler, fakeAuth(user.RoleAdmin))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/report", nil))
+	assert.True(t, mHandler.createReportCalled, "create report not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/reports", nil))
+	assert.True(t, mHandler.getQueueCalled, "get queue not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/admin/reports/post/f6630558-b800-48ff-9a09-5863d6055154/resolve", nil))
+	assert.True(t, mHandler.resolveReportsCalled, "resolve reports not called")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/admin/reports/post/f6630558-b800-48ff-9a09-5863d6055154/dismiss", nil))
+	assert.True(t, mHandler.dismissReportsCalled, "dismiss reports not called")
+}
+
+func TestRoute_QueueForbidden(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{}
+	RegisterReportRouter(router, &mHandler, fakeAuth(user.RoleUser))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/reports", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, mHandler.getQueueCalled, "get queue should not called")
+}
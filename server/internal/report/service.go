@@ -0,0 +1,139 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTarget   = errors.New("invalid target type")
+	ErrInvalidCategory = errors.New("invalid category")
+	ErrInvalidStatus   = errors.New("invalid status")
+	ErrSelfReport      = errors.New("cannot report yourself")
+)
+
+var categories = map[string]bool{
+	CategorySpam:       true,
+	CategoryHarassment: true,
+	CategoryHate:       true,
+	CategoryViolence:   true,
+	CategoryNudity:     true,
+	CategoryOther:      true,
+}
+
+type IReportRepository interface {
+	IsTargetExist(targetType, targetUUID string) (bool, error)
+	CreateReport(ReportCreated) (int64, error)
+	CreateFlag(ReportCreated) error
+	CountOpenReports(targetType, targetUUID string) (int64, error)
+	GetQueue(QueueFilter) ([]ReportQueueItem, error)
+	ResolveReports(ReportResolved) error
+}
+
+type IModerationService interface {
+	HidePost(moderatorUUID, postUUID, reason string) error
+	HideComment(moderatorUUID, commentUUID, reason string) error
+}
+
+type ReportService struct {
+	reportRepo        IReportRepository
+	moderationService IModerationService
+	threshold         int
+}
+
+func NewReportService(reportRepo IReportRepository, moderationService IModerationService, threshold int) *ReportService {
+	return &ReportService{reportRepo, moderationService, threshold}
+}
+
+func (s *ReportService) CreateReport(rp ReportCreated) (int64, error) {
+	if rp.TargetType != TargetUser && rp.TargetType != TargetPost && rp.TargetType != TargetComment {
+		return 0, ErrInvalidTarget
+	}
+	if !categories[rp.Category] {
+		return 0, ErrInvalidCategory
+	}
+	if rp.TargetType == TargetUser && rp.TargetUUID == rp.ReporterUUID {
+		return 0, ErrSelfReport
+	}
+
+	exist, err := s.reportRepo.IsTargetExist(rp.TargetType, rp.TargetUUID)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, ErrTargetNotFound
+	}
+
+	rp.UUID = uuid.NewString()
+	id, err := s.reportRepo.CreateReport(rp)
+	if err != nil {
+		return 0, err
+	}
+
+	// the report is already stored, so a failed auto-hide is only logged and
+	// will be retried by a moderator from the queue
+	if err := s.autoHide(rp.TargetType, rp.TargetUUID); err != nil {
+		log.Println("failed to auto-hide reported content:", err)
+	}
+	return id, nil
+}
+
+// Flag queues content the content filter let through but wants a moderator
+// to look at. It does not count toward the auto-hide threshold.
+func (s *ReportService) Flag(targetType, targetUUID, reason string) error {
+	return s.reportRepo.CreateFlag(ReportCreated{
+		UUID:       uuid.NewString(),
+		TargetType: targetType,
+		TargetUUID: targetUUID,
+		Category:   CategoryFiltered,
+		Detail:     reason,
+	})
+}
+
+func (s *ReportService) autoHide(targetType, targetUUID string) error {
+	if s.threshold <= 0 || targetType == TargetUser {
+		return nil
+	}
+
+	count, err := s.reportRepo.CountOpenReports(targetType, targetUUID)
+	if err != nil {
+		return err
+	}
+	// concurrent reports may both see the threshold passed, hiding is
+	// idempotent so only the first one hides and logs
+	if count < int64(s.threshold) {
+		return nil
+	}
+
+	reason := fmt.Sprintf("auto-hidden after %d reports", s.threshold)
+	if targetType == TargetPost {
+		return s.moderationService.HidePost("", targetUUID, reason)
+	}
+	return s.moderationService.HideComment("", targetUUID, reason)
+}
+
+func (s *ReportService) GetQueue(f QueueFilter) ([]ReportQueueItem, error) {
+	if f.Status == "" {
+		f.Status = StatusOpen
+	}
+	if f.Status != StatusOpen && f.Status != StatusResolved && f.Status != StatusDismissed {
+		return nil, ErrInvalidStatus
+	}
+	if f.TargetType != "" && f.TargetType != TargetUser && f.TargetType != TargetPost && f.TargetType != TargetComment {
+		return nil, ErrInvalidTarget
+	}
+	return s.reportRepo.GetQueue(f)
+}
+
+func (s *ReportService) ResolveReports(rr ReportResolved) error {
+	if rr.Status != StatusResolved && rr.Status != StatusDismissed {
+		return ErrInvalidStatus
+	}
+	if rr.TargetType != TargetUser && rr.TargetType != TargetPost && rr.TargetType != TargetComment {
+		return ErrInvalidTarget
+	}
+	return s.reportRepo.ResolveReports(rr)
+}
@@ -0,0 +1,165 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	exist    bool
+	count    int64
+	repoErr  error
+	resolved ReportResolved
+	flagged  ReportCreated
+}
+
+func (m *MockRepo) IsTargetExist(string, string) (bool, error) {
+	return m.exist, nil
+}
+
+func (m *MockRepo) CreateReport(ReportCreated) (int64, error) {
+	if m.repoErr != nil {
+		return 0, m.repoErr
+	}
+	return 1, nil
+}
+
+func (m *MockRepo) CreateFlag(rp ReportCreated) error {
+	m.flagged = rp
+	return m.repoErr
+}
+
+func (m *MockRepo) CountOpenReports(string, string) (int64, error) {
+	return m.count, nil
+}
+
+func (m *MockRepo) GetQueue(QueueFilter) ([]ReportQueueItem, error) {
+	return []ReportQueueItem{}, m.repoErr
+}
+
+func (m *MockRepo) ResolveReports(rr ReportResolved) error {
+	m.resolved = rr
+	return m.repoErr
+}
+
+type MockModerationSrv struct {
+	hiddenPost    string
+	hiddenComment string
+}
+
+func (m *MockModerationSrv) HidePost(moderatorUUID, postUUID, reason string) error {
+	m.hiddenPost = postUUID
+	return nil
+}
+
+func (m *MockModerationSrv) HideComment(moderatorUUID, commentUUID, reason string) error {
+	m.hiddenComment = commentUUID
+	return nil
+}
+
+func TestServiceCreateReport(t *testing.T) {
+	reporter := "e936e164-52fa-4fd5-b0e0-597c2f270245"
+	target := "f6630558-b800-48ff-9a09-5863d6055154"
+	testTable := []struct {
+		title   string
+		report  ReportCreated
+		exist   bool
+		repoErr error
+		wantErr error
+	}{
+		{"should create report", ReportCreated{ReporterUUID: reporter, TargetType: TargetPost, TargetUUID: target, Category: CategorySpam}, true, nil, nil},
+		{"should invalid target", ReportCreated{ReporterUUID: reporter, TargetType: "group", TargetUUID: target, Category: CategorySpam}, true, nil, ErrInvalidTarget},
+		{"should invalid category", ReportCreated{ReporterUUID: reporter, TargetType: TargetPost, TargetUUID: target, Category: "boring"}, true, nil, ErrInvalidCategory},
+		{"should not report yourself", ReportCreated{ReporterUUID: reporter, TargetType: TargetUser, TargetUUID: reporter, Category: CategorySpam}, true, nil, ErrSelfReport},
+		{"should target not found", ReportCreated{ReporterUUID: reporter, TargetType: TargetPost, TargetUUID: target, Category: CategorySpam}, false, nil, ErrTargetNotFound},
+		{"should duplicate report", ReportCreated{ReporterUUID: reporter, TargetType: TargetPost, TargetUUID: target, Category: CategorySpam}, true, ErrDuplicateReport, ErrDuplicateReport},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewReportService(&MockRepo{exist: v.exist, repoErr: v.repoErr}, &MockModerationSrv{}, 3)
+			_, err := s.CreateReport(v.report)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceCreateReport_AutoHide(t *testing.T) {
+	target := "f6630558-b800-48ff-9a09-5863d6055154"
+	testTable := []struct {
+		title       string
+		targetType  string
+		count       int64
+		wantPost    string
+		wantComment string
+	}{
+		{"should hide post at threshold", TargetPost, 3, target, ""},
+		{"should hide comment at threshold", TargetComment, 3, "", target},
+		{"should not hide below threshold", TargetPost, 2, "", ""},
+		{"should hide past threshold", TargetPost, 4, target, ""},
+		{"should not hide user", TargetUser, 3, "", ""},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			ms := MockModerationSrv{}
+			s := NewReportService(&MockRepo{exist: true, count: v.count}, &ms, 3)
+			_, err := s.CreateReport(ReportCreated{
+				ReporterUUID: "e936e164-52fa-4fd5-b0e0-597c2f270245",
+				TargetType:   v.targetType,
+				TargetUUID:   target,
+				Category:     CategorySpam,
+			})
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.wantPost, ms.hiddenPost, "Want %v but got %v", v.wantPost, ms.hiddenPost)
+			assert.Equalf(t, v.wantComment, ms.hiddenComment, "Want %v but got %v", v.wantComment, ms.hiddenComment)
+		})
+	}
+}
+
+func TestServiceFlag(t *testing.T) {
+	mRepo := MockRepo{count: 2}
+	mModeration := MockModerationSrv{}
+	s := NewReportService(&mRepo, &mModeration, 1)
+	err := s.Flag(TargetPost, "f6630558-b800-48ff-9a09-5863d6055154", "too many links")
+
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equal(t, CategoryFiltered, mRepo.flagged.Category)
+	assert.Equal(t, "too many links", mRepo.flagged.Detail)
+	assert.NotEmpty(t, mRepo.flagged.UUID)
+	assert.Empty(t, mModeration.hiddenPost, "flags must not auto-hide")
+}
+
+func TestServiceGetQueue(t *testing.T) {
+	testTable := []struct {
+		title   string
+		filter  QueueFilter
+		wantErr error
+	}{
+		{"should return open queue by default", QueueFilter{}, nil},
+		{"should invalid status", QueueFilter{Status: "closed"}, ErrInvalidStatus},
+		{"should invalid target", QueueFilter{TargetType: "group"}, ErrInvalidTarget},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			s := NewReportService(&MockRepo{}, &MockModerationSrv{}, 3)
+			_, err := s.GetQueue(v.filter)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestServiceResolveReports(t *testing.T) {
+	rr := ReportResolved{TargetPost, "f6630558-b800-48ff-9a09-5863d6055154", StatusResolved, "e936e164-52fa-4fd5-b0e0-597c2f270245"}
+	m := MockRepo{}
+	s := NewReportService(&m, &MockModerationSrv{}, 3)
+	err := s.ResolveReports(rr)
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, rr, m.resolved, "Want %v but got %v", rr, m.resolved)
+
+	rr.Status = StatusOpen
+	err = s.ResolveReports(rr)
+	assert.Equalf(t, ErrInvalidStatus, err, "Unexpected error: %v", err)
+}
@@ -0,0 +1,11 @@
+package search
+
+const (
+	TypePosts = "posts"
+	TypeUsers = "users"
+)
+
+type UserResult struct {
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+)
+
+type ISearchService interface {
+	SearchPosts(q, viewerUUID string, limit, offset int) ([]post.PostResponse, error)
+	SearchUsers(q, viewerUUID string, limit, offset int) ([]UserResult, error)
+}
+
+type SearchHandler struct {
+	searchService ISearchService
+}
+
+func NewSearchHandler(searchService ISearchService) *SearchHandler {
+	return &SearchHandler{searchService}
+}
+
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	errInvalidReq := util.BuildErrResponse("invalid request")
+	page, err := util.ParsePage(r)
+	if err != nil {
+		util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+
+	var result interface{}
+	switch r.URL.Query().Get("type") {
+	case "", TypePosts:
+		result, err = h.searchService.SearchPosts(q, viewerUUID, page.Limit, page.Offset)
+	case TypeUsers:
+		result, err = h.searchService.SearchUsers(q, viewerUUID, page.Limit, page.Offset)
+	default:
+		err = ErrInvalidType
+	}
+	if err != nil {
+		if err == ErrInvalidQuery || err == ErrInvalidType {
+			util.SendJson(w, errInvalidReq(err), http.StatusBadRequest)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failed")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, result, http.StatusOK)
+}
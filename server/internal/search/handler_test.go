@@ -0,0 +1,72 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/stretchr/testify/assert"
+)
+
+type mService struct {
+	isErr  error
+	called string
+}
+
+func (m *mService) SearchPosts(string, string, int, int) ([]post.PostResponse, error) {
+	m.called = TypePosts
+	return []post.PostResponse{}, m.isErr
+}
+
+func (m *mService) SearchUsers(string, string, int, int) ([]UserResult, error) {
+	m.called = TypeUsers
+	return []UserResult{{"f6630558-b800-48ff-9a09-5863d6055154", "ong"}}, m.isErr
+}
+
+func TestHandlerSearch(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		serviceErr error
+		wantStatus int
+		wantCalled string
+	}{
+		{"should search posts by default", "?q=golang", nil, http.StatusOK, TypePosts},
+		{"should search users", "?q=on&type=users", nil, http.StatusOK, TypeUsers},
+		{"should bad request cause invalid type", "?q=on&type=tags", nil, http.StatusBadRequest, ""},
+		{"should bad request cause invalid page", "?q=on&limit=abc", nil, http.StatusBadRequest, ""},
+		{"should bad request cause invalid query", "?q=", ErrInvalidQuery, http.StatusBadRequest, TypePosts},
+		{"should service failed", "?q=golang", errors.New("db err"), http.StatusInternalServerError, TypePosts},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			srv := &mService{isErr: v.serviceErr}
+			h := NewSearchHandler(srv)
+
+			req, _ := http.NewRequest(http.MethodGet, "/"+v.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), "userUUID", "e936e164-52fa-4fd5-b0e0-597c2f270245"))
+			rec := httptest.NewRecorder()
+			h.Search(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "Want %v but got %v", v.wantStatus, rec.Code)
+			assert.Equalf(t, v.wantCalled, srv.called, "Want %v but got %v", v.wantCalled, srv.called)
+		})
+	}
+}
+
+func TestHandlerSearchUsersBody(t *testing.T) {
+	h := NewSearchHandler(&mService{})
+	req, _ := http.NewRequest(http.MethodGet, "/?q=on&type=users", nil)
+	rec := httptest.NewRecorder()
+	h.Search(rec, req)
+
+	var res []UserResult
+	json.NewDecoder(rec.Body).Decode(&res)
+	want := []UserResult{{"f6630558-b800-48ff-9a09-5863d6055154", "ong"}}
+	assert.Equalf(t, want, res, "Want %v but got %v", want, res)
+}
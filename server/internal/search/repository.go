@@ -0,0 +1,100 @@
+package search
+
+import (
+	"database/sql"
+	"strings"
+	"unicode"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+)
+
+type SearchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{db}
+}
+
+// blockFilter drops authors the viewer ($1) has blocked or been blocked by.
+var blockFilter = `
+  AND ` + post.NotBlocked("u")
+
+// visibilityFilter keeps posts the viewer ($1) is allowed to read.
+var visibilityFilter = `
+  AND ` + post.VisibleTo("p", "u")
+
+func (r *SearchRepository) SearchPosts(q, viewerUUID string, limit, offset int) ([]post.PostResponse, error) {
+	query := `
+  SELECT ` + post.PostColumns + `
+  FROM post AS p
+  JOIN app_user AS u ON u.id = p.app_user_id
+  CROSS JOIN websearch_to_tsquery('english', $2) AS query
+  WHERE p.content_tsv @@ query
+  AND p.deleted_at IS NULL AND p.hidden_at IS NULL AND p.published_at IS NOT NULL
+  AND u.delete_at IS NULL AND u.suspended_at IS NULL` + visibilityFilter + blockFilter + `
+  ORDER BY ts_rank(p.content_tsv, query) DESC, p.id DESC
+  LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, viewerUUID, q, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []post.PostResponse{}
+	for rows.Next() {
+		p, err := post.ScanPost(rows)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+func (r *SearchRepository) SearchUsers(q, viewerUUID string, limit, offset int) ([]UserResult, error) {
+	users := []UserResult{}
+	tsquery := prefixQuery(q)
+	if tsquery == "" {
+		return users, nil
+	}
+
+	query := `
+  SELECT u.uuid, u.username
+  FROM app_user AS u
+  CROSS JOIN to_tsquery('simple', $2) AS query
+  WHERE u.username_tsv @@ query
+  AND u.delete_at IS NULL AND u.suspended_at IS NULL` + blockFilter + `
+  ORDER BY lower(u.username) = lower($3) DESC, ts_rank(u.username_tsv, query) DESC, length(u.username), u.id
+  LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Query(query, viewerUUID, tsquery, q, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u UserResult
+		if err := rows.Scan(&u.UUID, &u.Username); err != nil {
+			return users, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// prefixQuery turns free text into a tsquery where every word is matched as a
+// prefix, e.g. "jo do" becomes "jo:* & do:*". Anything that is not a letter
+// or digit is treated as a separator so user input cannot inject tsquery
+// operators.
+func prefixQuery(q string) string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/dsypasit/social-clone/server/internal/share/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchPosts(t *testing.T) {
+	updateAt := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	mock.ExpectQuery("websearch_to_tsquery").
+		WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", "golang", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "content", "num_like", "visibility_type_id", "uuid", "username", "updated_at", "mentions",
+			"is_repost", "is_quote", "num_repost", "num_quote", "reposted_by_me", "original", "reactions", "my_reaction", "bookmarked_by_me", "edited_at", "poll", "link_preview"}).
+			AddRow("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11", "i love golang", 2, 1, "f6630558-b800-48ff-9a09-5863d6055154", "ong", updateAt, nil,
+				false, false, 0, 0, false, nil, nil, nil, false, nil, nil, nil))
+
+	repo := NewSearchRepository(db)
+	posts, err := repo.SearchPosts("golang", "e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0)
+	want := []post.PostResponse{{
+		UUID:             util.Ptr("0c5b8ef5-3e8e-4c0e-9a47-4d7c6f8c8a11"),
+		Content:          util.Ptr("i love golang"),
+		NumLike:          2,
+		Username:         util.Ptr("ong"),
+		UserUUID:         util.Ptr("f6630558-b800-48ff-9a09-5863d6055154"),
+		VisibilityTypeId: 1,
+		UpdateAt:         updateAt,
+		Reactions:        post.ReactionCounts{},
+	}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, posts, "Want %v but got %v", want, posts)
+}
+
+func TestSearchUsers(t *testing.T) {
+	testTable := []struct {
+		title   string
+		q       string
+		tsquery string
+		rows    *sqlmock.Rows
+		want    []UserResult
+	}{
+		{
+			"should return matched users", "On", "on:*",
+			sqlmock.NewRows([]string{"uuid", "username"}).AddRow("f6630558-b800-48ff-9a09-5863d6055154", "ong"),
+			[]UserResult{{"f6630558-b800-48ff-9a09-5863d6055154", "ong"}},
+		},
+		{"should not query when nothing searchable", "&|!", "", nil, []UserResult{}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			if v.rows != nil {
+				mock.ExpectQuery("to_tsquery").
+					WithArgs("e936e164-52fa-4fd5-b0e0-597c2f270245", v.tsquery, v.q, 20, 0).
+					WillReturnRows(v.rows)
+			}
+
+			repo := NewSearchRepository(db)
+			users, err := repo.SearchUsers(v.q, "e936e164-52fa-4fd5-b0e0-597c2f270245", 20, 0)
+			assert.Nilf(t, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, users, "Want %v but got %v", v.want, users)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	testTable := []struct {
+		q    string
+		want string
+	}{
+		{"ong", "ong:*"},
+		{"John Doe", "john:* & doe:*"},
+		{"a:* | b", "a:* & b:*"},
+		{"john_doe", "john:* & doe:*"},
+		{"!!", ""},
+	}
+
+	for _, v := range testTable {
+		got := prefixQuery(v.q)
+		assert.Equalf(t, v.want, got, "Want %v but got %v", v.want, got)
+	}
+}
@@ -0,0 +1,18 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ISearchHandler interface {
+	Search(http.ResponseWriter, *http.Request)
+}
+
+func RegisterSearchRouter(router *mux.Router, searchHandler ISearchHandler, authMiddleware mux.MiddlewareFunc) {
+	srouter := router.PathPrefix("/search").Subrouter()
+	srouter.Use(authMiddleware)
+
+	srouter.HandleFunc("", searchHandler.Search).Methods(http.MethodGet)
+}
@@ -0,0 +1,27 @@
+package search
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHandler struct {
+	called bool
+}
+
+func (m *MockHandler) Search(http.ResponseWriter, *http.Request) {
+	m.called = true
+}
+
+func TestRoute(t *testing.T) {
+	router := mux.NewRouter()
+	mHandler := MockHandler{}
+	RegisterSearchRouter(router, &mHandler, mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next }))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?q=a", nil))
+	assert.True(t, mHandler.called, "Search not called")
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+)
+
+const maxQueryLength = 100
+
+var (
+	ErrInvalidQuery = errors.New("invalid search query")
+	ErrInvalidType  = errors.New("invalid search type")
+)
+
+type ISearchRepository interface {
+	SearchPosts(q, viewerUUID string, limit, offset int) ([]post.PostResponse, error)
+	SearchUsers(q, viewerUUID string, limit, offset int) ([]UserResult, error)
+}
+
+type SearchService struct {
+	searchRepo ISearchRepository
+}
+
+func NewSearchService(searchRepo ISearchRepository) *SearchService {
+	return &SearchService{searchRepo}
+}
+
+func (s *SearchService) SearchPosts(q, viewerUUID string, limit, offset int) ([]post.PostResponse, error) {
+	q, err := normalizeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchRepo.SearchPosts(q, viewerUUID, limit, offset)
+}
+
+func (s *SearchService) SearchUsers(q, viewerUUID string, limit, offset int) ([]UserResult, error) {
+	q, err := normalizeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchRepo.SearchUsers(q, viewerUUID, limit, offset)
+}
+
+func normalizeQuery(q string) (string, error) {
+	q = strings.TrimSpace(q)
+	if q == "" || utf8.RuneCountInString(q) > maxQueryLength {
+		return "", ErrInvalidQuery
+	}
+	return q, nil
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsypasit/social-clone/server/internal/post"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockRepo struct {
+	gotQuery string
+}
+
+func (m *MockRepo) SearchPosts(q, _ string, _, _ int) ([]post.PostResponse, error) {
+	m.gotQuery = q
+	return []post.PostResponse{}, nil
+}
+
+func (m *MockRepo) SearchUsers(q, _ string, _, _ int) ([]UserResult, error) {
+	m.gotQuery = q
+	return []UserResult{}, nil
+}
+
+func TestServiceSearch(t *testing.T) {
+	testTable := []struct {
+		title     string
+		q         string
+		wantQuery string
+		wantErr   error
+	}{
+		{"should trim query", "  golang ", "golang", nil},
+		{"should reject empty query", "   ", "", ErrInvalidQuery},
+		{"should reject long query", strings.Repeat("a", 101), "", ErrInvalidQuery},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			repo := &MockRepo{}
+			s := NewSearchService(repo)
+
+			_, err := s.SearchPosts(v.q, "", 20, 0)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.wantQuery, repo.gotQuery, "Want %v but got %v", v.wantQuery, repo.gotQuery)
+
+			_, err = s.SearchUsers(v.q, "", 20, 0)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+		})
+	}
+}
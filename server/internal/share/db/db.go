@@ -3,7 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
-	"log"
+	"fmt"
 	"time"
 )
 
@@ -20,11 +20,14 @@ func Close() {
 	DB.Close()
 }
 
-func Ping(ctx context.Context) {
+// Ping reports whether the database answers within a second. It leaves
+// deciding what an outage means to the caller.
+func Ping(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
 	if err := DB.PingContext(ctx); err != nil {
-		log.Fatalf("unable to connect to database: %v", err)
+		return fmt.Errorf("unable to connect to database: %w", err)
 	}
+	return nil
 }
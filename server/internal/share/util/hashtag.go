@@ -0,0 +1,77 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MaxHashtagLength   = 50
+	MaxHashtagsPerText = 30
+)
+
+// ExtractHashtags returns the distinct, lower-cased tags in text in the order
+// they first appear. A tag starts with '#' at the beginning of the text or
+// after a character that cannot be part of a word, so "a#b" and URL
+// fragments are not tags.
+func ExtractHashtags(text string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	prev := ' '
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != '#' || isTagRune(prev) || prev == '#' || prev == '/' {
+			prev = r
+			i += size
+			continue
+		}
+
+		end := i + size
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isTagRune(r) {
+				break
+			}
+			end += size
+		}
+
+		tag := NormalizeHashtag(text[i+size : end])
+		if IsValidHashtag(tag) && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+			if len(tags) == MaxHashtagsPerText {
+				break
+			}
+		}
+		prev = '#'
+		i = end
+	}
+	return tags
+}
+
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// IsValidHashtag expects a normalized tag. It must contain a letter so that
+// "#1" or "#2024" are not indexed as tags.
+func IsValidHashtag(tag string) bool {
+	if tag == "" || utf8.RuneCountInString(tag) > MaxHashtagLength {
+		return false
+	}
+	hasLetter := false
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	return hasLetter
+}
+
+func isTagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	testTable := []struct {
+		title string
+		text  string
+		want  []string
+	}{
+		{"should extract tags", "hello #Go and #golang!", []string{"go", "golang"}},
+		{"should dedup case insensitive", "#Go #go #GO", []string{"go"}},
+		{"should keep underscore and digits", "#go_1_22 rocks", []string{"go_1_22"}},
+		{"should ignore numeric only", "#1 #2024", []string{}},
+		{"should ignore tag inside word", "a#b c#d", []string{}},
+		{"should ignore url fragment", "see https://x.com/page#section", []string{}},
+		{"should ignore double hash", "##go", []string{}},
+		{"should support unicode", "#สวัสดี #café", []string{"สวัสดี", "café"}},
+		{"should stop at punctuation", "(#go), #web.", []string{"go", "web"}},
+		{"should ignore too long", "#" + strings.Repeat("a", MaxHashtagLength+1), []string{}},
+		{"should no tags", "hello", []string{}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			got := ExtractHashtags(v.text)
+			assert.Equalf(t, v.want, got, "Want %v but got %v", v.want, got)
+		})
+	}
+}
+
+func TestExtractHashtags_Limit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < MaxHashtagsPerText+5; i++ {
+		fmt.Fprintf(&b, "#tag%d ", i)
+	}
+	got := ExtractHashtags(b.String())
+	assert.Lenf(t, got, MaxHashtagsPerText, "Want %v tags but got %v", MaxHashtagsPerText, len(got))
+}
+
+func TestIsValidHashtag(t *testing.T) {
+	assert.True(t, IsValidHashtag("golang"))
+	assert.False(t, IsValidHashtag(""))
+	assert.False(t, IsValidHashtag("123"))
+	assert.False(t, IsValidHashtag("go-lang"))
+}
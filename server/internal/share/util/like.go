@@ -0,0 +1,8 @@
+package util
+
+import "strings"
+
+// LikeEscaper escapes the LIKE wildcards in user input, so a search for "_"
+// matches an underscore and not every row. The escape character is the
+// backslash, name it with ESCAPE '\' in the query.
+var LikeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
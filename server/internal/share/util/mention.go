@@ -0,0 +1,69 @@
+package util
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const MaxUsernameLength = 25
+
+type MentionToken struct {
+	Username string
+	// Offset and Length count Unicode code points and cover the leading '@'.
+	Offset int
+	Length int
+}
+
+// ExtractMentions returns every @username in text in order of appearance. A
+// mention starts with '@' at the beginning of the text or after a character
+// that cannot be part of a username, so e-mail addresses are skipped.
+func ExtractMentions(text string) []MentionToken {
+	mentions := []MentionToken{}
+	prev := ' '
+	runeIdx := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != '@' || isUsernameRune(prev) || prev == '@' {
+			prev = r
+			i += size
+			runeIdx++
+			continue
+		}
+
+		end := i + size
+		n := 0
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isUsernameRune(r) {
+				break
+			}
+			end += size
+			n++
+		}
+
+		if n > 0 && n <= MaxUsernameLength {
+			mentions = append(mentions, MentionToken{text[i+size : end], runeIdx, n + 1})
+		}
+		prev = '@'
+		runeIdx += n + 1
+		i = end
+	}
+	return mentions
+}
+
+// IsUsername reports whether s is a username a mention can match: letters,
+// digits and '_', at most MaxUsernameLength code points like the column.
+func IsUsername(s string) bool {
+	n := 0
+	for _, r := range s {
+		if !isUsernameRune(r) {
+			return false
+		}
+		n++
+	}
+	return n > 0 && n <= MaxUsernameLength
+}
+
+func isUsernameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractMentions(t *testing.T) {
+	testTable := []struct {
+		title string
+		text  string
+		want  []MentionToken
+	}{
+		{"should extract mention", "hi @ong!", []MentionToken{{"ong", 3, 4}}},
+		{"should extract many", "@a_1 and @bob", []MentionToken{{"a_1", 0, 4}, {"bob", 9, 4}}},
+		{"should keep repeated", "@ong @ong", []MentionToken{{"ong", 0, 4}, {"ong", 5, 4}}},
+		{"should ignore email", "mail a@b.com", []MentionToken{}},
+		{"should ignore lone at", "meet @ 5pm", []MentionToken{}},
+		{"should ignore double at", "@@ong", []MentionToken{}},
+		{"should count code points", "สวัสดี @ong", []MentionToken{{"ong", 7, 4}}},
+		{"should ignore too long", "@" + strings.Repeat("a", MaxUsernameLength+1), []MentionToken{}},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			got := ExtractMentions(v.text)
+			assert.Equalf(t, v.want, got, "Want %v but got %v", v.want, got)
+		})
+	}
+}
+
+func TestIsUsername(t *testing.T) {
+	testTable := []struct {
+		title    string
+		username string
+		want     bool
+	}{
+		{"should accept letters digits and underscore", "ong_1", true},
+		{"should accept other scripts", "สมชาย", true},
+		{"should accept max length", strings.Repeat("a", MaxUsernameLength), true},
+		{"should reject empty", "", false},
+		{"should reject too many code points", strings.Repeat("a", MaxUsernameLength+1), false},
+		{"should reject combining marks", "e\u0301\u0301", false},
+		{"should reject space", "o ng", false},
+		{"should reject punctuation", "ong.dev", false},
+		{"should reject emoji", "ong\U0001F600", false},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			assert.Equalf(t, v.want, IsUsername(v.username), "Want %v for %q", v.want, v.username)
+		})
+	}
+}
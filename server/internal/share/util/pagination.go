@@ -0,0 +1,42 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidPage = errors.New("invalid limit or offset")
+
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func ParsePage(r *http.Request) (Page, error) {
+	page := Page{Limit: DefaultPageLimit}
+	q := r.URL.Query()
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return Page{}, ErrInvalidPage
+		}
+		page.Limit = min(limit, MaxPageLimit)
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return Page{}, ErrInvalidPage
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
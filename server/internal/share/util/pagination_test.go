@@ -0,0 +1,33 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	testTable := []struct {
+		title   string
+		url     string
+		want    Page
+		wantErr error
+	}{
+		{"should return default page", "/", Page{Limit: DefaultPageLimit}, nil},
+		{"should return limit and offset", "/?limit=5&offset=10", Page{Limit: 5, Offset: 10}, nil},
+		{"should cap limit", "/?limit=1000", Page{Limit: MaxPageLimit}, nil},
+		{"should invalid limit", "/?limit=abc", Page{}, ErrInvalidPage},
+		{"should invalid zero limit", "/?limit=0", Page{}, ErrInvalidPage},
+		{"should invalid negative offset", "/?offset=-1", Page{}, ErrInvalidPage},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, v.url, nil)
+			actual, err := ParsePage(req)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, actual, "want %v but got %v", v.want, actual)
+		})
+	}
+}
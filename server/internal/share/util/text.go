@@ -0,0 +1,115 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxClusterRunes caps the code points in one grapheme cluster. The longest
+// emoji sequences stay well below it, stacked combining marks do not.
+const maxClusterRunes = 16
+
+var (
+	ErrTextEmpty      = errors.New("text is empty")
+	ErrTextTooLong    = errors.New("text is too long")
+	ErrZeroWidthAbuse = errors.New("text contains invisible character abuse")
+)
+
+// TextRule is how user text is cleaned and limited. MaxLength counts
+// grapheme clusters, what a reader sees as one character, and zero means no
+// limit. Without Multiline, line breaks and tabs become spaces.
+type TextRule struct {
+	MaxLength int
+	Multiline bool
+}
+
+// Sanitize normalizes text to NFC, drops control characters and invisible
+// characters that only serve to spoof or pad text, and trims surrounding
+// space. Joiners that emoji and some scripts need are kept, but a run of
+// them, one at either end of the text, or a cluster piled with marks is
+// rejected.
+func (r TextRule) Sanitize(text string) (string, error) {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = norm.NFC.String(text)
+
+	var b strings.Builder
+	b.Grow(len(text))
+	zeroWidthRun := 0
+	for _, c := range text {
+		switch {
+		case c == '\n' || c == '\t' || c == '\r':
+			if !r.Multiline {
+				c = ' '
+			} else if c == '\r' {
+				c = '\n'
+			}
+		case isStripped(c):
+			continue
+		case unicode.IsControl(c):
+			continue
+		}
+
+		if isJoiner(c) {
+			zeroWidthRun++
+			if zeroWidthRun > 1 {
+				return "", ErrZeroWidthAbuse
+			}
+		} else {
+			zeroWidthRun = 0
+		}
+		b.WriteRune(c)
+	}
+
+	text = strings.TrimSpace(b.String())
+	if text == "" {
+		return "", ErrTextEmpty
+	}
+	first, _ := utf8.DecodeRuneInString(text)
+	last, _ := utf8.DecodeLastRuneInString(text)
+	if isJoiner(first) || isJoiner(last) {
+		return "", ErrZeroWidthAbuse
+	}
+
+	count := 0
+	g := uniseg.NewGraphemes(text)
+	for g.Next() {
+		if len(g.Runes()) > maxClusterRunes {
+			return "", ErrZeroWidthAbuse
+		}
+		count++
+	}
+	if r.MaxLength > 0 && count > r.MaxLength {
+		return "", ErrTextTooLong
+	}
+	return text, nil
+}
+
+// isJoiner reports the zero-width characters that legitimately glue
+// characters together, in emoji sequences and in scripts such as Persian.
+func isJoiner(c rune) bool {
+	return c == '\u200C' || c == '\u200D'
+}
+
+// isStripped reports invisible characters that carry no meaning in a post:
+// zero-width spaces, the byte order mark, invisible operators and the
+// bidirectional overrides and isolates used to make text read differently
+// than it is stored.
+func isStripped(c rune) bool {
+	switch {
+	case c == '\u200B', c == '\u2060', c == '\uFEFF', c == '\u180E', c == '\u00AD':
+		return true
+	case c >= '\u2061' && c <= '\u2064':
+		return true
+	case c >= '\u202A' && c <= '\u202E':
+		return true
+	case c >= '\u2066' && c <= '\u2069':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextRuleSanitize(t *testing.T) {
+	testTable := []struct {
+		title   string
+		rule    TextRule
+		text    string
+		want    string
+		wantErr error
+	}{
+		{"should trim space", TextRule{}, "  hello  ", "hello", nil},
+		{"should normalize to NFC", TextRule{}, "cafe\u0301", "caf\u00e9", nil},
+		{"should strip control characters", TextRule{}, "a\x00b\x07c\x1b", "abc", nil},
+		{"should drop invalid utf8", TextRule{}, "a\xffb", "ab", nil},
+		{"should flatten lines when single line", TextRule{}, "a\nb\tc", "a b c", nil},
+		{"should keep lines when multiline", TextRule{Multiline: true}, "a\r\nb\rc", "a\nb\nc", nil},
+		{"should strip invisible characters", TextRule{}, "\u202eabc\u200b\ufeff\u2066d\u2069", "abcd", nil},
+		{"should keep joiner inside script", TextRule{}, "\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645", "\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645", nil},
+		{"should count emoji sequence as one", TextRule{MaxLength: 1}, "\U0001F468\u200d\U0001F469\u200d\U0001F467", "\U0001F468\u200d\U0001F469\u200d\U0001F467", nil},
+		{"should count flag as one", TextRule{MaxLength: 1}, "\U0001F1F9\U0001F1ED", "\U0001F1F9\U0001F1ED", nil},
+		{"should count combined character as one", TextRule{MaxLength: 2}, "e\u0301e\u0300", "\u00e9\u00e8", nil},
+		{"should allow exact max length", TextRule{MaxLength: 3}, "abc", "abc", nil},
+		{"should reject too long", TextRule{MaxLength: 2}, "abc", "", ErrTextTooLong},
+		{"should reject empty", TextRule{}, "", "", ErrTextEmpty},
+		{"should reject only invisible characters", TextRule{}, " \u200b\u2060\u00ad ", "", ErrTextEmpty},
+		{"should reject joiner run", TextRule{}, "a\u200d\u200db", "", ErrZeroWidthAbuse},
+		{"should reject leading joiner", TextRule{}, "\u200dab", "", ErrZeroWidthAbuse},
+		{"should reject trailing joiner", TextRule{}, "ab\u200c", "", ErrZeroWidthAbuse},
+		{"should reject stacked marks", TextRule{}, "a" + strings.Repeat("\u0301", 20), "", ErrZeroWidthAbuse},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			got, err := v.rule.Sanitize(v.text)
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.want, got, "Want %q but got %q", v.want, got)
+		})
+	}
+}
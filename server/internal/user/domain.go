@@ -2,14 +2,25 @@ package user
 
 import "time"
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID        int       `json:"-" db:"id"`
-	UUID      string    `json:"uuid" db:"uuid"`
-	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"-" db:"updated_at"`
+	ID          int        `json:"-" db:"id"`
+	UUID        string     `json:"uuid" db:"uuid"`
+	Username    string     `json:"username" db:"username"`
+	Email       string     `json:"email" db:"email"`
+	Password    string     `json:"-" db:"password"`
+	Role        string     `json:"role,omitempty" db:"role"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty" db:"suspended_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"-" db:"updated_at"`
+}
+
+func (u User) IsSuspended() bool {
+	return u.SuspendedAt != nil
 }
 
 type UserCreated struct {
@@ -22,5 +33,5 @@ type UserCreated struct {
 type UserResponse struct {
 	UUID     string `json:"uuid" db:"uuid"`
 	Username string `json:"username" db:"username"`
-	Email    string `json:"email" db:"email"`
+	Email    string `json:"email,omitempty" db:"email"`
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/gorilla/mux"
@@ -13,6 +14,7 @@ type IUserService interface {
 	GetUserByUUID(string) (User, error)
 	CreateUser(UserCreated) (int64, error)
 	GetUserByUsername(string) (User, error)
+	Autocomplete(q, viewerUUID string, limit int) ([]UserResponse, error)
 }
 
 type UserHandler struct {
@@ -90,3 +92,29 @@ func (h *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request)
 
 	util.SendJson(w, userRes, http.StatusOK)
 }
+
+func (h *UserHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	errInvalidRes := util.BuildErrResponse("invalid request")
+	var limit int
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			util.SendJson(w, errInvalidRes(errors.New("invalid limit")), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	viewerUUID, _ := r.Context().Value("userUUID").(string)
+	users, err := h.userSrv.Autocomplete(r.URL.Query().Get("q"), viewerUUID, limit)
+	if err != nil {
+		if err == ErrInvalidQuery {
+			util.SendJson(w, errInvalidRes(err), http.StatusBadRequest)
+			return
+		}
+		util.SendJson(w, util.BuildErrResponse("service failure")(err), http.StatusInternalServerError)
+		return
+	}
+
+	util.SendJson(w, users, http.StatusOK)
+}
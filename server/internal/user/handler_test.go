@@ -32,6 +32,10 @@ func (m *MockUserService) GetUserByUsername(string) (User, error) {
 	return m.u, m.err
 }
 
+func (m *MockUserService) Autocomplete(string, string, int) ([]UserResponse, error) {
+	return []UserResponse{{UUID: m.u.UUID, Username: m.u.Username}}, m.err
+}
+
 func TestHandlerGetUserByUUID(t *testing.T) {
 	passQuery, _ := util.GeneratePassword("wow")
 	userQuery := User{
@@ -280,3 +284,30 @@ func TestHandlerGetUserByUsername_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerAutocomplete(t *testing.T) {
+	testTable := []struct {
+		title      string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{"should return suggestions", "?q=on", nil, http.StatusOK},
+		{"should bad request cause invalid limit", "?q=on&limit=abc", nil, http.StatusBadRequest},
+		{"should bad request cause invalid query", "?q=", ErrInvalidQuery, http.StatusBadRequest},
+		{"should service failure", "?q=on", errors.New("db err"), http.StatusInternalServerError},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mService := MockUserService{User{UUID: "98da0985-1b0c-47f5-95c5-ca63b5c4df35", Username: "ong"}, v.serviceErr}
+			handler := NewUserHandler(&mService)
+
+			req := httptest.NewRequest(http.MethodGet, "/user/autocomplete"+v.query, nil)
+			rec := httptest.NewRecorder()
+			handler.Autocomplete(rec, req)
+
+			assert.Equalf(t, v.wantStatus, rec.Code, "want %v but got %v", v.wantStatus, rec.Code)
+		})
+	}
+}
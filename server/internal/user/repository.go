@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/dsypasit/social-clone/server/internal/share/util"
 	_ "github.com/lib/pq"
 )
 
@@ -39,8 +40,8 @@ func (ur *UserRepository) GetUserByUsername(username string) (User, error) {
 
 func (ur *UserRepository) GetUserByUUID(username string) (User, error) {
 	var u User
-	err := ur.db.QueryRow("SELECT id, uuid, username, email, updated_at FROM app_user WHERE uuid = $1 AND delete_at is NULL", username).
-		Scan(&u.ID, &u.UUID, &u.Username, &u.Email, &u.CreatedAt)
+	err := ur.db.QueryRow("SELECT id, uuid, username, email, role, suspended_at, updated_at FROM app_user WHERE uuid = $1 AND delete_at is NULL", username).
+		Scan(&u.ID, &u.UUID, &u.Username, &u.Email, &u.Role, &u.SuspendedAt, &u.CreatedAt)
 	if err != nil && err != sql.ErrNoRows {
 		return User{}, err
 	}
@@ -102,3 +103,39 @@ func (ur *UserRepository) UpdateEmailByUUID(uuid string, email string) error {
 
 	return nil
 }
+
+// Autocomplete matches usernames by prefix first and falls back to trigram
+// similarity for typos. Accounts the viewer follows are ranked first, and
+// blocked, suspended or deleted accounts are never suggested.
+func (ur *UserRepository) Autocomplete(q, viewerUUID string, limit int) ([]UserResponse, error) {
+	query := `
+  SELECT u.uuid, u.username
+  FROM app_user AS u
+  LEFT JOIN follows AS f
+    ON f.followed_id = u.id AND f.follower_id = (SELECT id FROM app_user WHERE uuid = $1)
+  WHERE (u.username ILIKE $2 ESCAPE '\' OR u.username % $3)
+  AND u.delete_at IS NULL AND u.suspended_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks AS b
+    WHERE (b.blocker_id = u.id AND b.blocked_id = (SELECT id FROM app_user WHERE uuid = $1))
+    OR (b.blocked_id = u.id AND b.blocker_id = (SELECT id FROM app_user WHERE uuid = $1))
+  )
+  ORDER BY f.id IS NOT NULL DESC, u.username ILIKE $2 DESC, similarity(u.username, $3) DESC, length(u.username), u.id
+  LIMIT $4`
+
+	rows, err := ur.db.Query(query, viewerUUID, util.LikeEscaper.Replace(q)+"%", q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []UserResponse{}
+	for rows.Next() {
+		var u UserResponse
+		if err := rows.Scan(&u.UUID, &u.Username); err != nil {
+			return users, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
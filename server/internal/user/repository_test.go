@@ -94,18 +94,33 @@ func TestGetUserByUUID(t *testing.T) {
 	}{
 		{
 			"should return user", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81",
-			*sqlmock.NewRows([]string{"id", "uuid", "username", "email", "created_at"}).AddRow("1", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81", "ong", "a@gmail.com", CreatedAt),
+			*sqlmock.NewRows([]string{"id", "uuid", "username", "email", "role", "suspended_at", "created_at"}).AddRow("1", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81", "ong", "a@gmail.com", "user", nil, CreatedAt),
 			User{
 				ID:        1,
 				UUID:      "0870a9ce-78d2-463d-bd88-ad0a0eee0e81",
 				Username:  "ong",
 				Email:     "a@gmail.com",
+				Role:      RoleUser,
 				CreatedAt: CreatedAt,
 			},
 			nil,
 		},
 		{
-			"should not found", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81", *sqlmock.NewRows([]string{"id", "uuid", "username", "email", "created_at"}),
+			"should return suspended user", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81",
+			*sqlmock.NewRows([]string{"id", "uuid", "username", "email", "role", "suspended_at", "created_at"}).AddRow("1", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81", "ong", "a@gmail.com", "user", CreatedAt, CreatedAt),
+			User{
+				ID:          1,
+				UUID:        "0870a9ce-78d2-463d-bd88-ad0a0eee0e81",
+				Username:    "ong",
+				Email:       "a@gmail.com",
+				Role:        RoleUser,
+				SuspendedAt: &CreatedAt,
+				CreatedAt:   CreatedAt,
+			},
+			nil,
+		},
+		{
+			"should not found", "0870a9ce-78d2-463d-bd88-ad0a0eee0e81", *sqlmock.NewRows([]string{"id", "uuid", "username", "email", "role", "suspended_at", "created_at"}),
 			User{},
 			ErrUserNotFound,
 		},
@@ -294,3 +309,20 @@ func TestUpdateEmailByUUID_UserNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestAutocomplete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nilf(t, err, "Expected nil from created sqlmock")
+	defer db.Close()
+
+	mock.ExpectQuery("similarity").
+		WithArgs("e45680fb-29e3-4679-ab45-a95c7d9a18f4", `o\_n%`, "o_n", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"uuid", "username"}).
+			AddRow("98da0985-1b0c-47f5-95c5-ca63b5c4df35", "o_ng"))
+
+	userRepo := NewUserRepository(db)
+	actual, err := userRepo.Autocomplete("o_n", "e45680fb-29e3-4679-ab45-a95c7d9a18f4", 10)
+	want := []UserResponse{{UUID: "98da0985-1b0c-47f5-95c5-ca63b5c4df35", Username: "o_ng"}}
+	assert.Nilf(t, err, "Unexpected error: %v", err)
+	assert.Equalf(t, want, actual, "Expected %v but got %v", want, actual)
+}
@@ -8,9 +8,11 @@ import (
 
 type IUserHandler interface {
 	GetUserByUsername(w http.ResponseWriter, r *http.Request)
+	Autocomplete(w http.ResponseWriter, r *http.Request)
 }
 
-func RegisterUserRouter(router *mux.Router, userHandler IUserHandler) {
+func RegisterUserRouter(router *mux.Router, userHandler IUserHandler, authMiddleware mux.MiddlewareFunc) {
 	s := router.PathPrefix("/user").Subrouter()
 	s.HandleFunc("", userHandler.GetUserByUsername)
+	s.Handle("/autocomplete", authMiddleware(http.HandlerFunc(userHandler.Autocomplete))).Methods(http.MethodGet)
 }
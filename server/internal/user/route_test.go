@@ -11,17 +11,34 @@ import (
 
 type MockHandler struct {
 	getUserByUsernameCalled bool
+	autocompleteCalled      bool
 }
 
 func (m *MockHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request) {
 	m.getUserByUsernameCalled = true
 }
 
+func (m *MockHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	m.autocompleteCalled = true
+}
+
 func TestRoute(t *testing.T) {
-	mhandler := MockHandler{false}
+	mhandler := MockHandler{}
+	authCalled := false
+	authMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authCalled = true
+			next.ServeHTTP(w, r)
+		})
+	}
 	router := mux.NewRouter()
-	RegisterUserRouter(router, &mhandler)
+	RegisterUserRouter(router, &mhandler, authMiddleware)
 
 	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user", nil))
 	assert.True(t, mhandler.getUserByUsernameCalled, "get user by username not called")
+	assert.False(t, authCalled, "get user by username should not require auth")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/autocomplete?q=on", nil))
+	assert.True(t, mhandler.autocompleteCalled, "autocomplete not called")
+	assert.True(t, authCalled, "autocomplete should require auth")
 }
@@ -3,17 +3,35 @@ package user
 import (
 	"database/sql"
 	"errors"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/google/uuid"
 )
 
+const (
+	DefaultAutocompleteLimit = 10
+	MaxAutocompleteLimit     = 20
+)
+
+var (
+	ErrInvalidQuery    = errors.New("invalid query")
+	ErrInvalidUsername = errors.New("invalid username")
+)
+
+// UsernameRule is how usernames are sanitized, at signup and wherever a
+// typed username is looked up. CreateUser also checks the result with
+// util.IsUsername so every stored name fits the column and can be mentioned.
+var UsernameRule = util.TextRule{MaxLength: util.MaxUsernameLength}
+
 type IUserRepository interface {
 	GetUserByUUID(string) (User, error)
 	GetPasswordByUsername(string) (string, error)
 	CreateUser(UserCreated) (int64, error)
 	GetUserUUIDByUsername(string) (string, error)
 	GetUserByUsername(string) (User, error)
+	Autocomplete(q, viewerUUID string, limit int) ([]UserResponse, error)
 }
 
 type UserService struct {
@@ -37,6 +55,13 @@ func (us *UserService) CreateUser(newUser UserCreated) (int64, error) {
 	if !util.IsValidEmail(newUser.Email) {
 		return 0, errors.New("invalid email")
 	}
+	newUser.Username, err = UsernameRule.Sanitize(newUser.Username)
+	if err != nil {
+		return 0, err
+	}
+	if !util.IsUsername(newUser.Username) {
+		return 0, ErrInvalidUsername
+	}
 	newUser.UUID = uuid.New().String()
 	newUser.Password, err = util.GeneratePassword(newUser.Password)
 	if err != nil {
@@ -59,3 +84,17 @@ func (us *UserService) GetUserByUsername(username string) (User, error) {
 	}
 	return user, nil
 }
+
+func (us *UserService) Autocomplete(q, viewerUUID string, limit int) ([]UserResponse, error) {
+	q = strings.TrimPrefix(strings.TrimSpace(q), "@")
+	if q == "" || utf8.RuneCountInString(q) > util.MaxUsernameLength {
+		return nil, ErrInvalidQuery
+	}
+	if limit <= 0 {
+		limit = DefaultAutocompleteLimit
+	}
+	if limit > MaxAutocompleteLimit {
+		limit = MaxAutocompleteLimit
+	}
+	return us.userRepo.Autocomplete(q, viewerUUID, limit)
+}
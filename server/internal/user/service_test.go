@@ -2,9 +2,11 @@ package user
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/dsypasit/social-clone/server/internal/share/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,6 +35,10 @@ func (m *MockUserRepo) GetUserByUsername(uname string) (User, error) {
 	return m.u, m.err
 }
 
+func (m *MockUserRepo) Autocomplete(q, viewerUUID string, limit int) ([]UserResponse, error) {
+	return []UserResponse{{UUID: m.u.UUID, Username: m.u.Username}}, m.err
+}
+
 func TestServiceGetUserByUUID(t *testing.T) {
 	want := User{
 		ID:        1,
@@ -105,6 +111,30 @@ func TestServiceCreateUser(t *testing.T) {
 			1,
 			nil,
 		},
+		{
+			"should reject blank username",
+			UserCreated{"35707a9d-a346-4cd4-ba0c-1dfd0b9ba96e", "\u200b \u202e", "a@gmail.com", "asldkfjasjkdlf"},
+			0,
+			util.ErrTextEmpty,
+		},
+		{
+			"should reject too long username",
+			UserCreated{"35707a9d-a346-4cd4-ba0c-1dfd0b9ba96e", strings.Repeat("a", 26), "a@gmail.com", "asldkfjasjkdlf"},
+			0,
+			util.ErrTextTooLong,
+		},
+		{
+			"should reject username over the column code points",
+			UserCreated{"35707a9d-a346-4cd4-ba0c-1dfd0b9ba96e", strings.Repeat("\u1113\u1161", 13), "a@gmail.com", "asldkfjasjkdlf"},
+			0,
+			ErrInvalidUsername,
+		},
+		{
+			"should reject username mentions cannot match",
+			UserCreated{"35707a9d-a346-4cd4-ba0c-1dfd0b9ba96e", "ong.dev", "a@gmail.com", "asldkfjasjkdlf"},
+			0,
+			ErrInvalidUsername,
+		},
 	}
 
 	for _, v := range testTable {
@@ -164,3 +194,43 @@ func TestServiceGetUserByUsername(t *testing.T) {
 		})
 	}
 }
+
+type autocompleteRepo struct {
+	MockUserRepo
+	gotQuery string
+	gotLimit int
+}
+
+func (m *autocompleteRepo) Autocomplete(q, viewerUUID string, limit int) ([]UserResponse, error) {
+	m.gotQuery, m.gotLimit = q, limit
+	return []UserResponse{}, nil
+}
+
+func TestServiceAutocomplete(t *testing.T) {
+	testTable := []struct {
+		title     string
+		q         string
+		limit     int
+		wantQuery string
+		wantLimit int
+		wantErr   error
+	}{
+		{"should use default limit", "ong", 0, "ong", DefaultAutocompleteLimit, nil},
+		{"should cap limit", "ong", 500, "ong", MaxAutocompleteLimit, nil},
+		{"should strip mention prefix", " @ong ", 5, "ong", 5, nil},
+		{"should reject empty query", "@", 5, "", 0, ErrInvalidQuery},
+		{"should reject long query", "abcdefghijklmnopqrstuvwxyz", 5, "", 0, ErrInvalidQuery},
+	}
+
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			mRepo := autocompleteRepo{}
+			userService := NewUserService(&mRepo)
+			_, err := userService.Autocomplete(v.q, "", v.limit)
+
+			assert.Equalf(t, v.wantErr, err, "Unexpected error: %v", err)
+			assert.Equalf(t, v.wantQuery, mRepo.gotQuery, "want %v but got %v", v.wantQuery, mRepo.gotQuery)
+			assert.Equalf(t, v.wantLimit, mRepo.gotLimit, "want %v but got %v", v.wantLimit, mRepo.gotLimit)
+		})
+	}
+}
@@ -1,20 +1,169 @@
 package pkg
 
 import (
-	"log"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
 )
 
-func CorsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Origin, content-type, X-Auth-Token, Authorization")
-		if r.Method == http.MethodOptions {
-			log.Println("option kub")
+// CorsOptions is the cross origin policy. An origin is matched exactly, "*"
+// allows any origin and "https://*.example.com" any subdomain of one.
+// A "*" origin never gets credentials, browsers refuse the combination.
+type CorsOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAgeSeconds    int
+}
+
+type cors struct {
+	opts    CorsOptions
+	router  *mux.Router
+	anyOrig bool
+}
+
+// CorsMiddleware serves router behind the policy. Preflights are answered
+// here, allowing only the methods the requested route has.
+func CorsMiddleware(opts CorsOptions, router *mux.Router) http.Handler {
+	c := &cors{opts: opts, router: router}
+	c.opts.AllowedOrigins = make([]string, len(opts.AllowedOrigins))
+	for i, o := range opts.AllowedOrigins {
+		c.opts.AllowedOrigins[i] = strings.ToLower(strings.TrimSuffix(o, "/"))
+		c.anyOrig = c.anyOrig || o == "*"
+	}
+	return c
+}
+
+func (c *cors) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		c.router.ServeHTTP(w, r)
+		return
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		c.preflight(w, r, origin)
+		return
+	}
+
+	if c.allowOrigin(w, origin) && len(c.opts.ExposedHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(c.opts.ExposedHeaders, ", "))
+	}
+	c.router.ServeHTTP(w, r)
+}
+
+// preflight answers 204 either way, a rejected preflight simply carries no
+// CORS headers and the browser blocks the request.
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	h := w.Header()
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	methods := c.routeMethods(r)
+	if !contains(methods, r.Header.Get("Access-Control-Request-Method")) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	headers := requestedHeaders(r)
+	for _, header := range headers {
+		if !containsFold(c.opts.AllowedHeaders, header) {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
-		next.ServeHTTP(w, r)
-	})
+	}
+	if !c.allowOrigin(w, origin) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(headers) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if c.opts.MaxAgeSeconds > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(c.opts.MaxAgeSeconds))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowOrigin sets the origin headers when origin is allowed.
+func (c *cors) allowOrigin(w http.ResponseWriter, origin string) bool {
+	h := w.Header()
+	if c.matchOrigin(origin) {
+		h.Set("Access-Control-Allow-Origin", origin)
+		if c.opts.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		return true
+	}
+	if c.anyOrig {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+	return false
+}
+
+func (c *cors) matchOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, o := range c.opts.AllowedOrigins {
+		if o == origin {
+			return true
+		}
+		scheme, host, ok := strings.Cut(o, "://*.")
+		if ok && strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeMethods lists the allowed methods the route at the request path
+// serves.
+func (c *cors) routeMethods(r *http.Request) []string {
+	var methods []string
+	for _, m := range c.opts.AllowedMethods {
+		req := r.Clone(r.Context())
+		req.Method = m
+		var match mux.RouteMatch
+		if c.router.Match(req, &match) && match.MatchErr == nil {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+func requestedHeaders(r *http.Request) []string {
+	var headers []string
+	for _, v := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(v, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+	}
+	return headers
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCorsOptions = CorsOptions{
+	AllowedOrigins:   []string{"http://localhost:3000", "https://*.example.com"},
+	AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+	AllowedHeaders:   []string{"Content-Type", "Authorization"},
+	ExposedHeaders:   []string{"Retry-After"},
+	AllowCredentials: true,
+	MaxAgeSeconds:    600,
+}
+
+func newTestCorsRouter() *mux.Router {
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/post", ok).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc("/post/{uuid}", ok).Methods(http.MethodGet, http.MethodPut)
+	return router
+}
+
+func TestCorsMiddleware(t *testing.T) {
+	testTable := []struct {
+		title           string
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"should reflect allowed origin", "http://localhost:3000", "http://localhost:3000", "true"},
+		{"should reflect subdomain origin", "https://app.example.com", "https://app.example.com", "true"},
+		{"should skip bare wildcard domain", "https://example.com", "", ""},
+		{"should skip other scheme", "http://app.example.com", "", ""},
+		{"should skip unknown origin", "https://evil.com", "", ""},
+	}
+
+	handler := CorsMiddleware(testCorsOptions, newTestCorsRouter())
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/post", nil)
+			req.Header.Set("Origin", v.origin)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, v.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, v.wantCredentials, rec.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, []string{"Origin"}, rec.Header().Values("Vary"))
+		})
+	}
+}
+
+func TestCorsMiddleware_NoOrigin(t *testing.T) {
+	handler := CorsMiddleware(testCorsOptions, newTestCorsRouter())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/post", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Vary"))
+}
+
+func TestCorsMiddleware_Wildcard(t *testing.T) {
+	opts := testCorsOptions
+	opts.AllowedOrigins = []string{"*"}
+	handler := CorsMiddleware(opts, newTestCorsRouter())
+
+	req := httptest.NewRequest(http.MethodGet, "/post", nil)
+	req.Header.Set("Origin", "https://any.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Retry-After", rec.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestCorsMiddleware_Preflight(t *testing.T) {
+	testTable := []struct {
+		title       string
+		origin      string
+		path        string
+		method      string
+		headers     string
+		wantOrigin  string
+		wantMethods string
+		wantHeaders string
+	}{
+		{"should allow route methods", "http://localhost:3000", "/post", http.MethodPost, "content-type, authorization", "http://localhost:3000", "GET, POST", "content-type, authorization"},
+		{"should list methods per route", "http://localhost:3000", "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", http.MethodGet, "", "http://localhost:3000", "GET", ""},
+		{"should reject method not configured", "http://localhost:3000", "/post/f307d2db-d2ea-4ec9-8d31-27b7443d7c72", http.MethodPut, "", "", "", ""},
+		{"should reject method of other route", "http://localhost:3000", "/post", http.MethodDelete, "", "", "", ""},
+		{"should reject unknown route", "http://localhost:3000", "/missing", http.MethodGet, "", "", "", ""},
+		{"should reject unknown header", "http://localhost:3000", "/post", http.MethodPost, "X-Debug", "", "", ""},
+		{"should reject unknown origin", "https://evil.com", "/post", http.MethodPost, "", "", "", ""},
+	}
+
+	handler := CorsMiddleware(testCorsOptions, newTestCorsRouter())
+	for _, v := range testTable {
+		t.Run(v.title, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, v.path, nil)
+			req.Header.Set("Origin", v.origin)
+			req.Header.Set("Access-Control-Request-Method", v.method)
+			if v.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", v.headers)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, v.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, v.wantMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, v.wantHeaders, rec.Header().Get("Access-Control-Allow-Headers"))
+			assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, rec.Header().Values("Vary"))
+			if v.wantOrigin != "" {
+				assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+				assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+			}
+		})
+	}
+}
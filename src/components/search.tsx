@@ -1,38 +1,39 @@
-import React, {
-  ChangeEvent,
-  ChangeEventHandler,
-  ReactEventHandler,
-  useState,
-} from "react";
+import React, { ChangeEvent, useEffect, useState } from "react";
 import { Input } from "./ui/input";
 import { Separator } from "./ui/separator";
-
-interface Item {
-  // Define the type of your list items here (optional)
-  name: string; // Example property
-}
-
-const MockValue: Item[] = [
-  { name: "aser" },
-  { name: "ajajaj" },
-  { name: "jkjkjkj" }, // Use objects if your items have properties
-];
+import {
+  IUserResult,
+  autocompleteUsersService,
+} from "@/lib/services/searchService";
 
 export function Search() {
   const [IsFocused, setIsFocused] = useState(false);
   const [filterValue, setFilterValue] = useState(""); // State for user input
+  const [items, setItems] = useState<IUserResult[]>([]);
 
-  const filteredItems = React.useMemo(() => {
-    if (!filterValue) {
-      return MockValue; // Return all items if no filter value
+  useEffect(() => {
+    const q = filterValue.trim();
+    if (!q) {
+      setItems([]);
+      return;
     }
 
-    const lowerCaseValue = filterValue.toLowerCase();
+    // debounce so we do not hit the API on every keystroke
+    let cancelled = false;
+    const timer = setTimeout(async () => {
+      try {
+        const users = await autocompleteUsersService(q);
+        if (!cancelled) setItems(users);
+      } catch (error) {
+        console.error("Error searching users:", error);
+      }
+    }, 250);
 
-    return MockValue.filter(
-      (item) => item.name.toLowerCase().includes(lowerCaseValue), // Filter by name property
-    );
-  }, [filterValue, MockValue]);
+    return () => {
+      cancelled = true;
+      clearTimeout(timer);
+    };
+  }, [filterValue]);
 
   const handleOnFocus = () => {
     setIsFocused(true);
@@ -55,17 +56,15 @@ export function Search() {
         onChange={handleOnChange}
         placeholder="search friends..."
       />
-      {IsFocused && (
+      {IsFocused && items.length > 0 && (
         <div className="mt-2 h-full w-full border-solid rounded-md border-2">
-          {filteredItems.map((item, n) => (
-            <>
+          {items.map((item, n) => (
+            <React.Fragment key={item.uuid}>
               <div className="h-20 p-5 flex items-center hover:bg-gray-200">
-                {item.name}
+                {item.username}
               </div>
-              {n + 1 < filteredItems.length && (
-                <Separator className="w-11/12 m-auto" />
-              )}
-            </>
+              {n + 1 < items.length && <Separator className="w-11/12 m-auto" />}
+            </React.Fragment>
           ))}
         </div>
       )}
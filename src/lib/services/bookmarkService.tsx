@@ -0,0 +1,37 @@
+import apiClient from "../apiClient";
+import { IBookmarkCollection, IPost } from "@/types/types";
+
+export interface IBookmarkPage {
+  posts: IPost[];
+  next_cursor?: string; // pass back as cursor, missing on the last page
+}
+
+export const getBookmarksService = async (collection?: string, cursor?: string, limit = 20) => {
+  const response = await apiClient.get<IBookmarkPage>("/bookmarks", {
+    params: { collection, cursor, limit },
+  });
+  return response.data;
+};
+
+// bookmarking an already saved post moves it to the given collection
+export const addBookmarkService = async (postUUID: string, collectionUUID?: string) => {
+  await apiClient.post(`/bookmarks/${postUUID}`, collectionUUID ? { collection_uuid: collectionUUID } : undefined);
+};
+
+export const removeBookmarkService = async (postUUID: string) => {
+  await apiClient.delete(`/bookmarks/${postUUID}`);
+};
+
+export const getCollectionsService = async () => {
+  const response = await apiClient.get<IBookmarkCollection[]>("/bookmarks/collections");
+  return response.data;
+};
+
+export const createCollectionService = async (name: string) => {
+  const response = await apiClient.post<{ uuid: string }>("/bookmarks/collections", { name });
+  return response.data.uuid;
+};
+
+export const deleteCollectionService = async (uuid: string) => {
+  await apiClient.delete(`/bookmarks/collections/${uuid}`);
+};
@@ -0,0 +1,66 @@
+import apiClient from "../apiClient";
+
+export interface IConversationMember {
+  uuid: string;
+  username: string;
+  last_read_message_uuid: string | null;
+  last_read_at: string | null;
+}
+
+export interface IMessage {
+  uuid: string;
+  conversation_uuid: string;
+  sender_uuid: string;
+  sender_username: string;
+  content: string;
+  created_at: string;
+}
+
+export interface IConversation {
+  uuid: string;
+  is_group: boolean;
+  title: string;
+  members: IConversationMember[];
+  last_message: IMessage | null;
+  unread_count: number;
+  updated_at: string;
+}
+
+export const createConversationService = async (memberUUIDs: string[], title = "") => {
+  const response = await apiClient.post<{ uuid: string }>("/conversations", {
+    member_uuids: memberUUIDs,
+    title,
+  });
+  return response.data.uuid;
+};
+
+export const getConversationsService = async (limit = 20, offset = 0) => {
+  const response = await apiClient.get<IConversation[]>("/conversations", {
+    params: { limit, offset },
+  });
+  return response.data;
+};
+
+// Pass `before` to page back through history (newest first) or `after` to
+// poll for new messages (oldest first) when the stream is unavailable.
+export const getMessagesService = async (
+  conversationUUID: string,
+  cursor: { before?: string; after?: string } = {},
+  limit = 50
+) => {
+  const response = await apiClient.get<IMessage[]>(`/conversations/${conversationUUID}/messages`, {
+    params: { ...cursor, limit },
+  });
+  return response.data;
+};
+
+export const sendMessageService = async (conversationUUID: string, content: string) => {
+  const response = await apiClient.post<{ uuid: string }>(`/conversations/${conversationUUID}/messages`, {
+    content,
+  });
+  return response.data.uuid;
+};
+
+export const markConversationReadService = async (conversationUUID: string, messageUUID?: string) => {
+  await apiClient.post(`/conversations/${conversationUUID}/read`, messageUUID ? { message_uuid: messageUUID } : undefined);
+};
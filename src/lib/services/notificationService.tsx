@@ -0,0 +1,27 @@
+import apiClient from "../apiClient";
+import { INotification } from "@/types/types";
+
+export interface INotificationList {
+  notifications: INotification[];
+  unread_count: number;
+}
+
+export const getNotificationsService = async (limit = 20, offset = 0, unread = false) => {
+  const response = await apiClient.get<INotificationList>("/notifications", {
+    params: { limit, offset, unread },
+  });
+  return response.data;
+};
+
+export const getUnreadCountService = async () => {
+  const response = await apiClient.get<{ unread_count: number }>("/notifications/unread-count");
+  return response.data.unread_count;
+};
+
+export const markNotificationReadService = async (uuid: string) => {
+  await apiClient.post(`/notifications/${uuid}/read`);
+};
+
+export const markAllNotificationsReadService = async () => {
+  await apiClient.post("/notifications/read-all");
+};
@@ -0,0 +1,18 @@
+import apiClient from "../apiClient";
+import { IReaction, ReactionTarget } from "@/types/types";
+
+export const getReactionTypesService = async () => {
+  const response = await apiClient.get<{ types: string[] }>("/reaction/types");
+  return response.data.types;
+};
+
+// reacting again with another type switches the reaction
+export const reactService = async (target: ReactionTarget, uuid: string, type: string) => {
+  const response = await apiClient.put<IReaction>(`/reaction/${target}/${uuid}`, { type });
+  return response.data;
+};
+
+export const unreactService = async (target: ReactionTarget, uuid: string) => {
+  const response = await apiClient.delete<IReaction>(`/reaction/${target}/${uuid}`);
+  return response.data;
+};
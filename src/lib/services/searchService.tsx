@@ -0,0 +1,20 @@
+import apiClient from "../apiClient";
+
+export interface IUserResult {
+  uuid: string;
+  username: string;
+}
+
+export const searchUsersService = async (q: string, limit = 10) => {
+  const response = await apiClient.get<IUserResult[]>("/search", {
+    params: { q, type: "users", limit },
+  });
+  return response.data;
+};
+
+export const autocompleteUsersService = async (q: string, limit = 10) => {
+  const response = await apiClient.get<IUserResult[]>("/user/autocomplete", {
+    params: { q, limit },
+  });
+  return response.data;
+};
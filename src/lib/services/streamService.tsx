@@ -0,0 +1,57 @@
+import Cookies from "js-cookie";
+import apiClient from "../apiClient";
+
+export type StreamEventType = "feed" | "notification" | "message" | "message_read";
+
+export interface IStreamEvent {
+  type: StreamEventType;
+  data: any;
+}
+
+// EventSource cannot send the Authorization header, so the stream is read
+// with fetch and parsed by hand. Returns a function that closes the stream.
+export const subscribeStreamService = (
+  onEvent: (e: IStreamEvent) => void,
+  onClose?: () => void
+) => {
+  const controller = new AbortController();
+  const token = Cookies.get("token");
+
+  (async () => {
+    try {
+      const response = await fetch(`${apiClient.defaults.baseURL}/stream`, {
+        headers: token ? { Authorization: `Bearer ${token}` } : {},
+        signal: controller.signal,
+      });
+      if (!response.ok || !response.body) return;
+
+      const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+      let buffer = "";
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) break;
+        buffer += value;
+
+        let end;
+        while ((end = buffer.indexOf("\n\n")) >= 0) {
+          const block = buffer.slice(0, end);
+          buffer = buffer.slice(end + 2);
+
+          let type = "";
+          let data = "";
+          for (const line of block.split("\n")) {
+            if (line.startsWith("event: ")) type = line.slice(7);
+            else if (line.startsWith("data: ")) data += line.slice(6);
+          }
+          if (type && data) onEvent({ type: type as StreamEventType, data: JSON.parse(data) });
+        }
+      }
+    } catch {
+      // aborted or network error, the caller decides whether to reconnect
+    } finally {
+      onClose?.();
+    }
+  })();
+
+  return () => controller.abort();
+};
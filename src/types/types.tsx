@@ -1,3 +1,10 @@
+export interface IMention {
+  user_uuid: string;
+  username: string;
+  offset: number; // code point offset into content, includes the "@"
+  length: number;
+}
+
 export interface IPost {
   uuid: string;
   content: string;
@@ -5,4 +12,71 @@ export interface IPost {
   user_uuid: string;
   visibility_type_id: number;
   update_at: string; // Assuming update_at is a string representation of a date
+  mentions?: IMention[];
+  is_repost: boolean;
+  is_quote: boolean;
+  num_repost: number;
+  num_quote: number;
+  reposted_by_me: boolean;
+  original?: IPost; // missing when the original is gone or not visible
+  reactions: Record<string, number>;
+  my_reaction: string | null;
+  bookmarked_by_me: boolean;
+  edited: boolean;
+  edited_at?: string;
+  poll?: IPoll;
+  link_preview?: ILinkPreview;
+}
+
+export interface ILinkPreview {
+  url: string;
+  title: string;
+  description?: string;
+  image_url?: string;
+  site_name?: string;
+  fetched_at: string;
+}
+
+// votes and total_votes are only sent once the viewer voted or the poll closed
+export interface IPoll {
+  options: { position: number; text: string; votes?: number }[];
+  closes_at: string;
+  closed: boolean;
+  my_vote: number | null;
+  total_votes?: number;
+}
+
+export interface IPostRevision {
+  content: string;
+  created_at: string;
+}
+
+export interface IBookmarkCollection {
+  uuid: string;
+  name: string;
+  num_bookmarks: number;
+  created_at: string;
+}
+
+export type ReactionTarget = "post" | "comment";
+
+export interface IReaction {
+  target_type: ReactionTarget;
+  target_uuid: string;
+  reactions: Record<string, number>;
+  my_reaction: string | null;
+}
+
+export type NotificationType = "follow" | "like" | "comment" | "mention";
+
+export interface INotification {
+  uuid: string;
+  type: NotificationType;
+  target_type: "user" | "post" | "comment";
+  target_uuid: string;
+  actors: { uuid: string; username: string }[];
+  actor_count: number;
+  unread: boolean;
+  message: string;
+  latest_at: string;
 }